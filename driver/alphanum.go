@@ -0,0 +1,139 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+/*
+Alphanum is the driver representation of a database ALPHANUM field value.
+
+The database transfers purely numeric ALPHANUM values dependent on the data format version:
+- data format version 1 (ALPHANUM is transferred like VARCHAR): padded with leading zeros to the field size
+- data format version > 1: without leading zeros
+
+Alphanum values are scanned as transferred. To scan the stored form (padded with leading zeros)
+independent of the data format version, the query needs to be executed with a context returned
+by WithAlphanumPadding. The value without leading zeros can be obtained via Trimmed.
+
+Alphanum values are bound as parameter unchanged, so the database applies its ALPHANUM semantics
+(purely numeric values are compared numerically, e.g. '007' equals '7').
+*/
+type Alphanum string
+
+// Scan implements the database/sql/Scanner interface.
+func (a *Alphanum) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case []byte:
+		*a = Alphanum(src)
+	case string:
+		*a = Alphanum(src)
+	default:
+		return fmt.Errorf("alphanum: invalid data type %T", src)
+	}
+	return nil
+}
+
+// Value implements the database/sql/Valuer interface.
+func (a Alphanum) Value() (driver.Value, error) {
+	return string(a), nil
+}
+
+// IsNumeric returns true if the value consists of digits only (purely numeric ALPHANUM value).
+func (a Alphanum) IsNumeric() bool {
+	if len(a) == 0 {
+		return false
+	}
+	for i := 0; i < len(a); i++ {
+		if a[i] < '0' || a[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Trimmed returns purely numeric values without leading zeros ('0' for a zero value).
+// All other values are returned unchanged.
+func (a Alphanum) Trimmed() string {
+	if !a.IsNumeric() {
+		return string(a)
+	}
+	if s := strings.TrimLeft(string(a), "0"); s != "" {
+		return s
+	}
+	return "0"
+}
+
+type alphanumPaddingCtxKey struct{}
+
+/*
+WithAlphanumPadding returns a copy of the context which makes queries executed with the context return
+purely numeric ALPHANUM values in the stored form, padded with leading zeros to the field size, independent
+of the data format version. This applies to all scan destinations (e.g. Alphanum, string or []byte).
+
+Example:
+
+	rows, err := db.QueryContext(driver.WithAlphanumPadding(ctx), "select ...")
+	...
+	var a driver.Alphanum
+	err := rows.Scan(&a)
+*/
+func WithAlphanumPadding(ctx context.Context) context.Context {
+	return context.WithValue(ctx, alphanumPaddingCtxKey{}, true)
+}
+
+/*
+useAlphanumPadding sets the alphanum padding mode of the context (if any) for the queries of a statement
+and returns the function restoring the previous mode.
+*/
+func (c *conn) useAlphanumPadding(ctx context.Context) (restore func()) {
+	if padding, ok := ctx.Value(alphanumPaddingCtxKey{}).(bool); !ok || !padding {
+		return func() {}
+	}
+	prev := c.session.SetAlphanumPadding(true)
+	return func() { c.session.SetAlphanumPadding(prev) }
+}
+
+// NullAlphanum represents an Alphanum that may be null.
+// NullAlphanum implements the Scanner interface so
+// it can be used as a scan destination, similar to NullString.
+type NullAlphanum struct {
+	Alphanum Alphanum
+	Valid    bool // Valid is true if Alphanum is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (n *NullAlphanum) Scan(value interface{}) error {
+	if value == nil {
+		n.Alphanum, n.Valid = "", false
+		return nil
+	}
+	n.Valid = true
+	return n.Alphanum.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (n NullAlphanum) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Alphanum.Value()
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"testing"
+)
+
+func testAlphanumScan(t *testing.T) {
+	testData := []struct {
+		src     interface{}
+		value   Alphanum
+		trimmed string
+	}{
+		{[]byte("00000000000000000123"), "00000000000000000123", "123"},
+		{[]byte("00000000000000000000"), "00000000000000000000", "0"},
+		{"0a1b2c", "0a1b2c", "0a1b2c"},
+		{"-123", "-123", "-123"},
+		{"abc", "abc", "abc"},
+	}
+
+	for i, d := range testData {
+		var a Alphanum
+		if err := a.Scan(d.src); err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		if a != d.value {
+			t.Fatalf("test %d: value %s - expected %s", i, a, d.value)
+		}
+		if trimmed := a.Trimmed(); trimmed != d.trimmed {
+			t.Fatalf("test %d: trimmed value %s - expected %s", i, trimmed, d.trimmed)
+		}
+	}
+}
+
+func testNullAlphanum(t *testing.T) {
+	var n NullAlphanum
+	if err := n.Scan(nil); err != nil || n.Valid {
+		t.Fatalf("invalid null value %v - error %v", n, err)
+	}
+	if err := n.Scan([]byte("007")); err != nil || !n.Valid || n.Alphanum != "007" {
+		t.Fatalf("invalid value %v - error %v", n, err)
+	}
+}
+
+func TestAlphanum(t *testing.T) {
+	tests := []struct {
+		name string
+		fct  func(t *testing.T)
+	}{
+		{"alphanumScan", testAlphanumScan},
+		{"nullAlphanum", testNullAlphanum},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.fct(t)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+/*
+ExecBatch executes a prepared statement for all rows of statement arguments as bulk execution.
+The rows are sent to the database in as few requests as possible, where the number of rows per
+request is limited by the connector bulk size and maximal number of batch parameters
+(see Connector.SetBulkSize and Connector.SetMaxBatchParams).
+The returned result reports the aggregated number of affected rows of all requests.
+
+As the bulk arguments are buffered by the driver statement, the statement should be prepared
+on a dedicated connection (sql.Conn) or within a transaction (sql.Tx).
+
+Lob arguments (see Lob) are supported: the lob readers of all rows of a request are streamed to the
+database after the request is executed, so that each row needs its own Lob value and reader.
+
+As the hdb protocol does not support binding array parameters (e.g. to be used by
+UPDATE ... FROM UNNEST(?)), ExecBatch is the recommended way to execute set-based
+operations like bulk updates keyed by a list of ids (see Example_bulkUpdate).
+*/
+func ExecBatch(ctx context.Context, stmt *sql.Stmt, rows [][]interface{}) (sql.Result, error) {
+	if len(rows) == 0 {
+		return p.ExecResult(0), nil
+	}
+
+	var (
+		args []interface{}
+		r    sql.Result
+		err  error
+	)
+	for i, row := range rows {
+		args = append(args[:0], row...)
+		if i == len(rows)-1 {
+			args = append(args, Flush)
+		} else {
+			args = append(args, NoFlush)
+		}
+		if r, err = stmt.ExecContext(ctx, args...); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+/*
+ColumnArray is a statement argument holding the values of one parameter for multiple rows
+(see Executing a statement for multiple rows). Plain slices and arrays are not bound as column
+arrays but rejected as scalar parameter values, so that executing a statement for multiple rows
+needs to be requested explicitly.
+*/
+type ColumnArray struct {
+	rv reflect.Value
+}
+
+// NewColumnArray returns a column array of values, which needs to be a slice or an array.
+func NewColumnArray(values interface{}) ColumnArray {
+	return ColumnArray{rv: reflect.ValueOf(values)}
+}
+
+// check returns an error if the column array values are not a slice or an array.
+func (c ColumnArray) check() error {
+	switch c.rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		return nil
+	case reflect.Invalid:
+		return fmt.Errorf("invalid column array values <nil> - slice or array expected")
+	default:
+		return fmt.Errorf("invalid column array values type %s - slice or array expected", c.rv.Type())
+	}
+}
+
+// isColumnArrays returns true if at least one statement argument is a column array.
+func isColumnArrays(args []driver.NamedValue) bool {
+	for _, arg := range args {
+		if _, ok := arg.Value.(ColumnArray); ok {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+columnArrayRows converts the column arrays into statement arguments of all rows (row by row).
+All arguments need to be column arrays of the same length.
+*/
+func (s *stmt) columnArrayRows(args []driver.NamedValue) ([]driver.NamedValue, int, error) {
+	cols := make([]reflect.Value, len(args))
+	numRow := -1
+	for i, arg := range args {
+		col, ok := arg.Value.(ColumnArray)
+		if !ok {
+			return nil, 0, fmt.Errorf("parameter %d: column array expected - got %T (arguments need to be all column arrays or all scalar values)", arg.Ordinal, arg.Value)
+		}
+		if numRow == -1 {
+			numRow = col.rv.Len()
+		} else if col.rv.Len() != numRow {
+			return nil, 0, fmt.Errorf("parameter %d: column array length %d - expected %d (length of parameter 1)", arg.Ordinal, col.rv.Len(), numRow)
+		}
+		cols[i] = col.rv
+	}
+
+	rows := make([]driver.NamedValue, 0, numRow*len(cols))
+	for i := 0; i < numRow; i++ {
+		for j, col := range cols {
+			nv := driver.NamedValue{Ordinal: j + 1, Value: col.Index(i).Interface()}
+			if err := convertNamedValue(s.pr, &nv, s.conn.strict); err != nil {
+				return nil, 0, fmt.Errorf("row %d: %w", i, err)
+			}
+			rows = append(rows, nv)
+		}
+	}
+	return rows, numRow, nil
+}
+
+// execColumnArrays executes the statement for all rows of the column arrays in as few requests as possible.
+func (s *stmt) execColumnArrays(args []driver.NamedValue) (driver.Result, error) {
+	if s.bulkNum != 0 {
+		return nil, fmt.Errorf("cannot execute column arrays - not flushed records: %d", s.bulkNum)
+	}
+	rows, numRow, err := s.columnArrayRows(args)
+	if err != nil {
+		return nil, err
+	}
+	numField := len(args)
+	var rowsAffected int64
+	for i := 0; i < numRow; i += s.maxBulkNum {
+		j := i + s.maxBulkNum
+		if j > numRow {
+			j = numRow
+		}
+		r, err := s.session.Exec(s.pr, rows[i*numField:j*numField])
+		s.conn._stats.trackBatch()
+		if err != nil {
+			return nil, err
+		}
+		if n, err := r.RowsAffected(); err == nil {
+			rowsAffected += n
+		}
+	}
+	return p.ExecResult(rowsAffected), nil
+}
@@ -19,8 +19,11 @@ limitations under the License.
 package driver
 
 import (
+	"bytes"
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -142,7 +145,225 @@ func testBulkInsertDuplicates(db *sql.DB, t *testing.T) {
 		if dbError.StmtNo() != stmtNo[i] {
 			t.Fatalf("statement number: %d - %d expected", dbError.StmtNo(), stmtNo[i])
 		}
+		if dbError.Code() != HdbErrUniqueViolation {
+			t.Fatalf("error code: %d - %d expected", dbError.Code(), HdbErrUniqueViolation)
+		}
+	}
+
+	if !IsUniqueViolation(err) {
+		t.Fatalf("unique violation expected - got %s", err)
+	}
+	if IsDeadlock(err) {
+		t.Fatalf("unexpected deadlock error %s", err)
+	}
+}
+
+func testExecBatch(db *sql.DB, t *testing.T) {
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const maxBatchParams = 100
+	if err := connector.SetMaxBatchParams(maxBatchParams); err != nil {
+		t.Fatal(err)
+	}
+	batchDB := sql.OpenDB(connector)
+	defer batchDB.Close()
+
+	table := RandomIdentifier("execBatch")
+	if _, err := batchDB.Exec(fmt.Sprintf("create table %s (k integer, v integer)", table)); err != nil {
+		t.Fatalf("create table failed: %s", err)
+	}
+
+	const numRow = 1000
+	rows := make([][]interface{}, numRow)
+	for i := range rows {
+		rows[i] = []interface{}{i, i}
+	}
+
+	tx, err := batchDB.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(fmt.Sprintf("insert into %s values (?,?)", table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	result, err := ExecBatch(context.Background(), stmt, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkAffectedRows(t, result, numRow)
+
+	numBatch := int64(0)
+	for _, s := range connector.ConnStats() {
+		numBatch += s.NumBatch
+	}
+	if expected := int64(numRow / (maxBatchParams / 2)); numBatch != expected {
+		t.Fatalf("number of batch requests %d - expected %d", numBatch, expected)
+	}
+}
+
+func testMaxBulkNumDefault(db *sql.DB, t *testing.T) {
+	sqlConn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlConn.Close()
+
+	if err := sqlConn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*conn)
+		if c.maxBatchParams != 0 {
+			return fmt.Errorf("max batch params %d - expected default", c.maxBatchParams)
+		}
+		maxBatchParams := c.session.PacketSize() / prmSizeEstimate
+		for _, numField := range []int{1, 2, 100, maxBatchParams + 1} {
+			expected := maxBatchParams / numField
+			if n := c.session.MaxBulkNum(); n < expected {
+				expected = n
+			}
+			if expected < 1 {
+				expected = 1
+			}
+			if n := c.maxBulkNum(numField); n != expected {
+				return fmt.Errorf("number of fields %d: max bulk num %d - expected %d", numField, n, expected)
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testExecBatchLob(db *sql.DB, t *testing.T) {
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const bulkSize = 10
+	if err := connector.SetBulkSize(bulkSize); err != nil {
+		t.Fatal(err)
+	}
+	batchDB := sql.OpenDB(connector)
+	defer batchDB.Close()
+
+	table := RandomIdentifier("execBatchLob")
+	if _, err := batchDB.Exec(fmt.Sprintf("create table %s (k integer, v nclob)", table)); err != nil {
+		t.Fatalf("create table failed: %s", err)
+	}
+
+	const numRow = 25 // last request not filling the bulk size
+	value := func(i int) string { return strings.Repeat(fmt.Sprintf("%d", i%10), 1000*(i+1)) }
+
+	rows := make([][]interface{}, numRow)
+	for i := range rows {
+		rows[i] = []interface{}{i, NewLob(strings.NewReader(value(i)), nil)}
+	}
+
+	tx, err := batchDB.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(fmt.Sprintf("insert into %s values (?,?)", table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	result, err := ExecBatch(context.Background(), stmt, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkAffectedRows(t, result, numRow)
+
+	dbRows, err := tx.Query(fmt.Sprintf("select k, v from %s order by k", table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbRows.Close()
+	n := 0
+	for dbRows.Next() {
+		var k int
+		b := new(bytes.Buffer)
+		if err := dbRows.Scan(&k, NewLob(nil, b)); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != value(k) {
+			t.Fatalf("row %d: lob value size %d - expected %d", k, b.Len(), len(value(k)))
+		}
+		n++
+	}
+	if err := dbRows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n != numRow {
+		t.Fatalf("number of rows %d - expected %d", n, numRow)
+	}
+}
+
+func testColumnArrays(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("columnArrays")
+	if _, err := db.Exec(fmt.Sprintf("create table %s (k integer, v nvarchar(10))", table)); err != nil {
+		t.Fatalf("create table failed: %s", err)
+	}
+
+	const numRow = 1000
+	ks := make([]int, numRow)
+	vs := make([]string, numRow)
+	for i := 0; i < numRow; i++ {
+		ks[i] = i
+		vs[i] = fmt.Sprintf("v%d", i)
+	}
+
+	stmt, err := db.Prepare(fmt.Sprintf("insert into %s values (?,?)", table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	// plain slices are no column arrays
+	if _, err := stmt.Exec(ks, vs); err == nil {
+		t.Fatal("scalar argument error expected")
+	}
+
+	result, err := stmt.Exec(NewColumnArray(ks), NewColumnArray(vs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkAffectedRows(t, result, numRow)
+
+	var cnt int
+	if err := db.QueryRow(fmt.Sprintf("select count(*) from %s where v = 'v' || to_nvarchar(k)", table)).Scan(&cnt); err != nil {
+		t.Fatal(err)
+	}
+	if cnt != numRow {
+		t.Fatalf("number of rows %d - expected %d", cnt, numRow)
+	}
+
+	// different lengths
+	if _, err := stmt.Exec(NewColumnArray(ks), NewColumnArray(vs[:1])); err == nil {
+		t.Fatal("column array length error expected")
+	}
+	// column array and scalar value
+	if _, err := stmt.Exec(NewColumnArray(ks), "v"); err == nil {
+		t.Fatal("column array error expected")
+	}
+	// no slice
+	if _, err := stmt.Exec(NewColumnArray(1), NewColumnArray("v")); err == nil {
+		t.Fatal("column array values error expected")
+	}
+	// empty column arrays
+	result, err = stmt.Exec(NewColumnArray([]int{}), NewColumnArray([]string{}))
+	if err != nil {
+		t.Fatal(err)
 	}
+	checkAffectedRows(t, result, 0)
 }
 
 func testBulk(db *sql.DB, t *testing.T) {
@@ -197,6 +418,10 @@ func TestBulk(t *testing.T) {
 	}{
 		{"testBulk", testBulk},
 		{"testBulkInsertDuplicates", testBulkInsertDuplicates},
+		{"testExecBatch", testExecBatch},
+		{"testMaxBulkNumDefault", testMaxBulkNumDefault},
+		{"testExecBatchLob", testExecBatchLob},
+		{"testColumnArrays", testColumnArrays},
 	}
 
 	for _, test := range tests {
@@ -17,7 +17,12 @@ limitations under the License.
 package driver
 
 import (
+	"context"
 	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
 )
 
 // NullBytes represents an []byte that may be null.
@@ -41,3 +46,64 @@ func (n NullBytes) Value() (driver.Value, error) {
 	}
 	return n.Bytes, nil
 }
+
+/*
+RawCESU8 represents unicode character data (NCHAR, NVARCHAR, SHORTTEXT, ...) as CESU-8 encoded bytes,
+the encoding used by the database. Please note that the bytes are CESU-8, not UTF-8, encoded: characters
+outside the Basic Multilingual Plane are encoded as a surrogate pair of two 3-byte sequences.
+
+Bound as parameter the bytes are sent to the database verbatim. As scan destination RawCESU8 receives
+the field bytes as read from the database, which requires the query to be executed with a context
+returned by WithRawCESU8 (character data of other queries is transformed into UTF-8 on fetching the
+result set and cannot be scanned into RawCESU8). So a HANA to HANA copy can pass values without an
+encoding round trip.
+A NULL value is scanned as nil RawCESU8 and a nil RawCESU8 is bound as NULL value.
+*/
+type RawCESU8 []byte
+
+// Scan implements the database/sql/Scanner interface.
+func (r *RawCESU8) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*r = nil
+		return nil
+	case p.RawCESU8:
+		*r = append(RawCESU8(nil), src...)
+		return nil
+	case []byte, string:
+		return errors.New("raw CESU-8: character data is transformed into UTF-8 - use a context returned by WithRawCESU8 for the query")
+	default:
+		return fmt.Errorf("raw CESU-8: invalid data type %T", src)
+	}
+}
+
+type rawCESU8CtxKey struct{}
+
+/*
+WithRawCESU8 returns a copy of the context which makes queries executed with the context return
+unicode character data (NCHAR, NVARCHAR, SHORTTEXT, ...) as CESU-8 bytes as read from the database,
+without transformation into UTF-8. Fields of such queries can be scanned into RawCESU8 or []byte,
+but not into string. Large objects (NCLOB, TEXT) are not affected.
+
+Example:
+
+	rows, err := db.QueryContext(driver.WithRawCESU8(ctx), "select ...")
+	...
+	var raw driver.RawCESU8
+	err := rows.Scan(&raw)
+*/
+func WithRawCESU8(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rawCESU8CtxKey{}, true)
+}
+
+/*
+useRawCESU8 sets the raw CESU-8 mode of the context (if any) for the queries of a statement
+and returns the function restoring the previous mode.
+*/
+func (c *conn) useRawCESU8(ctx context.Context) (restore func()) {
+	if raw, ok := ctx.Value(rawCESU8CtxKey{}).(bool); !ok || !raw {
+		return func() {}
+	}
+	prev := c.session.SetRawCESU8(true)
+	return func() { c.session.SetRawCESU8(prev) }
+}
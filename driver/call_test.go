@@ -20,9 +20,11 @@ package driver
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	"math/big"
 	"reflect"
 	"testing"
 )
@@ -239,6 +241,197 @@ end
 	}
 }
 
+func testCallOutParams(db *sql.DB, t *testing.T) {
+	const procOutParams = `create procedure %[1]s (in i integer, out o1 integer, out o2 nvarchar(20), out o3 decimal(10,2))
+language SQLSCRIPT as
+begin
+    o1 := i * 2;
+    o2 := 'Hello World';
+    o3 := 47.11;
+end
+`
+	proc := RandomIdentifier("procOutParams_")
+	if _, err := db.Exec(fmt.Sprintf(procOutParams, proc)); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		o1 int
+		o2 string
+		o3 Decimal
+	)
+	// sql.Out arguments are assigned in procedure parameter declaration order
+	if _, err := db.Exec(fmt.Sprintf("call %s(?, ?, ?, ?)", proc), 21, sql.Out{Dest: &o1}, sql.Out{Dest: &o2}, sql.Out{Dest: &o3}); err != nil {
+		t.Fatal(err)
+	}
+
+	if o1 != 42 {
+		t.Fatalf("value %d - expected %d", o1, 42)
+	}
+	if o2 != "Hello World" {
+		t.Fatalf("value %s - expected %s", o2, "Hello World")
+	}
+	if (*big.Rat)(&o3).Cmp(big.NewRat(4711, 100)) != 0 {
+		t.Fatalf("value %s - expected %s", (*big.Rat)(&o3), big.NewRat(4711, 100))
+	}
+}
+
+func testCallInOutParams(db *sql.DB, t *testing.T) {
+	const procInOutParams = `create procedure %[1]s (inout i integer)
+language SQLSCRIPT as
+begin
+    i := i * 2;
+end
+`
+	proc := RandomIdentifier("procInOutParams_")
+	if _, err := db.Exec(fmt.Sprintf(procInOutParams, proc)); err != nil {
+		t.Fatal(err)
+	}
+
+	i := 21
+	// sql.Out.In: value of destination is passed as input value
+	if _, err := db.Exec(fmt.Sprintf("call %s(?)", proc), sql.Out{Dest: &i, In: true}); err != nil {
+		t.Fatal(err)
+	}
+	if i != 42 {
+		t.Fatalf("value %d - expected %d", i, 42)
+	}
+
+	// named parameter
+	if _, err := db.Exec(fmt.Sprintf("call %s(:i)", proc), sql.Named("i", sql.Out{Dest: &i, In: true})); err != nil {
+		t.Fatal(err)
+	}
+	if i != 84 {
+		t.Fatalf("value %d - expected %d", i, 84)
+	}
+}
+
+func testCallResultSetInfos(db *sql.DB, t *testing.T) {
+	const procResultSets = `create procedure %[1]s (in i integer, out t1 table(a integer, b nvarchar(20)), out t2 table(c decimal(10,2), d date, e varchar(5)))
+language SQLSCRIPT as
+begin
+  t1 = select :i as a, 'Hello' as b from dummy;
+  t2 = select 47.11 as c, current_date as d, 'World' as e from dummy;
+end
+`
+	proc := RandomIdentifier("procResultSets_")
+	if _, err := db.Exec(fmt.Sprintf(procResultSets, proc)); err != nil {
+		t.Fatal(err)
+	}
+
+	var infos []ResultSetInfo
+	rows, err := db.QueryContext(WithResultSetInfos(context.Background(), &infos), fmt.Sprintf("call %s(?, ?, ?)", proc), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	expected := []struct {
+		columns   []string
+		typeNames []string
+	}{
+		{[]string{"A", "B"}, []string{"INTEGER", "NVARCHAR"}},
+		{[]string{"C", "D", "E"}, []string{"DECIMAL", "DAYDATE", "VARCHAR"}},
+	}
+	if len(infos) != len(expected) {
+		t.Fatalf("number of result sets %d - expected %d", len(infos), len(expected))
+	}
+	for i, e := range expected {
+		if !reflect.DeepEqual(infos[i].Columns, e.columns) {
+			t.Fatalf("result set %d: columns %v - expected %v", i, infos[i].Columns, e.columns)
+		}
+		for j, typeName := range e.typeNames {
+			if ci := infos[i].Types[j]; ci.DatabaseTypeName != typeName && !(typeName == "DAYDATE" && ci.DatabaseTypeName == "DATE") {
+				t.Fatalf("result set %d column %d: type %s - expected %s", i, j, ci.DatabaseTypeName, typeName)
+			}
+		}
+	}
+	if ci := infos[1].Types[0]; !ci.HasPrecision || ci.Precision != 10 || ci.Scale != 2 {
+		t.Fatalf("precision %d scale %d - expected 10 2", ci.Precision, ci.Scale)
+	}
+}
+
+func testCallNextResultSet(db *sql.DB, t *testing.T) {
+	const procResultSets = `create procedure %[1]s (in i integer, out o integer, out t1 table(a integer, b nvarchar(20)), out t2 table(c nvarchar(20)))
+language SQLSCRIPT as
+begin
+  o := :i * 2;
+  t1 = select :i as a, 'Hello' as b from dummy union all select :i + 1 as a, 'World' as b from dummy;
+  t2 = select 'Hello World' as c from dummy;
+end
+`
+	proc := RandomIdentifier("procNextResultSet_")
+	if _, err := db.Exec(fmt.Sprintf(procResultSets, proc)); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query(fmt.Sprintf("call %s(?, ?, ?, ?)", proc), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	// first result set: scalar output parameters (and table output parameters as sql.Rows)
+	if !rows.Next() {
+		t.Fatal(rows.Err())
+	}
+	var (
+		o      int
+		t1, t2 sql.Rows
+	)
+	if err := rows.Scan(&o, &t1, &t2); err != nil {
+		t.Fatal(err)
+	}
+	if o != 2 {
+		t.Fatalf("value %d - expected %d", o, 2)
+	}
+
+	// table t1
+	if !rows.NextResultSet() {
+		t.Fatal(rows.Err())
+	}
+	var (
+		a []int
+		b []string
+	)
+	for rows.Next() {
+		var (
+			i int
+			s string
+		)
+		if err := rows.Scan(&i, &s); err != nil {
+			t.Fatal(err)
+		}
+		a = append(a, i)
+		b = append(b, s)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(a, []int{1, 2}) || !reflect.DeepEqual(b, []string{"Hello", "World"}) {
+		t.Fatalf("values %v %v - expected %v %v", a, b, []int{1, 2}, []string{"Hello", "World"})
+	}
+
+	// table t2
+	if !rows.NextResultSet() {
+		t.Fatal(rows.Err())
+	}
+	var c string
+	if !rows.Next() {
+		t.Fatal(rows.Err())
+	}
+	if err := rows.Scan(&c); err != nil {
+		t.Fatal(err)
+	}
+	if c != "Hello World" {
+		t.Fatalf("value %s - expected %s", c, "Hello World")
+	}
+
+	if rows.NextResultSet() {
+		t.Fatal("no further result set expected")
+	}
+}
+
 func TestCall(t *testing.T) {
 	tests := []struct {
 		name string
@@ -247,6 +440,10 @@ func TestCall(t *testing.T) {
 		{"echo", testCallEcho},
 		{"blobEcho", testCallBlobEcho},
 		{"tableOut", testCallTableOut},
+		{"outParams", testCallOutParams},
+		{"inOutParams", testCallInOutParams},
+		{"resultSetInfos", testCallResultSetInfos},
+		{"nextResultSet", testCallNextResultSet},
 	}
 
 	for _, test := range tests {
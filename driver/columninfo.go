@@ -0,0 +1,114 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql/driver"
+	"reflect"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+// ColumnInfo contains the metadata of a statement parameter or result column (see Conn.Describe).
+type ColumnInfo struct {
+	Name             string       // Parameter or column name (might be empty for parameters).
+	DatabaseTypeName string       // Database type name (e.g. VARCHAR, DECIMAL).
+	ScanType         reflect.Type // Go type suitable for scanning.
+	Length           int64        // Length of variable length types.
+	HasLength        bool         // Length is set.
+	LengthInChars    bool         // Length is measured in characters (e.g. NVARCHAR) instead of bytes (e.g. VARCHAR).
+	Precision, Scale int64        // Precision and scale of decimal types.
+	HasPrecision     bool         // Precision and scale are set.
+	Nullable         bool         // Value might be NULL.
+	In, Out          bool         // Parameter mode (result columns are Out only).
+}
+
+/*
+Cesu8Len returns the length of s in characters like measured by the database for NVARCHAR and other
+character based fields (see ColumnInfo.LengthInChars), where characters outside the basic multilingual plane
+(e.g. emojis) count twice as they are encoded as surrogate pairs in CESU-8. A string fitting into a field
+by its number of UTF-8 characters might therefore exceed the field length.
+*/
+func Cesu8Len(s string) int { return p.CharLength(s) }
+
+/*
+VarcharLen returns the length of s like measured by the database for VARCHAR and other byte based fields
+(CHAR, ALPHANUM): the number of UTF-8 bytes, as string values are sent to these fields unchanged.
+*/
+func VarcharLen(s string) int { return len(s) }
+
+/*
+NvarcharLen returns the length of s like measured by the database for NVARCHAR, NCHAR and SHORTTEXT fields (see Cesu8Len):
+the number of characters, where a character outside the basic multilingual plane (e.g. "\U0001D11E") counts as 2.
+*/
+func NvarcharLen(s string) int { return Cesu8Len(s) }
+
+/*
+RowsColumnTypeLengthSemantics may be implemented by driver.Rows. It returns the type length of a variable
+length column and if the length is measured in characters (chars == true, e.g. NVARCHAR, where characters
+outside the basic multilingual plane count twice, see Cesu8Len) or in bytes (e.g. VARCHAR). For columns
+without type length the returned length is zero.
+The rows returned by the driver connection (see sql.Conn.Raw) implement this interface. As sql.ColumnType
+cannot be extended, the length semantics of database/sql query results are available via
+WithResultSetInfos (see ColumnInfo.LengthInChars).
+*/
+type RowsColumnTypeLengthSemantics interface {
+	driver.Rows
+	ColumnTypeLengthSemantics(index int) (chars bool, length int64)
+}
+
+// ResultSetInfo describes the columns of a result set (see WithResultSetInfos).
+type ResultSetInfo struct {
+	Columns []string     // Column names.
+	Types   []ColumnInfo // Column metadata.
+}
+
+func newResultSetInfo(fields []p.Field) ResultSetInfo {
+	info := ResultSetInfo{Columns: make([]string, len(fields)), Types: make([]ColumnInfo, len(fields))}
+	for i, f := range fields {
+		info.Columns[i] = f.Name()
+		info.Types[i] = newColumnInfo(f)
+	}
+	return info
+}
+
+func newColumnInfo(f p.Field) ColumnInfo {
+	ci := ColumnInfo{
+		Name:             f.Name(),
+		DatabaseTypeName: f.TypeName(),
+		ScanType:         f.ScanType().ScanType(),
+		Nullable:         f.Nullable(),
+		In:               f.In(),
+		Out:              f.Out(),
+	}
+	ci.Length, ci.HasLength = f.TypeLength()
+	ci.LengthInChars = ci.HasLength && p.LengthInChars(f)
+	ci.Precision, ci.Scale, ci.HasPrecision = f.TypePrecisionScale()
+	return ci
+}
+
+func describePrepareResult(pr *p.PrepareResult) (params, columns []ColumnInfo) {
+	params = make([]ColumnInfo, pr.NumField())
+	for i := range params {
+		params[i] = newColumnInfo(pr.PrmField(i))
+	}
+	columns = make([]ColumnInfo, pr.NumResultField())
+	for i := range columns {
+		columns[i] = newColumnInfo(pr.ResultField(i))
+	}
+	return params, columns
+}
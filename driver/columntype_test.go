@@ -25,8 +25,6 @@ import (
 	"strings"
 	"testing"
 	"time"
-
-	p "github.com/SAP/go-hdb/internal/protocol"
 )
 
 func testColumnType(connector *Connector, dataType func(string, int) string, dfv int, t *testing.T) {
@@ -37,6 +35,20 @@ func testColumnType(connector *Connector, dataType func(string, int) string, dfv
 		testBinary  = []byte{0x00, 0x01, 0x02}
 	)
 
+	var (
+		uint8Type   = reflect.TypeOf(uint8(0))
+		int16Type   = reflect.TypeOf(int16(0))
+		int32Type   = reflect.TypeOf(int32(0))
+		int64Type   = reflect.TypeOf(int64(0))
+		float32Type = reflect.TypeOf(float32(0))
+		float64Type = reflect.TypeOf(float64(0))
+		stringType  = reflect.TypeOf("")
+		bytesType   = reflect.TypeOf([]byte(nil))
+		timeType    = reflect.TypeOf(time.Time{})
+		decimalType = reflect.TypeOf(Decimal{})
+		lobType     = reflect.TypeOf(Lob{})
+	)
+
 	testColumnTypeData := []struct {
 		sqlType string
 		length  int64
@@ -52,38 +64,38 @@ func testColumnType(connector *Connector, dataType func(string, int) string, dfv
 
 		value interface{}
 	}{
-		{"tinyint", 0, false, false, "TINYINT", 0, 0, true, p.DtTinyint.ScanType(), 1},
-		{"smallint", 0, false, false, "SMALLINT", 0, 0, true, p.DtSmallint.ScanType(), 42},
-		{"integer", 0, false, false, "INTEGER", 0, 0, true, p.DtInteger.ScanType(), 4711},
-		{"bigint", 0, false, false, "BIGINT", 0, 0, true, p.DtBigint.ScanType(), 68000},
-		{"decimal", 0, false, true, "DECIMAL", 34, 32767, true, p.DtDecimal.ScanType(), testDecimal},
-		{"real", 0, false, false, "REAL", 0, 0, true, p.DtReal.ScanType(), 1.0},
-		{"double", 0, false, false, "DOUBLE", 0, 0, true, p.DtDouble.ScanType(), 3.14},
-		{"char", 30, true, false, "CHAR", 0, 0, true, p.DtString.ScanType(), testString},
-		{"varchar", 30, true, false, "VARCHAR", 0, 0, true, p.DtString.ScanType(), testString},
-		{"nchar", 20, true, false, "NCHAR", 0, 0, true, p.DtString.ScanType(), testString},
-		{"nvarchar", 20, true, false, "NVARCHAR", 0, 0, true, p.DtString.ScanType(), testString},
-		{"binary", 10, true, false, "BINARY", 0, 0, true, p.DtBytes.ScanType(), testBinary},
-		{"varbinary", 10, true, false, "VARBINARY", 0, 0, true, p.DtBytes.ScanType(), testBinary},
-		{"date", 0, false, false, dataType("DAYDATE", dfv), 0, 0, true, p.DtTime.ScanType(), testTime},
-		{"time", 0, false, false, dataType("SECONDTIME", dfv), 0, 0, true, p.DtTime.ScanType(), testTime},
-		{"timestamp", 0, false, false, dataType("LONGDATE", dfv), 0, 0, true, p.DtTime.ScanType(), testTime},
-		{"clob", 0, false, false, "CLOB", 0, 0, true, p.DtLob.ScanType(), new(Lob).SetReader(bytes.NewBuffer(testBinary))},
-		{"nclob", 0, false, false, "NCLOB", 0, 0, true, p.DtLob.ScanType(), new(Lob).SetReader(bytes.NewBuffer(testBinary))},
-		{"blob", 0, false, false, "BLOB", 0, 0, true, p.DtLob.ScanType(), new(Lob).SetReader(bytes.NewBuffer(testBinary))},
-		{"boolean", 0, false, false, "TINYINT", 0, 0, true, p.DtTinyint.ScanType(), false},                // hdb gives TINYINT back - not BOOLEAN
-		{"smalldecimal", 0, false, true, "DECIMAL", 16, 32767, true, p.DtDecimal.ScanType(), testDecimal}, // hdb gives DECIMAL back - not SMALLDECIMAL
+		{"tinyint", 0, false, false, "TINYINT", 0, 0, true, uint8Type, 1},
+		{"smallint", 0, false, false, "SMALLINT", 0, 0, true, int16Type, 42},
+		{"integer", 0, false, false, "INTEGER", 0, 0, true, int32Type, 4711},
+		{"bigint", 0, false, false, "BIGINT", 0, 0, true, int64Type, 68000},
+		{"decimal", 0, false, true, "DECIMAL", 34, 32767, true, decimalType, testDecimal},
+		{"real", 0, false, false, "REAL", 0, 0, true, float32Type, 1.0},
+		{"double", 0, false, false, "DOUBLE", 0, 0, true, float64Type, 3.14},
+		{"char", 30, true, false, "CHAR", 0, 0, true, stringType, testString},
+		{"varchar", 30, true, false, "VARCHAR", 0, 0, true, stringType, testString},
+		{"nchar", 20, true, false, "NCHAR", 0, 0, true, stringType, testString},
+		{"nvarchar", 20, true, false, "NVARCHAR", 0, 0, true, stringType, testString},
+		{"binary", 10, true, false, "BINARY", 0, 0, true, bytesType, testBinary},
+		{"varbinary", 10, true, false, "VARBINARY", 0, 0, true, bytesType, testBinary},
+		{"date", 0, false, false, dataType("DAYDATE", dfv), 0, 0, true, timeType, testTime},
+		{"time", 0, false, false, dataType("SECONDTIME", dfv), 0, 0, true, timeType, testTime},
+		{"timestamp", 0, false, false, dataType("LONGDATE", dfv), 0, 0, true, timeType, testTime},
+		{"clob", 0, false, false, "CLOB", 0, 0, true, lobType, new(Lob).SetReader(bytes.NewBuffer(testBinary))},
+		{"nclob", 0, false, false, "NCLOB", 0, 0, true, lobType, new(Lob).SetReader(bytes.NewBuffer(testBinary))},
+		{"blob", 0, false, false, "BLOB", 0, 0, true, lobType, new(Lob).SetReader(bytes.NewBuffer(testBinary))},
+		{"boolean", 0, false, false, "TINYINT", 0, 0, true, uint8Type, false},                  // hdb gives TINYINT back - not BOOLEAN
+		{"smalldecimal", 0, false, true, "DECIMAL", 16, 32767, true, decimalType, testDecimal}, // hdb gives DECIMAL back - not SMALLDECIMAL
 		//{"text", 0, false, false, "NCLOB", 0, 0, true, testLob},             // hdb gives NCLOB back - not TEXT
-		{"shorttext", 15, true, false, dataType("SHORTTEXT", dfv), 0, 0, true, p.DtString.ScanType(), testString},
-		{"alphanum", 15, true, false, dataType("ALPHANUM", dfv), 0, 0, true, p.DtString.ScanType(), testString},
-		{"longdate", 0, false, false, dataType("LONGDATE", dfv), 0, 0, true, p.DtTime.ScanType(), testTime},
-		{"seconddate", 0, false, false, dataType("SECONDDATE", dfv), 0, 0, true, p.DtTime.ScanType(), testTime},
-		{"daydate", 0, false, false, dataType("DAYDATE", dfv), 0, 0, true, p.DtTime.ScanType(), testTime},
-		{"secondtime", 0, false, false, dataType("SECONDTIME", dfv), 0, 0, true, p.DtTime.ScanType(), testTime},
+		{"shorttext", 15, true, false, dataType("SHORTTEXT", dfv), 0, 0, true, stringType, testString},
+		{"alphanum", 15, true, false, dataType("ALPHANUM", dfv), 0, 0, true, stringType, testString},
+		{"longdate", 0, false, false, dataType("LONGDATE", dfv), 0, 0, true, timeType, testTime},
+		{"seconddate", 0, false, false, dataType("SECONDDATE", dfv), 0, 0, true, timeType, testTime},
+		{"daydate", 0, false, false, dataType("DAYDATE", dfv), 0, 0, true, timeType, testTime},
+		{"secondtime", 0, false, false, dataType("SECONDTIME", dfv), 0, 0, true, timeType, testTime},
 
 		// not nullable
-		{"tinyint", 0, false, false, "TINYINT", 0, 0, false, p.DtTinyint.ScanType(), 42},
-		{"nvarchar", 25, true, false, "NVARCHAR", 0, 0, false, p.DtString.ScanType(), testString},
+		{"tinyint", 0, false, false, "TINYINT", 0, 0, false, uint8Type, 42},
+		{"nvarchar", 25, true, false, "NVARCHAR", 0, 0, false, stringType, testString},
 	}
 
 	// text is only supported for column table
@@ -23,6 +23,8 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/SAP/go-hdb/driver/sqltrace"
 	p "github.com/SAP/go-hdb/internal/protocol"
@@ -62,13 +64,23 @@ var ErrUnsupportedIsolationLevel = errors.New("unsupported isolation level")
 // ErrNestedTransaction is the error raised if a tranasction is created within a transaction as this is not supported by hdb.
 var ErrNestedTransaction = errors.New("nested transactions are not supported")
 
+// ErrMixedParameters is the error raised if named parameters (:name, sql.Named) and positional parameters (?)
+// are mixed in a statement.
+var ErrMixedParameters = p.ErrMixedParameters
+
+// ErrLastInsertIDNotSupported is the error returned by sql.Result.LastInsertId, as the database does not report
+// the ids of inserted rows (the last value of an identity column can be selected by CURRENT_IDENTITY_VALUE()).
+var ErrLastInsertIDNotSupported = p.ErrLastInsertIDNotSupported
+
 // queries
 const (
 	pingQuery          = "select 1 from dummy"
 	isolationLevelStmt = "set transaction isolation level %s"
 	accessModeStmt     = "set transaction %s"
 	sessionVariable    = "set %s=%s"
+	unsetSessionVar    = "unset %s"
 	defaultSchema      = "set schema %s"
+	userSessionVars    = "select key, value from m_session_context where connection_id = current_connection and section = 'USER'"
 )
 
 // bulk statement
@@ -91,6 +103,8 @@ var (
 func init() {
 	p.RegisterScanType(p.DtDecimal, reflect.TypeOf((*Decimal)(nil)).Elem())
 	p.RegisterScanType(p.DtLob, reflect.TypeOf((*Lob)(nil)).Elem())
+	p.RegisterScanType(p.DtDecimalArray, reflect.TypeOf((*DecimalArray)(nil)).Elem())
+	p.RegisterScanType(p.DtSTPoint, reflect.TypeOf((*STPoint)(nil)).Elem())
 }
 
 //  check if conn implements all required interfaces
@@ -107,44 +121,270 @@ var (
 	_ driver.SessionResetter    = (*conn)(nil)
 )
 
+/*
+Conn enhances a connection with go-hdb specific connection functions.
+The driver connection can be accessed via the database/sql Conn Raw method:
+
+	err := sqlConn.Raw(func(driverConn interface{}) error {
+		return driverConn.(driver.Conn).Invalidate()
+	})
+*/
+type Conn interface {
+	// Invalidate drops all server side statement handles of the connection, so that
+	// the statements are prepared again on their next execution and changed database
+	// metadata (e.g. after an ALTER TABLE statement) is used.
+	Invalidate() error
+	// Stats returns the diagnostic information of the connection.
+	Stats() ConnStats
+	// Describe prepares the query and returns the parameter and result column metadata
+	// without executing the statement. The statement handle is released afterwards.
+	Describe(ctx context.Context, query string) (params, columns []ColumnInfo, err error)
+	// InTransaction returns true if the connection has an open transaction.
+	InTransaction() bool
+	// SessionInfo returns the metadata of the database session (connection id, user, schema, ...)
+	// queried in a single database round-trip.
+	SessionInfo(ctx context.Context) (*SessionInfo, error)
+	// SupportsInlineTableParams returns true if the connected server supports table parameters.
+	SupportsInlineTableParams() bool
+	// SupportsCompression returns true if network compression was negotiated with the connected server.
+	SupportsCompression() bool
+	// SupportsClientReconnect returns true if the connected server supports transparent session recovery.
+	SupportsClientReconnect() bool
+	// StatementPlan prepares the query and returns the textual execution plan of the prepared statement
+	// as chosen by the optimizer (plan cache entry of the statement). The statement handle is released afterwards.
+	StatementPlan(ctx context.Context, query string) (string, error)
+}
+
+var _ Conn = (*conn)(nil)
+
 type conn struct {
+	ctr     *Connector
 	session *p.Session
 	scanner *scanner.Scanner
+	stmts   map[*stmt]struct{} // prepared statements of the connection
+	_stats  connStats
+
+	cancelMode     CancelMode
+	maxBatchParams int
+	queryTimeout   time.Duration
+	maxPrepared    int
+	strict         bool   // strict conversion of statement arguments
+	readYourWrites bool   // read your writes guarantee (see SetReadYourWrites)
+	wrote          bool   // connection did execute a write statement
+	useSeq         uint64 // statement usage sequence (least recently used statement eviction)
+
+	sessionVariables SessionVariables // session variables set on connection initialization
+	initSchema       Identifier       // current schema after connection initialization (see setExecuted)
+	schemaChanged    bool             // SET SCHEMA executed since last session reset
+	sessionChanged   bool             // statement executed since last session reset which might change session variables
+
+	maxRetries          int           // maximal number of statement re-executions (see SetMaxRetries)
+	retryBackoff        time.Duration // wait time before the first retry
+	retriableErrorCodes []int
+
+	tracing bool // tracer is set (see SetTracer)
 }
 
 func newConn(ctx context.Context, ctr *Connector) (driver.Conn, error) {
-	session, err := p.NewSession(ctx, ctr)
+	session, err := newSession(ctx, ctr)
 	if err != nil {
 		return nil, err
 	}
-	c := &conn{session: session, scanner: &scanner.Scanner{}}
+	if username, cookie := session.SessionCookie(); cookie != nil {
+		ctr.setSessionCookie(username, cookie)
+	}
+	c := &conn{ctr: ctr, session: session, scanner: &scanner.Scanner{}, stmts: map[*stmt]struct{}{}, cancelMode: ctr.CancelMode(), maxBatchParams: ctr.MaxBatchParams(), queryTimeout: ctr.QueryTimeout(), maxPrepared: ctr.MaxPreparedPerConn(), strict: ctr.StrictConversion(), readYourWrites: ctr.ReadYourWrites(), maxRetries: ctr.MaxRetries(), retryBackoff: ctr.RetryBackoff(), retriableErrorCodes: ctr.RetriableErrorCodes(), tracing: ctr.Tracer() != nil}
 	if err := c.init(ctx, ctr); err != nil {
+		session.Close() // do not leak the session of a failed connect
 		return nil, err
 	}
+	ctr.conns.add(c)
 	return c, nil
 }
 
+// check if sessionConfig implements session parameter interface.
+var _ p.SessionConfig = (*sessionConfig)(nil)
+
+// sessionConfig is the session configuration of a connector for one host of the connector host list.
+type sessionConfig struct {
+	*Connector
+	host string
+}
+
+func (c sessionConfig) Host() string { return c.host }
+
+// WarningHandler returns the warning handler of the connector as function of the session configuration.
+func (c sessionConfig) WarningHandler() func(warning error) { return c.Connector.warningFunc() }
+
+// newSession opens a session to the first reachable host of the connector host list.
+func newSession(ctx context.Context, ctr *Connector) (*p.Session, error) {
+	hosts := ctr.connectHosts()
+	if len(hosts) == 1 {
+		return p.NewSession(ctx, sessionConfig{Connector: ctr, host: hosts[0]})
+	}
+	errs := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		session, err := p.NewSession(ctx, sessionConfig{Connector: ctr, host: host})
+		if err == nil {
+			ctr.setPreferredHost(host)
+			return session, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		errs = append(errs, fmt.Sprintf("%s: %s", host, err))
+	}
+	return nil, fmt.Errorf("connect failed for all hosts: %s", strings.Join(errs, "; "))
+}
+
 func (c *conn) init(ctx context.Context, ctr *Connector) error {
-	if ctr.sessionVariables != nil {
-		for k, v := range ctr.sessionVariables {
-			if _, err := c.ExecContext(ctx, fmt.Sprintf(sessionVariable, fmt.Sprintf("'%s'", k), fmt.Sprintf("'%s'", v)), nil); err != nil {
-				return err
-			}
+	if err := c.setSessionVariables(ctx, ctr.sessionVariables); err != nil {
+		return err
+	}
+	if clientInfo := ctr.ClientInfo(); clientInfo != nil {
+		sv, err := clientInfo(ctx)
+		if err != nil {
+			return fmt.Errorf("client info: %w", err)
+		}
+		if err := c.setSessionVariables(ctx, sv); err != nil {
+			return err
 		}
 	}
 	if ctr.defaultSchema != "" {
 		if _, err := c.ExecContext(ctx, fmt.Sprintf(defaultSchema, ctr.defaultSchema), nil); err != nil {
 			return err
 		}
+		c.schemaChanged = false // initial schema
 	}
+	c.sessionChanged = false // initial session variables
 	return nil
 }
 
+func (c *conn) setSessionVariables(ctx context.Context, sv SessionVariables) error {
+	for k, v := range sv {
+		if _, err := c.ExecContext(ctx, fmt.Sprintf(sessionVariable, quoteLiteral(k), quoteLiteral(v)), nil); err != nil {
+			return err
+		}
+		if c.sessionVariables == nil {
+			c.sessionVariables = SessionVariables{}
+		}
+		c.sessionVariables[k] = v
+	}
+	return nil
+}
+
+/*
+changesSession returns true if a statement of query kind k might change session variables:
+SET statements, procedure calls and statements of unknown kind (e.g. anonymous blocks).
+*/
+func changesSession(k p.QueryKind) bool { return k == p.QkSet || k == p.QkCall || k == p.QkUnknown }
+
+/*
+setExecuted keeps the connection state consistent with an executed or prepared SET SCHEMA statement:
+cached statements might resolve object names differently (see SetStmtCacheSize) and the current schema
+after the connection initialization needs to be restored on session reset (see ResetSession).
+*/
+func (c *conn) setExecuted(query string) error {
+	fields := strings.Fields(query)
+	if len(fields) < 2 || strings.ToLower(fields[1]) != "schema" {
+		return nil
+	}
+	if !c.schemaChanged {
+		if c.initSchema = c.ctr.defaultSchema; c.initSchema == "" {
+			schema, err := c.currentSchema()
+			if err != nil {
+				return err
+			}
+			c.initSchema = schema
+		}
+		c.schemaChanged = true
+	}
+	return c.session.ClearStmtCache()
+}
+
+/*
+resetSessionVariables unsets the session variables set by the user since the connection initialization
+and restores the values of the session variables set on initialization (see SetSessionVariables and
+SetClientInfo). The statements are executed like user statements, so that they are bound by ctx.
+*/
+func (c *conn) resetSessionVariables(ctx context.Context) error {
+	values, err := c.queryValues(ctx, userSessionVars)
+	if err != nil {
+		return err
+	}
+	set := make(map[string]bool, len(values))
+	for _, row := range values {
+		k, v := asString(row[0]), asString(row[1])
+		set[k] = true
+		initial, ok := c.sessionVariables[k]
+		switch {
+		case !ok:
+			_, err = c.ExecContext(ctx, fmt.Sprintf(unsetSessionVar, quoteLiteral(k)), nil)
+		case initial != v:
+			_, err = c.ExecContext(ctx, fmt.Sprintf(sessionVariable, quoteLiteral(k), quoteLiteral(initial)), nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for k, v := range c.sessionVariables { // initial variables unset by the user
+		if !set[k] {
+			if _, err := c.ExecContext(ctx, fmt.Sprintf(sessionVariable, quoteLiteral(k), quoteLiteral(v)), nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// asString returns the string value of a character database value.
+func asString(v driver.Value) string {
+	switch v := v.(type) {
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// quoteLiteral returns s as sql string literal.
+func quoteLiteral(s string) string { return "'" + strings.ReplaceAll(s, "'", "''") + "'" }
+
+/*
+ResetSession implements the driver.SessionResetter interface.
+A transaction which is still open is rolled back to avoid carrying it into the next usage of the connection.
+Session variables set since the connection was handed out (by SET statements, procedures or anonymous blocks)
+are unset and the session variables set on connection initialization (see SetSessionVariables and SetClientInfo)
+are restored, so that session context does not leak into the next usage of the connection. Therefore the session
+variables are read from the database on session reset, if a statement which might change session variables (SET
+statements, procedure calls and statements of unknown kind like anonymous blocks) was executed since the last reset.
+A current schema set by SET SCHEMA is reset to the schema after the connection initialization (see SetDefaultSchema).
+*/
 func (c *conn) ResetSession(ctx context.Context) error {
 	c.session.Reset()
 	if c.session.IsBad() {
 		return driver.ErrBadConn
 	}
+	if err := c.session.RollbackOpenTx(); err != nil {
+		return driver.ErrBadConn
+	}
+	c.wrote = false // read your writes guarantee is bound to the usage of the connection
+	if c.sessionChanged {
+		if err := c.resetSessionVariables(ctx); err != nil {
+			sqltrace.Tracef("reset session variables failed: %s", err)
+			return driver.ErrBadConn
+		}
+	}
+	if c.schemaChanged {
+		if _, err := c.ExecContext(ctx, fmt.Sprintf(defaultSchema, c.initSchema), nil); err != nil {
+			sqltrace.Tracef("reset schema failed: %s", err)
+			return driver.ErrBadConn
+		}
+		c.schemaChanged = false
+	}
+	c.sessionChanged = false // reset statements
 	return nil
 }
 
@@ -153,48 +393,222 @@ func (c *conn) PrepareContext(ctx context.Context, query string) (stmt driver.St
 		return nil, driver.ErrBadConn
 	}
 
+	defer c.useContext(ctx)()
 	done := make(chan struct{})
 	go func() {
 		var (
-			qd *p.QueryDescr
-			pr *p.PrepareResult
+			qd        *p.QueryDescr
+			pr        *p.PrepareResult
+			stmtQuery string
+			schema    Identifier
+			cached    bool
 		)
 
 		qd, err = p.NewQueryDescr(query, c.scanner)
 		if err != nil {
 			goto done
 		}
-		pr, err = c.session.Prepare(qd.Query())
-		if err != nil {
-			goto done
+		if changesSession(qd.Kind()) {
+			c.sessionChanged = true
+		}
+		if qd.Kind() == p.QkSet {
+			if err = c.setExecuted(qd.Query()); err != nil {
+				goto done
+			}
+		}
+		stmtQuery = addHint(qd.PrepareQuery(), c.contextHints(ctx, qd.Kind()))
+		schema = contextSchema(ctx)
+		if pr, cached = c.session.CachedPrepareResult(stmtCacheKey(schema, stmtQuery)); !cached {
+			err = c.inSchema(schema, func() error { pr, err = c.session.Prepare(stmtQuery); return err })
+			if err != nil {
+				goto done
+			}
 		}
 
 		if err = pr.Check(qd); err != nil {
 			goto done
 		}
 
-		select {
-		default:
-		case <-ctx.Done():
-			return
-		}
-		stmt, err = newStmt(c.session, qd.Query(), qd.IsBulk(), pr)
+		stmt, err = newStmt(c, stmtQuery, c.currentQuery(ctx, qd.PrepareQuery(), stmtQuery, qd.Kind()), schema, qd.IsBulk(), isWrite(qd.Kind()), qd.NamedParameters(), pr)
 	done:
 		close(done)
 	}()
 
+	if err := c.wait(ctx, done, func() {
+		if stmt != nil {
+			stmt.Close()
+		}
+	}); err != nil {
+		return nil, err
+	}
+	return stmt, err
+}
+
+/*
+useContext sets the context of a statement for the database requests of the statement (see WithWarningHandler,
+WithRawCESU8, WithAlphanumPadding and SetTracer) and returns the function restoring the previous settings.
+*/
+func (c *conn) useContext(ctx context.Context) (restore func()) {
+	restoreWarningHandler := c.useWarningHandler(ctx)
+	restoreRawCESU8 := c.useRawCESU8(ctx)
+	restoreAlphanumPadding := c.useAlphanumPadding(ctx)
+	if !c.tracing {
+		return func() {
+			restoreAlphanumPadding()
+			restoreRawCESU8()
+			restoreWarningHandler()
+		}
+	}
+	prev := c.session.SetContext(ctx)
+	return func() {
+		c.session.SetContext(prev)
+		restoreAlphanumPadding()
+		restoreRawCESU8()
+		restoreWarningHandler()
+	}
+}
+
+/*
+wait waits until the database request executed in a separate go routine is done (done channel closed)
+or the context is cancelled. In case of context cancellation the request is cancelled according to
+the connection cancel mode and cleanup is called (if not nil) to release the results of a completed
+request in cancel modes which do keep the connection.
+The connection query timeout (if set) bounds the waiting time in addition to the context.
+*/
+func (c *conn) wait(ctx context.Context, done <-chan struct{}, cleanup func()) error {
+	if c.queryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.queryTimeout)
+		defer cancel()
+	}
+
 	select {
-	case <-ctx.Done():
-		return nil, ctx.Err()
 	case <-done:
-		return stmt, err
+		return nil
+	case <-ctx.Done():
+	}
+
+	switch c.cancelMode {
+	case CancelSoft:
+		if err := c.session.Cancel(); err != nil {
+			sqltrace.Tracef("cancel failed: %s", err)
+		}
+		<-done
+	case CancelDrain:
+		<-done
+	default: // CancelHardClose
+		c.session.Kill()
+		return ctx.Err()
+	}
+
+	if cleanup != nil {
+		cleanup()
+	}
+	return ctx.Err()
+}
+
+// prmSizeEstimate is the estimated average encoded size of a parameter value (see maxBulkNum).
+const prmSizeEstimate = 32
+
+/*
+maxBulkNum returns the maximal number of bulk statement executions sent to the database in one request
+dependent on the number of statement parameters. Without max batch params (see SetMaxBatchParams) the
+number of parameter values per request is derived from the session packet size.
+*/
+func (c *conn) maxBulkNum(numField int) int {
+	maxBulkNum := c.session.MaxBulkNum()
+	maxBatchParams := c.maxBatchParams
+	if maxBatchParams == 0 {
+		maxBatchParams = c.session.PacketSize() / prmSizeEstimate
+	}
+	if numField > 0 {
+		if n := maxBatchParams / numField; n < maxBulkNum {
+			maxBulkNum = n
+		}
+		if maxBulkNum < 1 {
+			maxBulkNum = 1
+		}
 	}
+	return maxBulkNum
 }
 
 func (c *conn) Close() error {
+	c.ctr.conns.remove(c)
 	return c.session.Close()
 }
 
+// InTransaction implements the Conn interface.
+func (c *conn) InTransaction() bool { return c.session.InTx() }
+
+// SessionInfo implements the Conn interface.
+func (c *conn) SessionInfo(ctx context.Context) (*SessionInfo, error) { return c.sessionInfo(ctx) }
+
+// StatementPlan implements the Conn interface.
+func (c *conn) StatementPlan(ctx context.Context, query string) (string, error) {
+	return c.statementPlan(ctx, query)
+}
+
+// SupportsInlineTableParams implements the Conn interface.
+func (c *conn) SupportsInlineTableParams() bool { return c.session.SupportsInlineTableParams() }
+
+// SupportsCompression implements the Conn interface.
+func (c *conn) SupportsCompression() bool { return c.session.SupportsCompression() }
+
+// SupportsClientReconnect implements the Conn interface.
+func (c *conn) SupportsClientReconnect() bool { return c.session.SupportsClientReconnect() }
+
+// Stats implements the Conn interface.
+func (c *conn) Stats() ConnStats { return c.stats() }
+
+// Invalidate implements the Conn interface.
+func (c *conn) Invalidate() error {
+	if c.session.IsBad() {
+		return driver.ErrBadConn
+	}
+	c.session.Reset()
+	if err := c.session.ClearStmtCache(); err != nil {
+		return err
+	}
+	for s := range c.stmts {
+		if err := s.reprepare(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *conn) Describe(ctx context.Context, query string) (params, columns []ColumnInfo, err error) {
+	if c.session.IsBad() {
+		return nil, nil, driver.ErrBadConn
+	}
+
+	done := make(chan struct{})
+	go func() {
+		var (
+			qd *p.QueryDescr
+			pr *p.PrepareResult
+		)
+
+		qd, err = p.NewQueryDescr(query, c.scanner)
+		if err != nil {
+			goto done
+		}
+		pr, err = c.session.Prepare(qd.PrepareQuery())
+		if err != nil {
+			goto done
+		}
+		params, columns = describePrepareResult(pr)
+		err = c.session.DropStatementID(pr.StmtID())
+	done:
+		close(done)
+	}()
+
+	if err := c.wait(ctx, done, nil); err != nil {
+		return nil, nil, err
+	}
+	return params, columns, err
+}
+
 func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (tx driver.Tx, err error) {
 	if c.session.IsBad() {
 		return nil, driver.ErrBadConn
@@ -224,15 +638,19 @@ func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (tx driver.Tx
 		close(done)
 	}()
 
-	select {
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	case <-done:
-		return tx, err
+	if err := c.wait(ctx, done, func() {
+		if tx != nil {
+			tx.Rollback()
+		}
+	}); err != nil {
+		return nil, err
 	}
+	return tx, err
 }
 
 func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (rows driver.Rows, err error) {
+	defer func() { c._stats.track(err) }()
+
 	if c.session.IsBad() {
 		return nil, driver.ErrBadConn
 	}
@@ -260,32 +678,41 @@ func (c *conn) QueryContext(ctx context.Context, query string, args []driver.Nam
 		return qrs, nil
 	}
 
+	if changesSession(qd.Kind()) {
+		c.sessionChanged = true
+	}
+
+	query = addHint(query, c.contextHints(ctx, qd.Kind()))
+
 	sqltrace.Traceln(query)
 
+	defer c.useContext(ctx)()
 	done := make(chan struct{})
 	go func() {
-		rows, err = c.session.QueryDirect(query)
-		if err != nil {
-			goto done
-		}
-		select {
-		default:
-		case <-ctx.Done():
-			return
-		}
-	done:
+		err = c.retry(ctx, true, func() error {
+			return c.inSchema(contextSchema(ctx), func() error { rows, err = c.session.QueryDirect(query); return err })
+		})
 		close(done)
 	}()
 
-	select {
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	case <-done:
-		return rows, err
+	if err := c.wait(ctx, done, func() {
+		if rows != nil {
+			rows.Close()
+		}
+	}); err != nil {
+		return nil, err
 	}
+	if err == nil {
+		setFetchSize(ctx, rows)
+		setRowsAffected(ctx, rows)
+		setResultSetInfos(ctx, rows)
+	}
+	return rows, err
 }
 
 func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (r driver.Result, err error) {
+	defer func() { c._stats.track(err) }()
+
 	if c.session.IsBad() {
 		return nil, driver.ErrBadConn
 	}
@@ -296,6 +723,8 @@ func (c *conn) ExecContext(ctx context.Context, query string, args []driver.Name
 
 	sqltrace.Traceln(query)
 
+	var write bool
+	defer c.useContext(ctx)()
 	done := make(chan struct{})
 	go func() {
 		var qd *p.QueryDescr
@@ -303,36 +732,60 @@ func (c *conn) ExecContext(ctx context.Context, query string, args []driver.Name
 		if err != nil {
 			goto done
 		}
-		r, err = c.session.ExecDirect(qd.Query())
+		write = isWrite(qd.Kind())
+		if changesSession(qd.Kind()) {
+			c.sessionChanged = true
+		}
+		if qd.Kind() == p.QkSet { // before execution: current schema is read on the first SET SCHEMA
+			if err = c.setExecuted(qd.Query()); err != nil {
+				goto done
+			}
+		}
+		err = c.retry(ctx, qd.Kind() != p.QkCall, func() error {
+			return c.inSchema(contextSchema(ctx), func() error { r, err = c.session.ExecDirect(qd.Query()); return err })
+		})
 	done:
 		close(done)
 	}()
 
-	select {
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	case <-done:
-		return r, err
+	if err := c.wait(ctx, done, nil); err != nil {
+		return nil, err
 	}
+	if err == nil && write {
+		c.wrote = true
+	}
+	return r, err
 }
 
+/*
+Ping implements the driver.Pinger interface.
+Ping executes a lightweight query (database round trip) bounded by the context, so that a connection whose
+database session was dropped (e.g. after an idle period) is detected even if the network connection still
+looks alive. Ping returns driver.ErrBadConn if the query fails, so that the connection is discarded by the
+connection pool.
+*/
 func (c *conn) Ping(ctx context.Context) (err error) {
 	if c.session.IsBad() {
 		return driver.ErrBadConn
 	}
 
+	var rows driver.Rows
 	done := make(chan struct{})
 	go func() {
-		_, err = c.QueryContext(ctx, pingQuery, nil)
+		if rows, err = c.session.QueryDirect(pingQuery); err == nil {
+			err = rows.Close()
+		}
 		close(done)
 	}()
 
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-done:
-		return err
+	if ctxErr := c.wait(ctx, done, nil); ctxErr != nil {
+		return ctxErr
+	}
+	if err != nil {
+		sqltrace.Tracef("ping failed: %s", err)
+		return driver.ErrBadConn
 	}
+	return nil
 }
 
 // CheckNamedValue implements NamedValueChecker interface.
@@ -390,22 +843,57 @@ var (
 
 type stmt struct {
 	pr                  *p.PrepareResult
+	conn                *conn
 	session             *p.Session
 	query               string
+	names               []string   // named parameters (see bindNamed)
+	schema              Identifier // schema the statement is prepared in (see WithSchema)
 	bulk, flush         bool
+	write               bool   // write statement (see SetReadYourWrites)
+	currentQuery        string // query without result lag hint used after the connection did write (see SetReadYourWrites)
 	maxBulkNum, bulkNum int
 	args                []driver.NamedValue
+	bulkRowsAffected    int64  // aggregated rows affected of bulk executions since last flush
+	dropped             bool   // statement handle dropped (see SetMaxPreparedPerConn)
+	lastUse             uint64 // usage sequence number of last execution
 }
 
-func newStmt(session *p.Session, query string, bulk bool, pr *p.PrepareResult) (*stmt, error) {
-	return &stmt{session: session, query: query, pr: pr, bulk: bulk, maxBulkNum: session.MaxBulkNum()}, nil
+func newStmt(conn *conn, query, currentQuery string, schema Identifier, bulk, write bool, names []string, pr *p.PrepareResult) (*stmt, error) {
+	s := &stmt{conn: conn, session: conn.session, query: query, currentQuery: currentQuery, names: names, schema: schema, pr: pr, bulk: bulk, write: write, maxBulkNum: conn.maxBulkNum(pr.NumField())}
+	conn.stmts[s] = struct{}{}
+	conn._stats.trackPrepared(1)
+	s.lastUse = conn.nextUse()
+	if err := conn.limitPrepared(s); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
 }
 
 func (s *stmt) Close() error {
+	delete(s.conn.stmts, s)
 	if len(s.args) != 0 {
 		sqltrace.Tracef("close: %s - not flushed records: %d)", s.query, len(s.args)/s.NumInput())
 	}
-	return s.session.DropStatementID(s.pr.StmtID())
+	if s.dropped {
+		return nil
+	}
+	s.conn._stats.trackPrepared(-1)
+	return s.session.CachePrepareResult(stmtCacheKey(s.schema, s.query), s.pr)
+}
+
+/*
+reprepare drops the statement handle, so that the statement is prepared again on next usage (see use).
+As the statement is marked as dropped, a failing prepare does not leave the statement with a dropped handle.
+*/
+func (s *stmt) reprepare() error {
+	if s.dropped { // prepared on next usage
+		return nil
+	}
+	if len(s.args) != 0 {
+		return fmt.Errorf("cannot prepare statement %s - not flushed records: %d", s.query, s.bulkNum)
+	}
+	return s.drop()
 }
 
 func (s *stmt) NumInput() int {
@@ -420,10 +908,16 @@ func (s *stmt) NumInput() int {
 }
 
 func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (rows driver.Rows, err error) {
+	defer func() { s.conn._stats.track(err) }()
+
 	if s.session.IsBad() {
 		return nil, driver.ErrBadConn
 	}
 
+	if args, err = s.bindNamed(args); err != nil {
+		return nil, err
+	}
+
 	sqltrace.Tracef("%s %v", s.query, args)
 
 	numArg := len(args)
@@ -436,30 +930,54 @@ func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (rows
 	if numArg != numExpected {
 		return nil, fmt.Errorf("invalid number of arguments %d - %d expected", numArg, numExpected)
 	}
+	if isColumnArrays(args) {
+		return nil, fmt.Errorf("column arrays are not supported by queries - use Exec to execute a statement for multiple rows")
+	}
 
+	defer s.conn.useContext(ctx)()
 	done := make(chan struct{})
 	go func() {
+		if err = s.use(); err != nil {
+			close(done)
+			return
+		}
 		if s.pr.IsProcedureCall() {
 			rows, err = s.session.QueryCall(s.pr, args)
 		} else {
-			rows, err = s.session.Query(s.pr, args)
+			err = s.conn.retry(ctx, !hasReaderArgs(args), func() error { rows, err = s.session.Query(s.pr, args); return err })
 		}
 		close(done)
 	}()
 
-	select {
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	case <-done:
-		return rows, err
+	if err := s.conn.wait(ctx, done, func() {
+		if rows != nil {
+			rows.Close()
+		}
+	}); err != nil {
+		return nil, err
+	}
+	if err == nil {
+		if s.write {
+			s.conn.wrote = true
+		}
+		setFetchSize(ctx, rows)
+		setRowsAffected(ctx, rows)
+		setResultSetInfos(ctx, rows)
 	}
+	return rows, err
 }
 
 func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (r driver.Result, err error) {
+	defer func() { s.conn._stats.track(err) }()
+
 	if s.session.IsBad() {
 		return nil, driver.ErrBadConn
 	}
 
+	if args, err = s.bindNamed(args); err != nil {
+		return nil, err
+	}
+
 	sqltrace.Tracef("%s %v", s.query, args)
 
 	numArg := len(args)
@@ -478,11 +996,18 @@ func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (r dri
 	}
 	defer func() { s.flush = false }()
 
+	defer s.conn.useContext(ctx)()
 	done := make(chan struct{})
 	go func() {
+		if err = s.use(); err != nil {
+			close(done)
+			return
+		}
 		switch {
 		case s.pr.IsProcedureCall():
 			r, err = s.session.ExecCall(s.pr, args)
+		case isColumnArrays(args):
+			r, err = s.execColumnArrays(args)
 		case s.bulk:
 			r, err = driver.ResultNoRows, nil
 
@@ -498,19 +1023,34 @@ func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (r dri
 				r, err = s.session.Exec(s.pr, s.args)
 				s.args = s.args[:0]
 				s.bulkNum = 0
+				s.conn._stats.trackBatch()
+				if err == nil {
+					if n, err := r.RowsAffected(); err == nil {
+						s.bulkRowsAffected += n
+					}
+					r = driver.ResultNoRows
+				}
+			}
+
+			if s.flush && err == nil { // report aggregated rows affected
+				r = p.ExecResult(s.bulkRowsAffected)
+			}
+			if s.flush || err != nil {
+				s.bulkRowsAffected = 0
 			}
 		default:
-			r, err = s.session.Exec(s.pr, args)
+			err = s.conn.retry(ctx, !hasReaderArgs(args), func() error { r, err = s.session.Exec(s.pr, args); return err })
 		}
 		close(done)
 	}()
 
-	select {
-	case <-ctx.Done():
-		return nil, ctx.Err()
-	case <-done:
-		return r, err
+	if err := s.conn.wait(ctx, done, nil); err != nil {
+		return nil, err
 	}
+	if err == nil && s.write {
+		s.conn.wrote = true
+	}
+	return r, err
 }
 
 // CheckNamedValue implements NamedValueChecker interface.
@@ -528,5 +1068,80 @@ func (s *stmt) CheckNamedValue(nv *driver.NamedValue) error {
 		}
 	}
 
-	return convertNamedValue(s.pr, nv)
+	if nv.Name != "" { // named arguments are converted on binding (see bindNamed)
+		return nil
+	}
+	return s.convertNamedValue(nv)
+}
+
+func (s *stmt) convertNamedValue(nv *driver.NamedValue) error {
+	// column arrays are converted row by row on execution
+	if col, ok := nv.Value.(ColumnArray); ok {
+		if s.pr.IsProcedureCall() {
+			return fmt.Errorf("parameter %d: column arrays are not supported by procedure calls", nv.Ordinal)
+		}
+		if err := col.check(); err != nil {
+			return fmt.Errorf("parameter %d: %w", nv.Ordinal, err)
+		}
+		return nil
+	}
+
+	return convertNamedValue(s.pr, nv, s.conn.strict)
+}
+
+/*
+bindNamed binds named arguments (sql.Named) to the named parameters (:name) of the statement
+and returns the converted positional arguments:
+- an argument is bound to all occurrences of its named parameter
+- output parameters of procedure calls without argument are skipped (see QueryCall)
+- named and positional arguments must not be mixed
+*/
+func (s *stmt) bindNamed(args []driver.NamedValue) ([]driver.NamedValue, error) {
+	numNamed := 0
+	for _, arg := range args {
+		if arg.Name != "" {
+			numNamed++
+		}
+	}
+	if numNamed == 0 {
+		return args, nil
+	}
+	if numNamed != len(args) {
+		return nil, ErrMixedParameters
+	}
+	if s.names == nil {
+		return nil, fmt.Errorf("named arguments are not supported by statement without named parameters (:name)")
+	}
+
+	values := make(map[string]interface{}, len(args))
+	for _, arg := range args {
+		if _, ok := values[arg.Name]; ok {
+			return nil, fmt.Errorf("duplicate named argument %s", arg.Name)
+		}
+		values[arg.Name] = arg.Value
+	}
+
+	bound := make([]driver.NamedValue, 0, len(s.names))
+	used := make(map[string]bool, len(values))
+	for i, name := range s.names {
+		v, ok := values[name]
+		if !ok {
+			if f := s.pr.PrmField(i); f.Out() && !f.In() {
+				continue
+			}
+			return nil, fmt.Errorf("missing argument for named parameter :%s", name)
+		}
+		nv := driver.NamedValue{Ordinal: i + 1, Value: v}
+		if err := s.convertNamedValue(&nv); err != nil {
+			return nil, fmt.Errorf("named parameter :%s: %w", name, err)
+		}
+		bound = append(bound, nv)
+		used[name] = true
+	}
+	for _, arg := range args {
+		if !used[arg.Name] {
+			return nil, fmt.Errorf("named argument %s does not match a named parameter", arg.Name)
+		}
+	}
+	return bound, nil
 }
@@ -0,0 +1,186 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
+	"github.com/SAP/go-hdb/internal/protocol/scanner"
+)
+
+/*
+ConsistencyLevel defines the data freshness required by a query in a system replication
+setup with read access on the secondary system (active/active read enabled).
+*/
+type ConsistencyLevel int
+
+// ConsistencyLevel constants.
+const (
+	/*
+		ConsistencyCurrent requires the query to read current data (default).
+		No hint is added to the query, so the query is executed on the primary system
+		and reads the writes of the own transaction.
+	*/
+	ConsistencyCurrent ConsistencyLevel = iota
+	/*
+		ConsistencyResultLag allows the query to be executed on the secondary system accepting
+		a result lag (hint RESULT_LAG('hana_sr')). The query might not see the latest writes.
+	*/
+	ConsistencyResultLag
+)
+
+const resultLagHint = "RESULT_LAG('hana_sr')"
+
+type consistencyCtxKey struct{}
+
+/*
+WithConsistency returns a copy of the context with the consistency level set.
+The consistency level is applied to select statements executed (or prepared) with the returned context.
+*/
+func WithConsistency(ctx context.Context, level ConsistencyLevel) context.Context {
+	return context.WithValue(ctx, consistencyCtxKey{}, level)
+}
+
+func consistencyHint(ctx context.Context, kind p.QueryKind) string {
+	if kind != p.QkSelect {
+		return ""
+	}
+	level, _ := ctx.Value(consistencyCtxKey{}).(ConsistencyLevel)
+	if level == ConsistencyResultLag {
+		return resultLagHint
+	}
+	return ""
+}
+
+// contextHints returns the hints set via context (consistency level, store preference) as hint list.
+func contextHints(ctx context.Context, kind p.QueryKind) string {
+	var hints []string
+	for _, hint := range []string{consistencyHint(ctx, kind), storeHint(ctx, kind)} {
+		if hint != "" {
+			hints = append(hints, hint)
+		}
+	}
+	return strings.Join(hints, ", ")
+}
+
+// isWrite returns true if a statement of query kind might write data (all statements except select and set
+// statements like set schema or set transaction).
+func isWrite(kind p.QueryKind) bool {
+	return kind != p.QkSelect && kind != p.QkSet
+}
+
+// contextHints returns the context hints of a query applying the read your writes guarantee of the connection
+// (see SetReadYourWrites).
+func (c *conn) contextHints(ctx context.Context, kind p.QueryKind) string {
+	if c.readYourWrites && c.wrote {
+		ctx = WithConsistency(ctx, ConsistencyCurrent)
+	}
+	return contextHints(ctx, kind)
+}
+
+/*
+currentQuery returns the query prepared without result lag hint, which is used by a prepared statement as soon
+as the connection did write (see SetReadYourWrites), or an empty string in case the query does not differ from
+the prepared query stmtQuery.
+*/
+func (c *conn) currentQuery(ctx context.Context, query, stmtQuery string, kind p.QueryKind) string {
+	if !c.readYourWrites || c.wrote {
+		return ""
+	}
+	if query = addHint(query, contextHints(WithConsistency(ctx, ConsistencyCurrent), kind)); query == stmtQuery {
+		return ""
+	}
+	return query
+}
+
+/*
+addHint adds a hint to the query. If the query does already contain a top-level hint clause
+(outside of string literals, comments and subqueries) the hint is added to the existing hint list.
+Otherwise a hint clause is appended to the statement, placed before trailing comments and
+without a trailing statement delimiter.
+*/
+func addHint(query, hint string) string {
+	if hint == "" {
+		return query
+	}
+
+	sc := &scanner.Scanner{}
+	sc.Reset(query)
+
+	var (
+		depth      int    // parenthesis depth
+		prev       string // lower case text of the previous token (identifiers only)
+		prev2      string // lower case text of the token before the previous one (identifiers only)
+		stmtEnd    int    // end of the last statement token (excluding comments and delimiter)
+		hintPos    = -1   // position after the opening parenthesis of a top-level hint clause
+		checkEmpty bool   // check if the hint list is empty
+		hintEmpty  bool   // hint list is empty
+		comments   []string
+	)
+	for {
+		token, start, end := sc.Next()
+		if token == scanner.EOS {
+			break
+		}
+		text := query[start:end]
+		if token == scanner.Comment {
+			if start >= stmtEnd {
+				comments = append(comments, text)
+			}
+			continue
+		}
+		if checkEmpty {
+			hintEmpty, checkEmpty = text == ")", false
+		}
+		if token == scanner.Delimiter {
+			switch text {
+			case "(":
+				if depth == 0 && prev == "hint" && prev2 == "with" {
+					hintPos, checkEmpty = end, true
+				}
+				depth++
+			case ")":
+				depth--
+			}
+		}
+		if text != ";" {
+			stmtEnd, comments = end, nil
+		}
+		prev2, prev = prev, ""
+		if token == scanner.Identifier {
+			prev = strings.ToLower(text)
+		}
+	}
+
+	if hintPos != -1 {
+		if hintEmpty {
+			return query[:hintPos] + hint + query[hintPos:]
+		}
+		return query[:hintPos] + hint + ", " + query[hintPos:]
+	}
+	b := strings.Builder{}
+	b.WriteString(query[:stmtEnd])
+	fmt.Fprintf(&b, " with hint(%s)", hint)
+	for _, comment := range comments { // a line comment ends the line
+		b.WriteByte('\n')
+		b.WriteString(comment)
+	}
+	return b.String()
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"testing"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+func testAddHint(t *testing.T) {
+	testData := []struct {
+		query, hint, result string
+	}{
+		{"select * from dummy", "", "select * from dummy"},
+		{"select * from dummy", resultLagHint, "select * from dummy with hint(RESULT_LAG('hana_sr'))"},
+		{"select * from dummy;", resultLagHint, "select * from dummy with hint(RESULT_LAG('hana_sr'))"},
+		{"select * from dummy WITH HINT(NO_CS_JOIN)", resultLagHint, "select * from dummy WITH HINT(RESULT_LAG('hana_sr'), NO_CS_JOIN)"},
+		{"select * from dummy with hint (NO_CS_JOIN)", resultLagHint, "select * from dummy with hint (RESULT_LAG('hana_sr'), NO_CS_JOIN)"},
+		{"select * from dummy With\n\tHint(NO_CS_JOIN)", resultLagHint, "select * from dummy With\n\tHint(RESULT_LAG('hana_sr'), NO_CS_JOIN)"},
+		{"select 'İ' from dummy with hint(NO_CS_JOIN)", resultLagHint, "select 'İ' from dummy with hint(RESULT_LAG('hana_sr'), NO_CS_JOIN)"},
+		{"select * from dummy_with hint(x)", resultLagHint, "select * from dummy_with hint(x) with hint(RESULT_LAG('hana_sr'))"},
+		{"select * from dummy -- comment", resultLagHint, "select * from dummy with hint(RESULT_LAG('hana_sr'))\n-- comment"},
+		{"select * from dummy; /* c1 */ -- c2\n", resultLagHint, "select * from dummy with hint(RESULT_LAG('hana_sr'))\n/* c1 */\n-- c2"},
+		{"select * from dummy with hint()", resultLagHint, "select * from dummy with hint(RESULT_LAG('hana_sr'))"},
+		{"select 'with hint(x)' from dummy", resultLagHint, "select 'with hint(x)' from dummy with hint(RESULT_LAG('hana_sr'))"},
+		{"select * from (select * from dummy with hint(x))", resultLagHint, "select * from (select * from dummy with hint(x)) with hint(RESULT_LAG('hana_sr'))"},
+		{"select * from (select * from dummy with hint(x)) with hint(y)", resultLagHint, "select * from (select * from dummy with hint(x)) with hint(RESULT_LAG('hana_sr'), y)"},
+		{"select * from dummy /* with hint(x) */", resultLagHint, "select * from dummy with hint(RESULT_LAG('hana_sr'))\n/* with hint(x) */"},
+	}
+
+	for i, d := range testData {
+		if result := addHint(d.query, d.hint); result != d.result {
+			t.Fatalf("test %d: query %s - expected %s", i, result, d.result)
+		}
+	}
+}
+
+func testConsistencyHint(t *testing.T) {
+	ctx := context.Background()
+	if hint := consistencyHint(ctx, p.QkSelect); hint != "" {
+		t.Fatalf("hint %s - expected none", hint)
+	}
+	if hint := consistencyHint(WithConsistency(ctx, ConsistencyCurrent), p.QkSelect); hint != "" {
+		t.Fatalf("hint %s - expected none", hint)
+	}
+	if hint := consistencyHint(WithConsistency(ctx, ConsistencyResultLag), p.QkSelect); hint != resultLagHint {
+		t.Fatalf("hint %s - expected %s", hint, resultLagHint)
+	}
+	if hint := consistencyHint(WithConsistency(ctx, ConsistencyResultLag), p.QkUpdate); hint != "" {
+		t.Fatalf("hint %s - expected none", hint)
+	}
+}
+
+func testContextHints(t *testing.T) {
+	ctx := WithStorePreference(context.Background(), StoreColumn)
+	if hints := contextHints(ctx, p.QkSelect); hints != "CS_JOIN" {
+		t.Fatalf("hints %s - expected %s", hints, "CS_JOIN")
+	}
+	ctx = WithConsistency(WithStorePreference(context.Background(), StoreRow), ConsistencyResultLag)
+	if hints := contextHints(ctx, p.QkSelect); hints != resultLagHint+", NO_CS_JOIN" {
+		t.Fatalf("hints %s - expected %s", hints, resultLagHint+", NO_CS_JOIN")
+	}
+	if hints := contextHints(WithStorePreference(context.Background(), "invalid"), p.QkSelect); hints != "" {
+		t.Fatalf("hints %s - expected none", hints)
+	}
+	if hints := contextHints(ctx, p.QkUpdate); hints != "" {
+		t.Fatalf("hints %s - expected none", hints)
+	}
+}
+
+func testReadYourWrites(t *testing.T) {
+	ctx := WithConsistency(context.Background(), ConsistencyResultLag)
+
+	for _, kind := range []p.QueryKind{p.QkSelect, p.QkSet} {
+		if isWrite(kind) {
+			t.Fatalf("query kind %s: no write statement expected", kind)
+		}
+	}
+	for _, kind := range []p.QueryKind{p.QkInsert, p.QkUpdate, p.QkCall, p.QkUnknown} {
+		if !isWrite(kind) {
+			t.Fatalf("query kind %s: write statement expected", kind)
+		}
+	}
+
+	testData := []struct {
+		readYourWrites, wrote bool
+		hints                 string
+	}{
+		{false, false, resultLagHint},
+		{false, true, resultLagHint},
+		{true, false, resultLagHint},
+		{true, true, ""},
+	}
+
+	for i, d := range testData {
+		c := &conn{readYourWrites: d.readYourWrites, wrote: d.wrote}
+		if hints := c.contextHints(ctx, p.QkSelect); hints != d.hints {
+			t.Fatalf("test %d: hints %s - expected %s", i, hints, d.hints)
+		}
+	}
+
+	// query of statements prepared before the first write
+	const query = "select * from dummy"
+	ctx = WithStorePreference(ctx, StoreColumn)
+	storeQuery := addHint(query, storeHint(ctx, p.QkSelect))
+	for i, d := range testData {
+		c := &conn{readYourWrites: d.readYourWrites, wrote: d.wrote}
+		stmtQuery := addHint(query, c.contextHints(ctx, p.QkSelect))
+		expected := ""
+		if d.readYourWrites && !d.wrote {
+			expected = storeQuery // keep store preference
+		}
+		if currentQuery := c.currentQuery(ctx, query, stmtQuery, p.QkSelect); currentQuery != expected {
+			t.Fatalf("test %d: current query %s - expected %s", i, currentQuery, expected)
+		}
+	}
+	if c := (&conn{readYourWrites: true}); c.currentQuery(context.Background(), query, query, p.QkSelect) != "" {
+		t.Fatal("no current query expected without result lag hint")
+	}
+}
+
+func TestConsistency(t *testing.T) {
+	tests := []struct {
+		name string
+		fct  func(t *testing.T)
+	}{
+		{"addHint", testAddHint},
+		{"consistencyHint", testConsistencyHint},
+		{"contextHints", testContextHints},
+		{"readYourWrites", testReadYourWrites},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.fct(t)
+		})
+	}
+}
@@ -21,12 +21,26 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"io"
+	"math"
 	"reflect"
 
 	p "github.com/SAP/go-hdb/internal/protocol"
 )
 
-func convertNamedValue(pr *p.PrepareResult, nv *driver.NamedValue) error {
+/*
+LengthError is the error returned if a statement argument exceeds the length of a variable length field,
+so that values are never truncated silently. It can be detected via errors.As.
+
+Example:
+
+	var lengthErr *driver.LengthError
+	if errors.As(err, &lengthErr) {
+		...
+	}
+*/
+type LengthError = p.LengthError
+
+func convertNamedValue(pr *p.PrepareResult, nv *driver.NamedValue, strict bool) error {
 	idx := nv.Ordinal - 1
 
 	f := pr.PrmField(idx)
@@ -43,8 +57,19 @@ func convertNamedValue(pr *p.PrepareResult, nv *driver.NamedValue) error {
 		return fmt.Errorf("out parameter %v needs to be pointer variable", v)
 	}
 
+	dest := v // output parameter destination
+
 	var err error
 
+	// raw CESU-8 bytes are sent verbatim (no UTF-8 to CESU-8 transformation)
+	if raw, ok := v.(RawCESU8); ok && !out {
+		if raw == nil {
+			v = nil
+		} else {
+			v = p.RawCESU8(raw)
+		}
+	}
+
 	// let fields with own Value converter convert themselves first (e.g. NullInt64, ...)
 	if valuer, ok := v.(driver.Valuer); ok {
 		if v, err = valuer.Value(); err != nil {
@@ -52,6 +77,24 @@ func convertNamedValue(pr *p.PrepareResult, nv *driver.NamedValue) error {
 		}
 	}
 
+	if !out {
+		if err := checkScalarArg(v); err != nil {
+			return fmt.Errorf("parameter %d (%s %s): %w", nv.Ordinal, f.Name(), f.TypeName(), err)
+		}
+		if strict {
+			if err := checkStrictArg(f.ScanType(), v); err != nil {
+				return fmt.Errorf("parameter %d (%s %s): %w", nv.Ordinal, f.Name(), f.TypeName(), err)
+			}
+		}
+	}
+
+	// exact decimal conversion of strings and big integers
+	if !out && f.ScanType() == p.DtDecimal {
+		if v, err = convertDecimalArg(v); err != nil {
+			return err
+		}
+	}
+
 	// special cases
 	switch v := v.(type) {
 	case io.Reader:
@@ -64,8 +107,10 @@ func convertNamedValue(pr *p.PrepareResult, nv *driver.NamedValue) error {
 		}
 	}
 
+	var in interface{}
 	if out {
-		_, err = converter.Convert(v) // check field only
+		in, err = converter.Convert(v) // check field
+		v = dest                       // keep destination for output parameter assignment
 	} else {
 		v, err = converter.Convert(v) // convert field
 	}
@@ -73,10 +118,84 @@ func convertNamedValue(pr *p.PrepareResult, nv *driver.NamedValue) error {
 		return err
 	}
 
+	if !out {
+		if err := p.CheckLength(f, v); err != nil { // avoid silent truncation
+			return err
+		}
+	}
+
+	if out && f.In() { // in- and output parameter (INOUT): input value of sql.Out.In, NULL otherwise
+		if !nv.Value.(sql.Out).In {
+			in = nil
+		} else if err := p.CheckLength(f, in); err != nil {
+			return err
+		}
+		v = p.InOutValue{In: in, Dest: dest}
+	}
+
 	nv.Value = v
 	return nil
 }
 
+// checkScalarArg returns an error in case a slice or array value (except a byte slice) is bound
+// to a parameter, which usually is the attempt to execute a statement for multiple rows.
+func checkScalarArg(v interface{}) error {
+	if v == nil {
+		return nil
+	}
+	rt := reflect.TypeOf(v)
+	switch rt.Kind() {
+	case reflect.Slice, reflect.Array:
+		if rt.Elem().Kind() == reflect.Uint8 { // byte slices and arrays
+			return nil
+		}
+		return fmt.Errorf("cannot bind %T to scalar parameter - use bulk execution (ExecBatch or NoFlush) to execute a statement for multiple rows", v)
+	}
+	return nil
+}
+
+// checkStrictArg returns an error in case the go type of an argument does not match the parameter data type
+// or the value would change by the conversion (strict conversion mode).
+func checkStrictArg(dt p.DataType, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() { // nil
+		return nil
+	}
+
+	switch dt {
+	case p.DtTinyint, p.DtSmallint, p.DtInteger, p.DtBigint:
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return nil
+		}
+	case p.DtReal:
+		switch rv.Kind() {
+		case reflect.Float32:
+			return nil
+		case reflect.Float64:
+			if f := rv.Float(); float64(float32(f)) != f && !math.IsNaN(f) {
+				return fmt.Errorf("strict conversion: value %g loses precision as REAL", f)
+			}
+			return nil
+		}
+	case p.DtDouble:
+		switch rv.Kind() {
+		case reflect.Float32, reflect.Float64:
+			return nil
+		}
+	default:
+		return nil
+	}
+	return fmt.Errorf("strict conversion: cannot convert %T to %s", v, dt)
+}
+
 func normNamedValue(nv *driver.NamedValue) (interface{}, bool) {
 	if out, isOut := nv.Value.(sql.Out); isOut { // out parameter
 		return out.Dest, true // 'flatten' driver.NamedValue (remove sql.Out)
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"math"
+	"testing"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+func TestCheckScalarArg(t *testing.T) {
+	testData := []struct {
+		v  interface{}
+		ok bool
+	}{
+		{nil, true},
+		{int64(42), true},
+		{"Hello", true},
+		{[]byte("Hello"), true},
+		{[4]byte{1, 2, 3, 4}, true},
+		{[]int{1, 2, 3}, false},
+		{[]string{"a", "b"}, false},
+		{[2]int64{1, 2}, false},
+		{[]interface{}{1, "a"}, false},
+	}
+
+	for i, d := range testData {
+		err := checkScalarArg(d.v)
+		if d.ok && err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		if !d.ok && err == nil {
+			t.Fatalf("test %d: error expected for %T", i, d.v)
+		}
+	}
+}
+
+func TestCheckStrictArg(t *testing.T) {
+	i := int32(42)
+	var nilPtr *float64
+
+	testData := []struct {
+		dt p.DataType
+		v  interface{}
+		ok bool
+	}{
+		{p.DtInteger, nil, true},
+		{p.DtInteger, int64(42), true},
+		{p.DtInteger, uint8(42), true},
+		{p.DtInteger, &i, true},
+		{p.DtInteger, nilPtr, true},
+		{p.DtInteger, 42.0, false},
+		{p.DtInteger, "42", false},
+		{p.DtBigint, float32(42), false},
+		{p.DtReal, float32(1.5), true},
+		{p.DtReal, 1.5, true},
+		{p.DtReal, math.NaN(), true},
+		{p.DtReal, 0.1, false},
+		{p.DtReal, int64(1), false},
+		{p.DtDouble, 0.1, true},
+		{p.DtDouble, float32(0.1), true},
+		{p.DtDouble, int64(1), false},
+		{p.DtDouble, "0.1", false},
+		{p.DtString, 42, true},
+	}
+
+	for i, d := range testData {
+		err := checkStrictArg(d.dt, d.v)
+		if d.ok && err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		if !d.ok && err == nil {
+			t.Fatalf("test %d: error expected for %T", i, d.v)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+)
+
+// copyScanner keeps the driver value of a column as it is (no go type conversion)
+// so that it can be bound as statement argument without re-encoding.
+type copyScanner struct {
+	ct *sql.ColumnType
+	v  interface{}
+}
+
+// Scan implements the database/sql/Scanner interface.
+func (s *copyScanner) Scan(src interface{}) error {
+	if src == nil {
+		s.v = nil
+		return nil
+	}
+	if st := s.ct.ScanType(); st == lobReflectType || st == stPointReflectType { // lob values are only valid while reading the result set
+		b := new(bytes.Buffer)
+		if err := NewLob(nil, b).Scan(src); err != nil {
+			return err
+		}
+		s.v = b.Bytes()
+		return nil
+	}
+	if b, ok := src.([]byte); ok { // src might be reused by the driver
+		s.v = append([]byte(nil), b...)
+	} else {
+		s.v = src
+	}
+	return nil
+}
+
+/*
+CopyRows inserts all rows of a query result via a prepared statement (e.g. insert into <table> values (?, ...))
+as bulk execution and returns the number of inserted rows. The number of statement parameters needs to match
+the number of result columns.
+
+The column values are bound as statement arguments in the format they are returned by the driver (e.g. decimals
+in decimal128 format, character types as byte slices), so no conversion into go types (Scan) and back is needed. Lob values are read completely and bound as byte slices.
+
+As the rows are decoded by the hdb protocol on fetching a result set chunk, the driver does not expose
+the raw encoded rows (e.g. via io.WriterTo). CopyRows is the recommended way to copy the result of a query
+between database systems or tables.
+
+As the bulk arguments are buffered by the driver statement, the statement should be prepared
+on a dedicated connection (sql.Conn) or within a transaction (sql.Tx) (see ExecBatch).
+*/
+func CopyRows(ctx context.Context, stmt *sql.Stmt, rows *sql.Rows) (int64, error) {
+	cts, err := rows.ColumnTypes()
+	if err != nil {
+		return 0, err
+	}
+
+	scanners := make([]interface{}, len(cts))
+	for i, ct := range cts {
+		scanners[i] = &copyScanner{ct: ct}
+	}
+	args := make([]interface{}, len(cts)+1)
+	args[len(cts)] = NoFlush
+
+	numRow := 0
+	for rows.Next() {
+		if err := rows.Scan(scanners...); err != nil {
+			return 0, err
+		}
+		for i, s := range scanners {
+			args[i] = s.(*copyScanner).v
+		}
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			return 0, err
+		}
+		numRow++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if numRow == 0 {
+		return 0, nil
+	}
+
+	r, err := stmt.ExecContext(ctx, Flush)
+	if err != nil {
+		return 0, err
+	}
+	return r.RowsAffected()
+}
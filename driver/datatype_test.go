@@ -407,12 +407,36 @@ func TestDataType(t *testing.T) {
 		return equalTime(in.(time.Time).UTC(), out.(time.Time))
 	}
 
-	checkDateTime := func(in, out interface{}, fieldSize int, t *testing.T) bool {
+	// seconddate and daydate values must not have residual sub-precision fields
+	zeroNanosecond := func(t time.Time) bool { return t.Nanosecond() == 0 }
+	zeroClock := func(t time.Time) bool {
+		return t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0
+	}
+
+	checkSeconddate := func(in, out interface{}, fieldSize int, t *testing.T) bool {
+		if out, ok := out.(sql.NullTime); ok {
+			in := in.(sql.NullTime)
+			return in.Valid == out.Valid && (!in.Valid || (equalDateTime(in.Time.UTC(), out.Time) && zeroNanosecond(out.Time)))
+		}
+		return equalDateTime(in.(time.Time).UTC(), out.(time.Time)) && zeroNanosecond(out.(time.Time))
+	}
+
+	checkDaydate := func(in, out interface{}, fieldSize int, t *testing.T) bool {
 		if out, ok := out.(sql.NullTime); ok {
 			in := in.(sql.NullTime)
-			return in.Valid == out.Valid && (!in.Valid || equalDateTime(in.Time.UTC(), out.Time))
+			return in.Valid == out.Valid && (!in.Valid || (equalDate(in.Time.UTC(), out.Time) && zeroClock(out.Time)))
 		}
-		return equalDateTime(in.(time.Time).UTC(), out.(time.Time))
+		return equalDate(in.(time.Time).UTC(), out.(time.Time)) && zeroClock(out.(time.Time))
+	}
+
+	// secondtime values must not have fractional seconds
+	// (time values are transferred with millisecond precision, but the database returns whole seconds only)
+	checkSecondtime := func(in, out interface{}, fieldSize int, t *testing.T) bool {
+		if out, ok := out.(sql.NullTime); ok {
+			in := in.(sql.NullTime)
+			return in.Valid == out.Valid && (!in.Valid || (equalTime(in.Time.UTC(), out.Time) && zeroNanosecond(out.Time)))
+		}
+		return equalTime(in.(time.Time).UTC(), out.(time.Time)) && zeroNanosecond(out.(time.Time))
 	}
 
 	checkTimestamp := func(in, out interface{}, fieldSize int, t *testing.T) bool {
@@ -567,9 +591,9 @@ func TestDataType(t *testing.T) {
 		{"varbinary", 20, checkBytes, binaryTestData},
 		{"date", 0, checkDate, timeTestData},
 		{"time", 0, checkTime, timeTestData},
-		{"seconddate", 0, checkDateTime, timeTestData},
-		{"daydate", 0, checkDate, timeTestData},
-		{"secondtime", 0, checkTime, timeTestData},
+		{"seconddate", 0, checkSeconddate, timeTestData},
+		{"daydate", 0, checkDaydate, timeTestData},
+		{"secondtime", 0, checkSecondtime, timeTestData},
 		{"decimal", 0, checkDecimal, decimalTestData},
 		{"boolean", 0, checkBoolean, booleanTestData},
 		{"clob", 0, checkLob, lobTestData(true)},
@@ -622,3 +646,83 @@ func TestDataType(t *testing.T) {
 		})
 	}
 }
+
+func testDateRangeParams(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("dateRange_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s (d date)", table)); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		if _, err := db.Exec(fmt.Sprintf("insert into %s values (?)", table), start.AddDate(0, 0, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	query := fmt.Sprintf("select count(*) from %s where d between ? and add_days(?, ?)", table)
+
+	testData := []struct {
+		from  time.Time
+		days  interface{}
+		count int
+	}{
+		{start, 3, 4},
+		{start, int64(3), 4},
+		{start, int32(3), 4},
+		{start, uint8(3), 4},
+		{start, 3.0, 4}, // float without fraction
+		{start, "3", 4}, // numeric string
+		{start, 0, 1},
+		{start.AddDate(0, 0, 9), -2, 0},   // empty range
+		{start.Add(15 * time.Hour), 3, 3}, // time of day: 2020-01-01 15:00 > 2020-01-01
+		{time.Date(2020, 1, 5, 0, 0, 0, 0, time.UTC), 100, 6}, // open end
+	}
+
+	stmt, err := db.Prepare(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	for i, d := range testData {
+		var count int
+		if err := stmt.QueryRow(d.from, d.from, d.days).Scan(&count); err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		if count != d.count {
+			t.Fatalf("test %d: count %d - expected %d", i, count, d.count)
+		}
+	}
+
+	// day count must not be truncated silently
+	for i, days := range []interface{}{2.5, "2.5", "three"} {
+		var count int
+		if err := stmt.QueryRow(start, start, days).Scan(&count); err == nil {
+			t.Fatalf("test %d: error expected for day count %v (%[2]T)", i, days)
+		}
+	}
+}
+
+func TestDateRangeParams(t *testing.T) {
+	var testSet map[int]bool
+	if testing.Short() {
+		testSet = map[int]bool{DefaultDfv: true}
+	} else {
+		testSet = supportedDfvs
+	}
+
+	connector, err := NewDSNConnector(TestDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	connector.SetDefaultSchema(TestSchema)
+
+	for dfv := range testSet {
+		t.Run(fmt.Sprintf("dfv %d", dfv), func(t *testing.T) {
+			connector.SetDfv(dfv)
+			db := sql.OpenDB(connector)
+			defer db.Close()
+			testDateRangeParams(db, t)
+		})
+	}
+}
@@ -23,20 +23,14 @@ import (
 	"math"
 	"math/big"
 	"sync"
-)
 
-//bigint word size (*--> src/pkg/math/big/arith.go)
-const (
-	// Compute the size _S of a Word in bytes.
-	_m    = ^big.Word(0)
-	_logS = _m>>8&1 + _m>>16&1 + _m>>32&1
-	_S    = 1 << _logS
+	p "github.com/SAP/go-hdb/internal/protocol"
 )
 
 const (
 	// http://en.wikipedia.org/wiki/Decimal128_floating-point_format
 	dec128Digits = 34
-	dec128Bias   = 6176
+	dec128Bias   = p.Dec128Bias
 	dec128MinExp = -6176
 	dec128MaxExp = 6111
 )
@@ -94,6 +88,22 @@ type Decimal big.Rat
 // Scan implements the database/sql/Scanner interface.
 func (d *Decimal) Scan(src interface{}) error {
 
+	if f, ok := src.(float64); ok { // decimal float mode DecimalFloatRound
+		if (*big.Rat)(d).SetFloat64(f) == nil {
+			return fmt.Errorf("decimal: invalid float value %f", f)
+		}
+		return nil
+	}
+
+	if s, ok := src.(string); ok { // decimal float mode DecimalString
+		return d.SetString(s)
+	}
+
+	if x, ok := src.(*big.Rat); ok { // fixed decimal (FIXED8, FIXED12, FIXED16)
+		(*big.Rat)(d).Set(x)
+		return nil
+	}
+
 	b, ok := src.([]byte)
 	if !ok {
 		return fmt.Errorf("decimal: invalid data type %T", src)
@@ -159,6 +169,123 @@ func (d Decimal) Value() (driver.Value, error) {
 	return v, err
 }
 
+/*
+Text returns the decimal formatted with scale fractional digits. The last digit is rounded
+(half away from zero). Use Text to format decimals of a column with a known scale
+(see sql.ColumnType.DecimalSize).
+*/
+func (d *Decimal) Text(scale int) string {
+	if scale < 0 {
+		scale = 0
+	}
+	return (*big.Rat)(d).FloatString(scale)
+}
+
+// decimalStringEllipsis indicates a rounded non-terminating decimal string representation.
+const decimalStringEllipsis = "..."
+
+/*
+String implements the fmt.Stringer interface.
+
+As a big.Rat might not have a finite decimal representation (e.g. 1/3) String returns
+- the exact decimal representation if the fractional part is terminating (e.g. '0.125') or
+- the value rounded to dec128Digits (34) fractional digits followed by '...' otherwise
+  (e.g. '0.3333333333333333333333333333333333...').
+Use Text to format a decimal with a defined scale.
+*/
+func (d *Decimal) String() string {
+	if d == nil {
+		return "<nil>"
+	}
+	x := (*big.Rat)(d)
+	if scale, ok := terminatingScale(x.Denom()); ok {
+		return x.FloatString(scale)
+	}
+	return x.FloatString(dec128Digits) + decimalStringEllipsis
+}
+
+// Float64 returns the nearest float64 value of the decimal and a bool indicating whether the float64
+// value represents the decimal exactly.
+func (d *Decimal) Float64() (float64, bool) {
+	return (*big.Rat)(d).Float64()
+}
+
+/*
+SetString sets the decimal to the value of a decimal literal and returns an error if s is not a valid
+decimal literal. A decimal literal consists of an optional sign, the integer and / or fractional digits
+separated by a decimal point and an optional exponent (e.g. '47.11', '-.5', '1.5e-3', '4711E2').
+*/
+func (d *Decimal) SetString(s string) error {
+	if !isDecimalLiteral(s) {
+		return fmt.Errorf("decimal: invalid decimal literal %q", s)
+	}
+	if _, ok := (*big.Rat)(d).SetString(s); !ok {
+		return fmt.Errorf("decimal: invalid decimal literal %q", s)
+	}
+	return nil
+}
+
+// isDecimalLiteral returns true if s is a decimal literal [+-](digits[.digits]|.digits)[(e|E)[+-]digits].
+func isDecimalLiteral(s string) bool {
+	digits := func() int {
+		i := 0
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		s = s[i:]
+		return i
+	}
+	sign := func() {
+		if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+			s = s[1:]
+		}
+	}
+
+	sign()
+	n := digits()
+	if len(s) > 0 && s[0] == '.' {
+		s = s[1:]
+		n += digits()
+	}
+	if n == 0 {
+		return false
+	}
+	if len(s) > 0 && (s[0] == 'e' || s[0] == 'E') {
+		s = s[1:]
+		sign()
+		if digits() == 0 {
+			return false
+		}
+	}
+	return len(s) == 0
+}
+
+/*
+terminatingScale returns the number of fractional digits needed to represent a rational
+with denominator q exactly and true, or false if the decimal representation is non-terminating
+(q has prime factors other than 2 and 5).
+*/
+func terminatingScale(q *big.Int) (int, bool) {
+	q = new(big.Int).Set(q)
+	m, r := new(big.Int), new(big.Int)
+	count := func(f *big.Int) int {
+		n := 0
+		for {
+			m.QuoRem(q, f, r)
+			if r.Sign() != 0 {
+				return n
+			}
+			q.Set(m)
+			n++
+		}
+	}
+	n2, n5 := count(big.NewInt(2)), count(big.NewInt(5))
+	if q.Cmp(natOne) != 0 {
+		return 0, false
+	}
+	return max(n2, n5), true
+}
+
 func convertRatToDecimal(x *big.Rat, m *big.Int, digits, minExp, maxExp int) (bool, int, decFlags) {
 
 	neg := x.Sign() < 0 //store sign
@@ -287,41 +414,15 @@ func digits10(p *big.Int) int {
 	}
 }
 
-func decodeDecimal(b []byte, m *big.Int) (bool, int) {
-
-	neg := (b[15] & 0x80) != 0
-	exp := int((((uint16(b[15])<<8)|uint16(b[14]))<<1)>>2) - dec128Bias
+// decodeDecimal decodes a value in decimal128 format into the unsigned mantissa m and returns the sign and the exponent.
+var decodeDecimal = p.DecodeDecimal
 
-	b14 := b[14]  // save b[14]
-	b[14] &= 0x01 // keep the mantissa bit (rest: sign and exp)
-
-	//most significand byte
-	msb := 14
-	for msb > 0 {
-		if b[msb] != 0 {
-			break
-		}
-		msb--
-	}
-
-	//calc number of words
-	numWords := (msb / _S) + 1
-	w := make([]big.Word, numWords)
-
-	k := numWords - 1
-	d := big.Word(0)
-	for i := msb; i >= 0; i-- {
-		d |= big.Word(b[i])
-		if k*_S == i {
-			w[k] = d
-			k--
-			d = 0
-		}
-		d <<= 8
+// decimalScale returns the number of fractional digits of a decimal128 value as transferred by the database.
+func decimalScale(b []byte) int {
+	if exp := p.DecimalExp(b); exp < 0 {
+		return -exp
 	}
-	b[14] = b14 // restore b[14]
-	m.SetBits(w)
-	return neg, exp
+	return 0
 }
 
 func encodeDecimal(m *big.Int, neg bool, exp int) (driver.Value, error) {
@@ -349,6 +450,44 @@ func encodeDecimal(m *big.Int, neg bool, exp int) (driver.Value, error) {
 	return b, nil
 }
 
+/*
+convertDecimalArg converts decimal statement arguments given as string (e.g. "123456789012345678901234567890"
+or "1234.5678"), big.Int or int64 into the decimal field format without going through float64.
+As the values are converted exactly, an error is returned if a value exceeds the number of significant
+digits of the decimal field format (34) instead of rounding the value.
+Values of other types are returned unchanged.
+*/
+func convertDecimalArg(v interface{}) (interface{}, error) {
+	x := new(big.Rat)
+	switch v := v.(type) {
+	case string:
+		if _, ok := x.SetString(v); !ok {
+			return nil, fmt.Errorf("decimal: invalid value %s", v)
+		}
+	case *big.Int:
+		if v == nil {
+			return nil, nil
+		}
+		x.SetInt(v)
+	case big.Int:
+		x.SetInt(&v)
+	case int64:
+		x.SetInt64(v)
+	default:
+		return v, nil
+	}
+
+	m := new(big.Int)
+	neg, exp, df := convertRatToDecimal(x, m, dec128Digits, dec128MinExp, dec128MaxExp)
+	switch {
+	case df&dfOverflow != 0:
+		return nil, ErrDecimalOutOfRange
+	case df&(dfNotExact|dfUnderflow) != 0:
+		return nil, fmt.Errorf("decimal: value %s exceeds %d significant digits", x.RatString(), dec128Digits)
+	}
+	return encodeDecimal(m, neg, exp)
+}
+
 // NullDecimal represents an Decimal that may be null.
 // NullDecimal implements the Scanner interface so
 // it can be used as a scan destination, similar to NullString.
@@ -359,16 +498,17 @@ type NullDecimal struct {
 
 // Scan implements the Scanner interface.
 func (n *NullDecimal) Scan(value interface{}) error {
-	var b []byte
-
-	b, n.Valid = value.([]byte)
-	if !n.Valid {
+	switch value.(type) {
+	case []byte, float64:
+		n.Valid = true
+	default:
+		n.Valid = false
 		return nil
 	}
 	if n.Decimal == nil {
 		return fmt.Errorf("invalid decimal value %v", n.Decimal)
 	}
-	return n.Decimal.Scan(b)
+	return n.Decimal.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
@@ -381,3 +521,78 @@ func (n NullDecimal) Value() (driver.Value, error) {
 	}
 	return n.Decimal.Value()
 }
+
+/*
+A DecimalArray is the driver representation of a database decimal digit array value
+(e.g. the result of an ARRAY_AGG aggregation over a decimal column).
+
+Decimals contains the element values and Scales the number of fractional digits of each
+element as transferred by the database (the scale is not part of the big.Rat representation).
+
+As sql.Rows.Scan does not support scanning into slices of values, DecimalArray provides
+the conversion methods Float64s and Strings:
+- Float64s returns the nearest float64 value for each element (see big.Rat.Float64).
+- Strings formats each element with its scale (e.g. '1.50').
+*/
+type DecimalArray struct {
+	Decimals []Decimal
+	Scales   []int
+}
+
+// Scan implements the database/sql/Scanner interface.
+func (a *DecimalArray) Scan(src interface{}) error {
+	b, ok := src.([]byte)
+	if !ok {
+		return fmt.Errorf("decimal array: invalid data type %T", src)
+	}
+	if len(b)%decimalSize != 0 {
+		return fmt.Errorf("decimal array: invalid size %d - multiple of %d expected", len(b), decimalSize)
+	}
+
+	n := len(b) / decimalSize
+	a.Decimals = make([]Decimal, n)
+	a.Scales = make([]int, n)
+	for i := 0; i < n; i++ {
+		e := b[i*decimalSize : (i+1)*decimalSize]
+		if err := a.Decimals[i].Scan(e); err != nil {
+			return err
+		}
+		a.Scales[i] = decimalScale(e)
+	}
+	return nil
+}
+
+// Value implements the database/sql/Valuer interface.
+func (a DecimalArray) Value() (driver.Value, error) {
+	b := make([]byte, 0, len(a.Decimals)*decimalSize)
+	for _, d := range a.Decimals {
+		v, err := d.Value()
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, v.([]byte)...)
+	}
+	return b, nil
+}
+
+// Float64s returns the elements of the decimal array as float64 values.
+func (a DecimalArray) Float64s() []float64 {
+	f := make([]float64, len(a.Decimals))
+	for i := range a.Decimals {
+		f[i], _ = (*big.Rat)(&a.Decimals[i]).Float64()
+	}
+	return f
+}
+
+// Strings returns the elements of the decimal array formatted with their scale.
+func (a DecimalArray) Strings() []string {
+	s := make([]string, len(a.Decimals))
+	for i := range a.Decimals {
+		scale := 0
+		if i < len(a.Scales) {
+			scale = a.Scales[i]
+		}
+		s[i] = (*big.Rat)(&a.Decimals[i]).FloatString(scale)
+	}
+	return s
+}
@@ -110,6 +110,235 @@ func testConvertRat(t *testing.T) {
 	}
 }
 
+func testDecimalArray(t *testing.T) {
+	testData := []struct {
+		m       int64
+		exp     int
+		str     string
+		float64 float64
+	}{
+		{150, -2, "1.50", 1.5},
+		{-3, 0, "-3", -3},
+		{1, 2, "100", 100},
+		{12345, -5, "0.12345", 0.12345},
+	}
+
+	var b []byte
+	for _, d := range testData {
+		m := big.NewInt(d.m)
+		v, err := encodeDecimal(m.Abs(m), d.m < 0, d.exp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b = append(b, v.([]byte)...)
+	}
+
+	var a DecimalArray
+	if err := a.Scan(b); err != nil {
+		t.Fatal(err)
+	}
+	strs := a.Strings()
+	floats := a.Float64s()
+	if len(strs) != len(testData) || len(floats) != len(testData) {
+		t.Fatalf("decimal array size %d - expected %d", len(strs), len(testData))
+	}
+	for i, d := range testData {
+		if strs[i] != d.str {
+			t.Fatalf("value %d string %s - expected %s", i, strs[i], d.str)
+		}
+		if floats[i] != d.float64 {
+			t.Fatalf("value %d float64 %f - expected %f", i, floats[i], d.float64)
+		}
+	}
+
+	if err := a.Scan(b[:len(b)-1]); err == nil {
+		t.Fatal("invalid size error expected")
+	}
+}
+
+func testDecimalString(t *testing.T) {
+	testData := []struct {
+		x      *big.Rat
+		s      string
+		scale  int
+		scaled string
+	}{
+		{big.NewRat(0, 1), "0", 2, "0.00"},
+		{big.NewRat(1, 8), "0.125", 2, "0.13"},
+		{big.NewRat(-4711, 100), "-47.11", 1, "-47.1"},
+		{big.NewRat(1200, 1), "1200", 0, "1200"},
+		{big.NewRat(1, 3), "0.3333333333333333333333333333333333...", 4, "0.3333"},
+		{big.NewRat(-2, 3), "-0.6666666666666666666666666666666667...", 2, "-0.67"},
+	}
+
+	for i, d := range testData {
+		dec := (*Decimal)(d.x)
+		if s := dec.String(); s != d.s {
+			t.Fatalf("test %d: string %s - expected %s", i, s, d.s)
+		}
+		if s := dec.Text(d.scale); s != d.scaled {
+			t.Fatalf("test %d: text %s - expected %s", i, s, d.scaled)
+		}
+	}
+}
+
+func testDecimalFloat64(t *testing.T) {
+	testData := []struct {
+		x     *big.Rat
+		f     float64
+		exact bool
+	}{
+		{big.NewRat(0, 1), 0, true},
+		{big.NewRat(1, 8), 0.125, true},
+		{big.NewRat(-4711, 100), -47.11, false},
+		{big.NewRat(1200, 1), 1200, true},
+		{big.NewRat(1, 3), 1.0 / 3, false},
+	}
+
+	for i, d := range testData {
+		f, exact := (*Decimal)(d.x).Float64()
+		if f != d.f || exact != d.exact {
+			t.Fatalf("test %d: value %v exact %t - expected %v exact %t", i, f, exact, d.f, d.exact)
+		}
+	}
+}
+
+func testDecimalSetString(t *testing.T) {
+	testData := []struct {
+		s string
+		x *big.Rat
+	}{
+		{"0", big.NewRat(0, 1)},
+		{"47.11", big.NewRat(4711, 100)},
+		{"-47.11", big.NewRat(-4711, 100)},
+		{"+1200", big.NewRat(1200, 1)},
+		{".5", big.NewRat(1, 2)},
+		{"5.", big.NewRat(5, 1)},
+		{"1.5e-3", big.NewRat(3, 2000)},
+		{"4711E2", big.NewRat(471100, 1)},
+		{"-1.25e+1", big.NewRat(-25, 2)},
+		{"1e0", big.NewRat(1, 1)},
+	}
+
+	for i, d := range testData {
+		dec := new(Decimal)
+		if err := dec.SetString(d.s); err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		if (*big.Rat)(dec).Cmp(d.x) != 0 {
+			t.Fatalf("test %d: value %s - expected %s", i, dec, (*Decimal)(d.x))
+		}
+	}
+
+	invalid := []string{"", "-", ".", "e3", "1e", "1e+", "1/3", "0x10", "1.2.3", "1_000", " 1", "inf", "NaN"}
+	for _, s := range invalid {
+		if err := new(Decimal).SetString(s); err == nil {
+			t.Fatalf("invalid decimal literal %q: error expected", s)
+		}
+	}
+}
+
+func testConvertDecimalArg(t *testing.T) {
+	n30, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	n34, _ := new(big.Int).SetString("1234567890123456789012345678901234", 10)
+	n38, _ := new(big.Int).SetString("12345678901234567890123456789012345678", 10)
+
+	testData := []struct {
+		v  interface{}
+		x  *big.Rat
+		ok bool
+	}{
+		{"123456789012345678901234567890", new(big.Rat).SetInt(n30), true},
+		{"-1234.5678", big.NewRat(-12345678, 10000), true},
+		{n34, new(big.Rat).SetInt(n34), true},
+		{*n34, new(big.Rat).SetInt(n34), true},
+		{n38, nil, false},          // exceeds significant digits
+		{n38.String(), nil, false}, // exceeds significant digits
+		{"invalid", nil, false},
+		{int64(-42), big.NewRat(-42, 1), true},
+	}
+
+	for i, d := range testData {
+		v, err := convertDecimalArg(d.v)
+		if !d.ok {
+			if err == nil {
+				t.Fatalf("test %d: error expected", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		var dec Decimal
+		if err := dec.Scan(v); err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		if (*big.Rat)(&dec).Cmp(d.x) != 0 {
+			t.Fatalf("test %d: value %s - expected %s", i, (*big.Rat)(&dec), d.x)
+		}
+	}
+
+	// other types are returned unchanged
+	if v, err := convertDecimalArg(float64(42)); err != nil || v != float64(42) {
+		t.Fatalf("value %v error %v - expected unchanged value", v, err)
+	}
+}
+
+func testSmallDecimal(t *testing.T) {
+	testData := []struct {
+		m     int64
+		exp   int
+		str   string
+		scale int
+	}{
+		{150, -2, "1.50", 2},
+		{-10, -2, "-0.10", 2},
+		{0, -3, "0.000", 3},
+		{-3, 0, "-3", 0},
+		{1, 2, "100", 0},
+		{123450000, -8, "1.23450000", 8},
+	}
+
+	for i, d := range testData {
+		m := big.NewInt(d.m)
+		v, err := encodeDecimal(m.Abs(m), d.m < 0, d.exp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sd SmallDecimal
+		if err := sd.Scan(v); err != nil {
+			t.Fatal(err)
+		}
+		if sd.Scale != d.scale || sd.String() != d.str {
+			t.Fatalf("test %d: value %s scale %d - expected %s scale %d", i, sd.String(), sd.Scale, d.str, d.scale)
+		}
+		// binding keeps the scale
+		v2, err := sd.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sd2 SmallDecimal
+		if err := sd2.Scan(v2); err != nil {
+			t.Fatal(err)
+		}
+		if sd2.String() != d.str {
+			t.Fatalf("test %d: value %s - expected %s", i, sd2.String(), d.str)
+		}
+	}
+
+	var sd SmallDecimal
+	if err := sd.Scan(nil); err == nil {
+		t.Fatal("error expected scanning NULL value")
+	}
+	var nsd NullSmallDecimal
+	if err := nsd.Scan(nil); err != nil || nsd.Valid {
+		t.Fatalf("valid %t error %v - expected NULL", nsd.Valid, err)
+	}
+	if v, err := nsd.Value(); err != nil || v != nil {
+		t.Fatalf("value %v error %v - expected nil", v, err)
+	}
+}
+
 func TestDecimal(t *testing.T) {
 	tests := []struct {
 		name string
@@ -118,6 +347,12 @@ func TestDecimal(t *testing.T) {
 		{"decimalInfo", testDecimalInfo},
 		{"digits10", testDigits10},
 		{"convertRat", testConvertRat},
+		{"decimalArray", testDecimalArray},
+		{"smallDecimal", testSmallDecimal},
+		{"decimalString", testDecimalString},
+		{"decimalFloat64", testDecimalFloat64},
+		{"decimalSetString", testDecimalSetString},
+		{"convertDecimalArg", testConvertDecimalArg},
 	}
 
 	for _, test := range tests {
@@ -14,5 +14,88 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package driver is a native Go SAP HANA driver implementation for the database/sql package.
+/*
+Package driver is a native Go SAP HANA driver implementation for the database/sql package.
+
+Scanning into custom types
+
+A type implementing the sql.Scanner interface receives the value in the canonical representation
+decoded by the driver, so that no precision is lost by an intermediate conversion (e.g. into a string):
+- integer types (TINYINT, SMALLINT, INTEGER, BIGINT): int64
+- floating point types (REAL, DOUBLE): float64
+- date and time types (DATE, TIME, SECONDDATE, TIMESTAMP, ...): time.Time
+- decimal types (DECIMAL, SMALLDECIMAL): []byte of size 16 in IEEE 754 decimal128 format (BID encoding),
+  which can be converted via Decimal.Scan (float64 in decimal float mode DecimalFloatRound,
+  string in decimal float mode DecimalString, *big.Rat for the fixed decimal types of data format version 8)
+- character types (VARCHAR, NVARCHAR, ALPHANUM, SHORTTEXT, ...): []byte (UTF-8)
+- binary types (BINARY, VARBINARY): []byte
+- lob and spatial types (BLOB, CLOB, NCLOB, TEXT, ST_GEOMETRY, ST_POINT): a lob value implementing
+  SetWriter(w io.Writer) error, which can be converted via Lob.Scan (or GeoJSON, Geometry and STPoint for spatial values)
+- NULL values: nil
+
+Slices ([]byte) are owned by the driver and might be reused after Scan returns, so a Scanner
+needs to copy a slice it keeps.
+
+Column scan types
+
+The scan type of a result column (see sql.ColumnType.ScanType) is the most specific go type of the column values,
+so that a value of the scan type allocated via reflection (e.g. by an ORM) can be used as scan destination:
+- TINYINT: uint8, SMALLINT: int16, INTEGER: int32, BIGINT: int64
+- REAL: float32, DOUBLE: float64
+- date and time types: time.Time
+- decimal types: Decimal (and DecimalArray for decimal digit arrays)
+- character types: string, binary types: []byte
+- lob and spatial types: Lob (and STPoint for ST_POINT)
+As BOOLEAN values are transferred as TINYINT by the supported data format versions, the scan type
+of BOOLEAN columns is uint8 (see Boolean values).
+
+Scanning integers into bool
+
+Boolean values stored as integers (e.g. TINYINT columns in schemas predating the BOOLEAN data type)
+can be scanned into *bool, where database/sql converts 0 into false, 1 into true and returns an error
+for any other value (strict rule). To scan any non-zero value as true (lenient rule) please use IntBool
+as scan destination.
+
+Boolean values
+
+BOOLEAN values are transferred as integers (TINYINT), so that besides *bool and *sql.NullBool a BOOLEAN
+column can be scanned into integer destinations (e.g. *int64, *int) as 0 or 1 and into *string as "0" or "1".
+As parameter a BOOLEAN field accepts bool values, the integers 0 and 1 and the strings "true", "false"
+(case insensitive), "1" and "0". As the database reports BOOLEAN parameters with the TINYINT type code,
+the driver cannot tell both apart: a plain TINYINT parameter accepts the strings "true" and "false" as well
+(converted into 1 and 0).
+
+Time values
+
+time.Time parameter values are converted using the wall clock only, a monotonic clock reading
+(like the one of time.Now()) is stripped before the value is sent to the database.
+time.Time values returned by the driver never carry a monotonic clock reading, so round-tripped
+values compare equal (via time.Time.Equal) to the wall clock of the bound value within the
+precision of the database type.
+
+Executing a statement for multiple rows (column arrays)
+
+Besides bulk execution (see ExecBatch), a prepared statement can be executed for multiple rows by
+binding one column array per parameter holding the values of all rows (see NewColumnArray):
+
+	ids := []int64{1, 2, 3}
+	names := []string{"a", "b", "c"}
+	result, err := stmt.Exec(driver.NewColumnArray(ids), driver.NewColumnArray(names)) // insert into t values (?, ?)
+
+All arguments need to be column arrays of the same length. Plain slices and arrays (except byte slices)
+are rejected as parameter values to avoid executing a statement for multiple rows accidentally.
+The rows are sent to the database in as few requests as possible (see Connector.SetBulkSize and
+Connector.SetMaxBatchParams) and the result reports the aggregated number of affected rows.
+Column arrays are not supported by queries and procedure calls.
+
+Named parameters
+
+Besides positional parameters (?), statements can use named parameters (:name) bound by sql.Named arguments:
+
+	result, err := db.Exec("insert into t values (:id, :name)", sql.Named("name", "a"), sql.Named("id", 1))
+
+A named parameter can be used multiple times in a statement and is bound to all its occurrences. Named and
+positional parameters must not be mixed in a statement (ErrMixedParameters). As SQLScript uses the same
+syntax for variables, named parameters are not supported in CREATE, ALTER and DO statements.
+*/
 package driver
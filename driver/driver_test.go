@@ -17,11 +17,17 @@ limitations under the License.
 package driver
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"math/big"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func testConnection(db *sql.DB, t *testing.T) {
@@ -45,6 +51,26 @@ func testPing(db *sql.DB, t *testing.T) {
 	if err := db.PingContext(context.Background()); err != nil {
 		t.Fatal(err)
 	}
+
+	// ping detects a database session dropped by the database
+	sqlConn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlConn.Close()
+	var connectionID int64
+	if err := sqlConn.Raw(func(driverConn interface{}) error {
+		connectionID = driverConn.(*conn).session.ConnectionID()
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(fmt.Sprintf("alter system disconnect session '%d'", connectionID)); err != nil {
+		t.Fatal(err)
+	}
+	if err := sqlConn.PingContext(context.Background()); err != driver.ErrBadConn {
+		t.Fatalf("error %v - expected %v", err, driver.ErrBadConn)
+	}
 }
 
 func testInsertByQuery(db *sql.DB, t *testing.T) {
@@ -80,8 +106,14 @@ func testHDBError(db *sql.DB, t *testing.T) {
 		if !ok {
 			t.Fatalf("hdb error expected got %v", err)
 		}
-		if dbError.Code() != 259 {
-			t.Fatalf("hdb error code: %d - expected: %d", dbError.Code(), 259)
+		if dbError.Code() != HdbErrInvalidTableName {
+			t.Fatalf("hdb error code: %d - expected: %d", dbError.Code(), HdbErrInvalidTableName)
+		}
+		if !HasErrorCode(err, HdbErrInvalidTableName) {
+			t.Fatalf("hdb error code %d expected - got %s", HdbErrInvalidTableName, err)
+		}
+		if len(dbError.SQLState()) != 5 {
+			t.Fatalf("invalid sql state %q", dbError.SQLState())
 		}
 	}
 }
@@ -162,6 +194,29 @@ func testRowsAffected(db *sql.DB, t *testing.T) {
 		t.Fatal(err)
 	}
 	checkAffectedRows(t, result, maxRows)
+
+	// prepared update
+	result, err = db.Exec(fmt.Sprintf("update %s set i = ? where i = ?", table), 0, maxRows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkAffectedRows(t, result, maxRows)
+
+	// bulk update: rows affected of all rows summed up
+	updStmt, err := db.Prepare(fmt.Sprintf("update %s set i = ? where i = ?", table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer updStmt.Close()
+	result, err = ExecBatch(context.Background(), updStmt, [][]interface{}{{1, 0}, {2, 1}, {3, 42}}) // maxRows rows updated twice
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkAffectedRows(t, result, 2*maxRows)
+
+	if _, err := result.LastInsertId(); !errors.Is(err, ErrLastInsertIDNotSupported) {
+		t.Fatalf("error %v - expected %v", err, ErrLastInsertIDNotSupported)
+	}
 }
 
 func testUpsert(db *sql.DB, t *testing.T) {
@@ -202,6 +257,1211 @@ func testUpsert(db *sql.DB, t *testing.T) {
 
 }
 
+func testInvalidate(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("invalidate_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s (i tinyint)", table)); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	stmt, err := conn.PrepareContext(ctx, fmt.Sprintf("insert into %s values(?)", table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("alter table %s alter (i integer)", table)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.Raw(func(driverConn interface{}) error {
+		return driverConn.(Conn).Invalidate()
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// value exceeds tinyint range - needs integer parameter metadata
+	result, err := stmt.Exec(4711)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkAffectedRows(t, result, 1)
+}
+
+func testDescribe(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("describe_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s (i integer not null, s nvarchar(20), d decimal(10,2))", table)); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var params, columns []ColumnInfo
+	if err := conn.Raw(func(driverConn interface{}) error {
+		params, columns, err = driverConn.(Conn).Describe(ctx, fmt.Sprintf("select i, s, d from %s where i = ? and s = ?", table))
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(params) != 2 {
+		t.Fatalf("number of parameters %d - expected %d", len(params), 2)
+	}
+	if !params[0].In || params[0].DatabaseTypeName != "INTEGER" {
+		t.Fatalf("invalid parameter %v", params[0])
+	}
+	if len(columns) != 3 {
+		t.Fatalf("number of columns %d - expected %d", len(columns), 3)
+	}
+	if columns[0].Name != "I" || columns[0].Nullable {
+		t.Fatalf("invalid column %v", columns[0])
+	}
+	if columns[1].DatabaseTypeName != "NVARCHAR" || !columns[1].HasLength || columns[1].Length != 20 {
+		t.Fatalf("invalid column %v", columns[1])
+	}
+	if !columns[2].HasPrecision || columns[2].Precision != 10 || columns[2].Scale != 2 {
+		t.Fatalf("invalid column %v", columns[2])
+	}
+}
+
+func testColumnTypeLengthSemantics(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("lengthSemantics_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s (n nvarchar(20), v varchar(10), i integer)", table)); err != nil {
+		t.Fatal(err)
+	}
+	query := fmt.Sprintf("select n, v, i from %s", table)
+
+	expected := []struct {
+		chars  bool
+		length int64
+	}{{true, 20}, {false, 10}, {false, 0}}
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := conn.Raw(func(driverConn interface{}) error {
+		rows, err := driverConn.(driver.QueryerContext).QueryContext(ctx, query, nil)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		r, ok := rows.(RowsColumnTypeLengthSemantics)
+		if !ok {
+			return fmt.Errorf("rows %T do not implement RowsColumnTypeLengthSemantics", rows)
+		}
+		for i, e := range expected {
+			if chars, length := r.ColumnTypeLengthSemantics(i); chars != e.chars || length != e.length {
+				return fmt.Errorf("column %d: chars %t length %d - expected chars %t length %d", i, chars, length, e.chars, e.length)
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var infos []ResultSetInfo
+	rows, err := db.QueryContext(WithResultSetInfos(ctx, &infos), query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows.Close()
+	if len(infos) != 1 {
+		t.Fatalf("number of result set infos %d - expected %d", len(infos), 1)
+	}
+	for i, e := range expected {
+		if ci := infos[0].Types[i]; ci.LengthInChars != e.chars || ci.Length != e.length {
+			t.Fatalf("column %d: chars %t length %d - expected chars %t length %d", i, ci.LengthInChars, ci.Length, e.chars, e.length)
+		}
+	}
+}
+
+func testQueryScalar(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("queryScalar_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s (i integer, s nvarchar(20))", table)); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := db.Exec(fmt.Sprintf("insert into %s values (?, ?)", table), i, fmt.Sprintf("row %d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := context.Background()
+
+	var cnt int64
+	if err := QueryScalar(ctx, db, &cnt, fmt.Sprintf("select count(*) from %s", table)); err != nil {
+		t.Fatal(err)
+	}
+	if cnt != 3 {
+		t.Fatalf("count %d - expected %d", cnt, 3)
+	}
+
+	var s string
+	if err := QueryScalar(ctx, db, &s, fmt.Sprintf("select s from %s where i = ?", table), 1); err != nil {
+		t.Fatal(err)
+	}
+	if s != "row 1" {
+		t.Fatalf("value %s - expected %s", s, "row 1")
+	}
+
+	if err := QueryScalar(ctx, db, &s, fmt.Sprintf("select s from %s where i = ?", table), 42); err != sql.ErrNoRows {
+		t.Fatalf("error %v - expected %v", err, sql.ErrNoRows)
+	}
+	if err := QueryScalar(ctx, db, &s, fmt.Sprintf("select s from %s", table)); err != ErrScalarMultipleRows {
+		t.Fatalf("error %v - expected %v", err, ErrScalarMultipleRows)
+	}
+	if err := QueryScalar(ctx, db, &s, fmt.Sprintf("select i, s from %s where i = ?", table), 1); err == nil {
+		t.Fatal("error expected")
+	}
+}
+
+// testScanPointer tests scanning into pointer variables (**T), where NULL values are scanned as nil pointers
+// (supported by database/sql for all scan types including the driver types like Decimal).
+func testScanPointer(db *sql.DB, t *testing.T) {
+	var (
+		s  *string
+		i  *int64
+		d  *Decimal
+		ts *time.Time
+	)
+
+	if err := db.QueryRow("select 'Hello', 42, to_decimal(47.11, 10, 2), to_timestamp('2020-01-01 12:00:00') from dummy").Scan(&s, &i, &d, &ts); err != nil {
+		t.Fatal(err)
+	}
+	if s == nil || *s != "Hello" {
+		t.Fatalf("string %v - expected %s", s, "Hello")
+	}
+	if i == nil || *i != 42 {
+		t.Fatalf("integer %v - expected %d", i, 42)
+	}
+	if d == nil || (*big.Rat)(d).Cmp(big.NewRat(4711, 100)) != 0 {
+		t.Fatalf("decimal %v - expected %s", d, "47.11")
+	}
+	if ts == nil || !ts.Equal(time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Fatalf("timestamp %v - expected %s", ts, time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC))
+	}
+
+	if err := db.QueryRow("select cast(null as nvarchar(10)), cast(null as integer), cast(null as decimal(10,2)), cast(null as timestamp) from dummy").Scan(&s, &i, &d, &ts); err != nil {
+		t.Fatal(err)
+	}
+	if s != nil || i != nil || d != nil || ts != nil {
+		t.Fatalf("nil pointers expected - got %v %v %v %v", s, i, d, ts)
+	}
+}
+
+func testWithSchema(db *sql.DB, t *testing.T) {
+	schema := RandomIdentifier(TestGoHDBSchemaPrefix)
+	table := RandomIdentifier("withSchema_")
+
+	if _, err := db.Exec(fmt.Sprintf("create schema %s", schema)); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec(fmt.Sprintf("drop schema %s cascade", schema))
+	if _, err := db.Exec(fmt.Sprintf("create table %s.%s (i integer)", schema, table)); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	schemaCtx := WithSchema(ctx, schema)
+
+	// direct execution and query
+	if _, err := conn.ExecContext(schemaCtx, fmt.Sprintf("insert into %s values (1)", table)); err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	if err := conn.QueryRowContext(schemaCtx, fmt.Sprintf("select count(*) from %s", table)).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("number of rows %d - expected %d", n, 1)
+	}
+	// prepared statement
+	stmt, err := conn.PrepareContext(schemaCtx, fmt.Sprintf("insert into %s values (?)", table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+	if _, err := stmt.Exec(2); err != nil {
+		t.Fatal(err)
+	}
+
+	// session schema is not changed
+	var currentSchema string
+	if err := conn.QueryRowContext(ctx, "select current_schema from dummy").Scan(&currentSchema); err != nil {
+		t.Fatal(err)
+	}
+	if currentSchema != string(TestSchema) {
+		t.Fatalf("current schema %s - expected %s", currentSchema, TestSchema)
+	}
+	if _, err := conn.QueryContext(ctx, fmt.Sprintf("select * from %s", table)); err == nil {
+		t.Fatalf("table %s in current schema %s: error expected", table, currentSchema)
+	}
+}
+
+func testSTPointColumn(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("stPoint_")
+	if _, err := db.Exec(fmt.Sprintf("create column table %s (pt st_point)", table)); err != nil {
+		t.Fatal(err)
+	}
+	in := STPoint{X: 1.5, Y: -2.5}
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (?)", table), in); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (?)", table), NullSTPoint{}); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query(fmt.Sprintf("select pt from %s order by pt.st_x() nulls last", table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	cts, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scanType := cts[0].ScanType(); scanType != reflect.TypeOf(STPoint{}) {
+		t.Fatalf("scan type %s - expected %s", scanType, reflect.TypeOf(STPoint{}))
+	}
+
+	var out []NullSTPoint
+	for rows.Next() {
+		var pt NullSTPoint
+		if err := rows.Scan(&pt); err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, pt)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 2 || !out[0].Valid || out[0].STPoint.X != in.X || out[0].STPoint.Y != in.Y || out[1].Valid {
+		t.Fatalf("points %v - expected %v and NULL", out, in)
+	}
+}
+
+func testGeometryColumn(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("geometry_")
+	if _, err := db.Exec(fmt.Sprintf("create column table %s (id integer, g st_geometry)", table)); err != nil {
+		t.Fatal(err)
+	}
+	testData := []string{
+		"POINT (1 2)",
+		"LINESTRING (0 0, 1 1, 2 0)",
+		"MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)), ((2 2, 3 2, 3 3, 2 2)))",
+		"GEOMETRYCOLLECTION (POINT (1 2), LINESTRING (0 0, 1 1), POLYGON ((0 0, 1 0, 1 1, 0 0)))",
+	}
+	for i, wkt := range testData {
+		if _, err := db.Exec(fmt.Sprintf("insert into %s values (?, ?)", table), i, Geometry(wkt)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (?, ?)", table), len(testData), Geometry(nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query(fmt.Sprintf("select g from %s order by id", table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	i := 0
+	for rows.Next() {
+		var g Geometry
+		if err := rows.Scan(&g); err != nil {
+			t.Fatal(err)
+		}
+		if i == len(testData) {
+			if g != nil {
+				t.Fatalf("geometry %x - expected nil", g)
+			}
+		} else {
+			wkt, err := g.WKT()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if wkt != testData[i] {
+				t.Fatalf("geometry %s - expected %s", wkt, testData[i])
+			}
+		}
+		i++
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if i != len(testData)+1 {
+		t.Fatalf("number of rows %d - expected %d", i, len(testData)+1)
+	}
+}
+
+func testSmallDecimalColumn(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("smallDecimal_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s (sd smalldecimal, d decimal(10, 2))", table)); err != nil {
+		t.Fatal(err)
+	}
+	in := SmallDecimal{Decimal: Decimal(*big.NewRat(3, 2)), Scale: 2}
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (?, ?)", table), in, in); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (?, ?)", table), NullSmallDecimal{}, NullSmallDecimal{}); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query(fmt.Sprintf("select sd, d from %s order by sd nulls last", table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var out []NullSmallDecimal
+	for rows.Next() {
+		var sd, d NullSmallDecimal
+		if err := rows.Scan(&sd, &d); err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, sd, d)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 4 || out[2].Valid || out[3].Valid {
+		t.Fatalf("values %v - expected 2 rows with NULL values in second row", out)
+	}
+	for _, v := range out[:2] {
+		if !v.Valid || v.SmallDecimal.String() != "1.50" {
+			t.Fatalf("value %s - expected %s", v.SmallDecimal.String(), "1.50")
+		}
+	}
+}
+
+func testAlphanumPadding(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("alphanumPadding_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s (a alphanum(10))", table)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (?)", table), Alphanum("123")); err != nil {
+		t.Fatal(err)
+	}
+
+	// stored form independent of data format version
+	var a Alphanum
+	if err := db.QueryRowContext(WithAlphanumPadding(context.Background()), fmt.Sprintf("select a from %s", table)).Scan(&a); err != nil {
+		t.Fatal(err)
+	}
+	if a != "0000000123" {
+		t.Fatalf("value %s - expected %s", a, "0000000123")
+	}
+	if a.Trimmed() != "123" {
+		t.Fatalf("trimmed value %s - expected %s", a.Trimmed(), "123")
+	}
+}
+
+func testRawCESU8Column(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("rawCESU8_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s (i integer, s nvarchar(10))", table)); err != nil {
+		t.Fatal(err)
+	}
+
+	const text = "Ä€\U0001F600" // character outside the BMP: surrogate pair in CESU-8
+	raw := RawCESU8{0xc3, 0x84, 0xe2, 0x82, 0xac, 0xed, 0xa0, 0xbd, 0xed, 0xb8, 0x80}
+
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (?, ?)", table), 1, raw); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (?, ?)", table), 2, RawCESU8(nil)); err != nil {
+		t.Fatal(err)
+	}
+	// length check in characters (UTF-16 code units)
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (?, ?)", table), 3, RawCESU8(bytes.Repeat(raw, 3))); err == nil {
+		t.Fatal("value length error expected")
+	}
+
+	var s string
+	if err := db.QueryRow(fmt.Sprintf("select s from %s where i = 1", table)).Scan(&s); err != nil {
+		t.Fatal(err)
+	}
+	if s != text {
+		t.Fatalf("value %q - expected %q", s, text)
+	}
+
+	var out RawCESU8
+	// character data transformed into UTF-8
+	if err := db.QueryRow(fmt.Sprintf("select s from %s where i = 1", table)).Scan(&out); err == nil {
+		t.Fatal("raw CESU-8 scan error expected")
+	}
+
+	ctx := WithRawCESU8(context.Background())
+	if err := db.QueryRowContext(ctx, fmt.Sprintf("select s from %s where i = 1", table)).Scan(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out, raw) {
+		t.Fatalf("value %x - expected %x", out, raw)
+	}
+	var b []byte
+	if err := db.QueryRowContext(ctx, fmt.Sprintf("select s from %s where i = 1", table)).Scan(&b); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, raw) {
+		t.Fatalf("value %x - expected %x", b, raw)
+	}
+	if err := db.QueryRowContext(ctx, fmt.Sprintf("select s from %s where i = 2", table)).Scan(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out != nil {
+		t.Fatalf("value %x - expected nil", out)
+	}
+}
+
+func testStringLength(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("stringLength_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s (v varchar(4), n nvarchar(4))", table)); err != nil {
+		t.Fatal(err)
+	}
+
+	testData := []struct {
+		s           string
+		varcharLen  int
+		nvarcharLen int
+	}{
+		{"aaaa", 4, 4},
+		{"€", 3, 1},
+		{"𝄞", 4, 2},          // surrogate pair
+		{"𝄞𝄞", 8, 4},         // 2 UTF-8 characters, but 4 UTF-16 code units
+		{"𝄞𝄞a", 9, 5},        // 3 UTF-8 characters, but CESU-8 length 5
+		{"\U0001F600", 4, 2}, // surrogate pair
+	}
+
+	for i, d := range testData {
+		if l := VarcharLen(d.s); l != d.varcharLen {
+			t.Fatalf("test %d: varchar length %d - expected %d", i, l, d.varcharLen)
+		}
+		if l := NvarcharLen(d.s); l != d.nvarcharLen {
+			t.Fatalf("test %d: nvarchar length %d - expected %d", i, l, d.nvarcharLen)
+		}
+
+		_, err := db.Exec(fmt.Sprintf("insert into %s (v) values (?)", table), d.s)
+		if ok := d.varcharLen <= 4; ok != (err == nil) {
+			t.Fatalf("test %d: varchar length %d - error %v", i, d.varcharLen, err)
+		}
+		_, err = db.Exec(fmt.Sprintf("insert into %s (n) values (?)", table), d.s)
+		if ok := d.nvarcharLen <= 4; ok != (err == nil) {
+			t.Fatalf("test %d: nvarchar length %d - error %v", i, d.nvarcharLen, err)
+		}
+		if err != nil && !strings.Contains(err.Error(), fmt.Sprintf("CESU-8 length %d", d.nvarcharLen)) {
+			t.Fatalf("test %d: unexpected error %s", i, err)
+		}
+	}
+}
+
+func testIntBoolColumn(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("intBool_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s (i integer, b tinyint)", table)); err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range []interface{}{0, 1, 2, nil, IntBool(true)} {
+		if _, err := db.Exec(fmt.Sprintf("insert into %s values (?, ?)", table), i, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	query := fmt.Sprintf("select b from %s where i = ?", table)
+
+	// strict rule: *bool
+	var b bool
+	for i, expected := range []bool{false, true} {
+		if err := db.QueryRow(query, i).Scan(&b); err != nil {
+			t.Fatal(err)
+		}
+		if b != expected {
+			t.Fatalf("value %t - expected %t", b, expected)
+		}
+	}
+	if err := db.QueryRow(query, 2).Scan(&b); err == nil {
+		t.Fatal("conversion error expected")
+	}
+
+	// lenient rule: IntBool
+	var ib IntBool
+	for i, expected := range []IntBool{false, true, true, false, true} {
+		if i == 3 { // NULL
+			continue
+		}
+		if err := db.QueryRow(query, i).Scan(&ib); err != nil {
+			t.Fatal(err)
+		}
+		if ib != expected {
+			t.Fatalf("row %d: value %t - expected %t", i, ib, expected)
+		}
+	}
+	if err := db.QueryRow(query, 3).Scan(&ib); err == nil {
+		t.Fatal("NULL conversion error expected")
+	}
+	var pib *IntBool
+	if err := db.QueryRow(query, 3).Scan(&pib); err != nil {
+		t.Fatal(err)
+	}
+	if pib != nil {
+		t.Fatalf("value %v - expected nil", pib)
+	}
+}
+
+func testBooleanColumn(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("boolean_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s (i integer, b boolean)", table)); err != nil {
+		t.Fatal(err)
+	}
+	values := []interface{}{true, false, 1, 0, "true", "FALSE", "1", "0"}
+	for i, v := range values {
+		if _, err := db.Exec(fmt.Sprintf("insert into %s values (?, ?)", table), i, v); err != nil {
+			t.Fatalf("value %v: %s", v, err)
+		}
+	}
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (?, ?)", table), len(values), "yes"); err == nil {
+		t.Fatal("conversion error expected")
+	}
+
+	query := fmt.Sprintf("select b from %s where i = ?", table)
+
+	for i := range values {
+		expected := i%2 == 0
+
+		var b bool
+		if err := db.QueryRow(query, i).Scan(&b); err != nil {
+			t.Fatal(err)
+		}
+		if b != expected {
+			t.Fatalf("row %d: value %t - expected %t", i, b, expected)
+		}
+
+		var n int
+		if err := db.QueryRow(query, i).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		if (n == 1) != expected {
+			t.Fatalf("row %d: value %d - expected %t", i, n, expected)
+		}
+
+		var s string
+		if err := db.QueryRow(query, i).Scan(&s); err != nil {
+			t.Fatal(err)
+		}
+		if (s == "1") != expected {
+			t.Fatalf("row %d: value %s - expected %t", i, s, expected)
+		}
+	}
+}
+
+// srcTypeScanner records the source value handed to a sql.Scanner.
+type srcTypeScanner struct {
+	src interface{}
+}
+
+func (s *srcTypeScanner) Scan(src interface{}) error {
+	if b, ok := src.([]byte); ok {
+		src = append([]byte(nil), b...)
+	}
+	s.src = src
+	return nil
+}
+
+func testScannerSourceTypes(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("scannerSource_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s (i integer, d decimal(20, 4), ts timestamp, f double, s nvarchar(10))", table)); err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Date(2020, 2, 29, 12, 30, 45, 123456700, time.UTC)
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (?, ?, ?, ?, ?)", table), 42, "1234.5678", ts, 1.5, "Hello"); err != nil {
+		t.Fatal(err)
+	}
+
+	var i, d, tsv, f, str srcTypeScanner
+	if err := db.QueryRow(fmt.Sprintf("select * from %s", table)).Scan(&i, &d, &tsv, &f, &str); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, ok := i.src.(int64); !ok || v != 42 {
+		t.Fatalf("integer source %v (%T) - expected int64 %d", i.src, i.src, 42)
+	}
+	b, ok := d.src.([]byte)
+	if !ok || len(b) != 16 {
+		t.Fatalf("decimal source %v (%T) - expected decimal128 []byte", d.src, d.src)
+	}
+	var dec Decimal
+	if err := dec.Scan(b); err != nil {
+		t.Fatal(err)
+	}
+	if r := (*big.Rat)(&dec); r.Cmp(big.NewRat(12345678, 10000)) != 0 {
+		t.Fatalf("decimal %s - expected %s", r.FloatString(4), "1234.5678")
+	}
+	if v, ok := tsv.src.(time.Time); !ok || !v.Equal(ts) {
+		t.Fatalf("timestamp source %v (%T) - expected time.Time %s", tsv.src, tsv.src, ts)
+	}
+	if v, ok := f.src.(float64); !ok || v != 1.5 {
+		t.Fatalf("double source %v (%T) - expected float64 %f", f.src, f.src, 1.5)
+	}
+	if v, ok := str.src.([]byte); !ok || string(v) != "Hello" {
+		t.Fatalf("nvarchar source %v (%T) - expected []byte %s", str.src, str.src, "Hello")
+	}
+}
+
+func testExists(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("exists_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s (i integer)", table)); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := db.Exec(fmt.Sprintf("insert into %s values (?)", table), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := context.Background()
+	testData := []struct {
+		query  string
+		args   []interface{}
+		exists bool
+	}{
+		{fmt.Sprintf("select * from %s", table), nil, true},
+		{fmt.Sprintf("select * from %s where i = ?", table), []interface{}{1}, true},
+		{fmt.Sprintf("select * from %s where i = ?;", table), []interface{}{42}, false},
+	}
+
+	for i, d := range testData {
+		exists, err := Exists(ctx, db, d.query, d.args...)
+		if err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		if exists != d.exists {
+			t.Fatalf("test %d: exists %t - expected %t", i, exists, d.exists)
+		}
+	}
+}
+
+func testGrouping(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("grouping_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s (region nvarchar(10), product nvarchar(10), amount integer)", table)); err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range [][]interface{}{{"EU", "A", 1}, {"EU", nil, 2}, {"US", "A", 3}} {
+		if _, err := db.Exec(fmt.Sprintf("insert into %s values (?, ?, ?)", table), row...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rows, err := db.Query(fmt.Sprintf("select region, product, sum(amount), %s from %s group by rollup (region, product)", GroupingColumns("region", "product"), table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	numDetail, numAggregate := 0, 0
+	for rows.Next() {
+		var (
+			region, product                 sql.NullString
+			amount                          int64
+			regionGrouping, productGrouping Grouping
+		)
+		if err := rows.Scan(&region, &product, &amount, &regionGrouping, &productGrouping); err != nil {
+			t.Fatal(err)
+		}
+		if productGrouping {
+			if product.Valid {
+				t.Fatalf("grouping NULL expected for product %s", product.String)
+			}
+			numAggregate++
+		} else {
+			numDetail++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	// detail rows: (EU, A), (EU, NULL), (US, A) - aggregate rows: (EU), (US), grand total
+	if numDetail != 3 || numAggregate != 3 {
+		t.Fatalf("detail rows %d aggregate rows %d - expected %d %d", numDetail, numAggregate, 3, 3)
+	}
+}
+
+func testDecimalBigInt(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("decimalBigInt_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s (id decimal(38,0))", table)); err != nil {
+		t.Fatal(err)
+	}
+
+	const s = "1234567890123456789012345678901234" // maximal number of significant digits
+	i, _ := new(big.Int).SetString(s, 10)
+	i.Neg(i)
+
+	for _, arg := range []interface{}{s, i} {
+		if _, err := db.Exec(fmt.Sprintf("insert into %s values (?)", table), arg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 38 digits exceed the significant digits of the decimal transfer format
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (?)", table), "12345678901234567890123456789012345678"); err == nil {
+		t.Fatal("decimal error expected")
+	}
+
+	rows, err := db.Query(fmt.Sprintf("select id from %s order by id desc", table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var result []string
+	for rows.Next() {
+		var d Decimal
+		if err := rows.Scan(&d); err != nil {
+			t.Fatal(err)
+		}
+		result = append(result, d.String())
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 2 || result[0] != s || result[1] != "-"+s {
+		t.Fatalf("values %v - expected %s, -%s", result, s, s)
+	}
+}
+
+func testPipeline(db *sql.DB, t *testing.T) {
+	table1 := RandomIdentifier("pipeline1_")
+	table2 := RandomIdentifier("pipeline2_")
+	for _, table := range []Identifier{table1, table2} {
+		if _, err := db.Exec(fmt.Sprintf("create table %s (i integer primary key)", table)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := context.Background()
+
+	count := func(table Identifier) int {
+		var n int
+		if err := db.QueryRow(fmt.Sprintf("select count(*) from %s", table)).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	var pl Pipeline
+	pl.Queue(fmt.Sprintf("insert into %s values (?)", table1), 1)
+	pl.Queue(fmt.Sprintf("insert into %s values (?)", table1), 2)
+	pl.Queue(fmt.Sprintf("insert into %s values (?)", table2), 1)
+	pl.Queue(fmt.Sprintf("update %s set i = i + 10", table2))
+	if pl.Len() != 4 {
+		t.Fatalf("number of executions %d - expected %d", pl.Len(), 4)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := pl.Exec(ctx, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if pl.Len() != 0 {
+		t.Fatalf("number of executions %d - expected %d", pl.Len(), 0)
+	}
+	for i, expected := range []int64{2, 2, 1, 1} { // first two executions: bulk request
+		checkAffectedRows(t, results[i], expected)
+	}
+	if count(table1) != 2 || count(table2) != 1 {
+		t.Fatalf("number of rows %d %d - expected %d %d", count(table1), count(table2), 2, 1)
+	}
+
+	// duplicate key: all-or-nothing by rollback
+	pl.Queue(fmt.Sprintf("insert into %s values (?)", table2), 2)
+	pl.Queue(fmt.Sprintf("insert into %s values (?)", table1), 1)
+	tx, err = db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pl.Exec(ctx, tx); err == nil {
+		t.Fatal("unique constraint error expected")
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if count(table2) != 1 {
+		t.Fatalf("number of rows %d - expected %d", count(table2), 1)
+	}
+}
+
+func testNamedParameters(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("namedParameters")
+	if _, err := db.Exec(fmt.Sprintf("create table %s (i integer, s nvarchar(20))", table)); err != nil {
+		t.Fatal(err)
+	}
+
+	// named parameters in different order than the arguments
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (:id, :name)", table), sql.Named("name", "one"), sql.Named("id", 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	// named parameter used multiple times
+	var s string
+	if err := db.QueryRow(fmt.Sprintf("select s from %s where i = :id and i >= :id", table), sql.Named("id", 1)).Scan(&s); err != nil {
+		t.Fatal(err)
+	}
+	if s != "one" {
+		t.Fatalf("value %s - expected %s", s, "one")
+	}
+
+	// named and positional arguments must not be mixed
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (:id, :name)", table), 2, sql.Named("name", "two")); !errors.Is(err, ErrMixedParameters) {
+		t.Fatalf("error %v - expected %v", err, ErrMixedParameters)
+	}
+	// named and positional parameters must not be mixed
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (?, :name)", table), sql.Named("name", "two")); !errors.Is(err, ErrMixedParameters) {
+		t.Fatalf("error %v - expected %v", err, ErrMixedParameters)
+	}
+	// missing named argument
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (:id, :name)", table), sql.Named("id", 2)); err == nil {
+		t.Fatal("error expected")
+	}
+}
+
+func testCopyRows(db *sql.DB, t *testing.T) {
+	src := RandomIdentifier("copyRowsSrc_")
+	dst := RandomIdentifier("copyRowsDst_")
+	for _, table := range []Identifier{src, dst} {
+		if _, err := db.Exec(fmt.Sprintf("create table %s (i integer, s nvarchar(20), d decimal(10,2), c nclob)", table)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	const numRow = 100
+	for i := 0; i < numRow; i++ {
+		if _, err := db.Exec(fmt.Sprintf("insert into %s values (?, ?, ?, ?)", src), i, fmt.Sprintf("Hello %d", i), (*Decimal)(big.NewRat(int64(i), 100)), "World"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(fmt.Sprintf("insert into %s values (?, ?, ?, ?)", dst))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	rows, err := db.Query(fmt.Sprintf("select * from %s", src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	n, err := CopyRows(context.Background(), stmt, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != numRow {
+		t.Fatalf("copied rows %d - expected %d", n, numRow)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	var numDiff int
+	if err := db.QueryRow(fmt.Sprintf("select count(*) from (select i, s, d from %s except select i, s, d from %s)", src, dst)).Scan(&numDiff); err != nil {
+		t.Fatal(err)
+	}
+	if numDiff != 0 {
+		t.Fatalf("different rows %d - expected 0", numDiff)
+	}
+}
+
+func testScanAny(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("scanAny_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s (i integer, f double, s nvarchar(20), b varbinary(10), d decimal(10,2), ts timestamp, c nclob, n nvarchar(20))", table)); err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (?, ?, ?, ?, ?, ?, ?, ?)", table), 42, 4.5, "Hello", []byte{0x01, 0x02}, (*Decimal)(big.NewRat(4711, 100)), ts, "World", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query(fmt.Sprintf("select * from %s", table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	values := make([]interface{}, 8)
+	dest := make([]*interface{}, len(values))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if !rows.Next() {
+		t.Fatal("row expected")
+	}
+	if err := ScanAny(rows, dest...); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, ok := values[0].(int64); !ok || v != 42 {
+		t.Fatalf("value %[1]v type %[1]T - expected int64 %d", values[0], 42)
+	}
+	if v, ok := values[1].(float64); !ok || v != 4.5 {
+		t.Fatalf("value %[1]v type %[1]T - expected float64 %f", values[1], 4.5)
+	}
+	if v, ok := values[2].(string); !ok || v != "Hello" {
+		t.Fatalf("value %[1]v type %[1]T - expected string %s", values[2], "Hello")
+	}
+	if v, ok := values[3].([]byte); !ok || !bytes.Equal(v, []byte{0x01, 0x02}) {
+		t.Fatalf("value %[1]v type %[1]T - expected []byte %v", values[3], []byte{0x01, 0x02})
+	}
+	if v, ok := values[4].(*Decimal); !ok || (*big.Rat)(v).Cmp(big.NewRat(4711, 100)) != 0 {
+		t.Fatalf("value %[1]v type %[1]T - expected *Decimal %s", values[4], big.NewRat(4711, 100))
+	}
+	if v, ok := values[5].(time.Time); !ok || !v.Equal(ts) {
+		t.Fatalf("value %[1]v type %[1]T - expected time.Time %s", values[5], ts)
+	}
+	if v, ok := values[6].(string); !ok || v != "World" {
+		t.Fatalf("value %[1]v type %[1]T - expected string %s", values[6], "World")
+	}
+	if values[7] != nil {
+		t.Fatalf("value %v - expected nil", values[7])
+	}
+}
+
+func testScanText(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("scanText_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s (i integer, f double, s nvarchar(20), b varbinary(10), d decimal(10,2), dt date, ts timestamp, c nclob, n nvarchar(20))", table)); err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Date(2020, 1, 1, 12, 0, 0, 500000000, time.UTC)
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (?, ?, ?, ?, ?, ?, ?, ?, ?)", table), 42, 4.5, "Hello", []byte{0x01, 0xab}, (*Decimal)(big.NewRat(15, 10)), ts, ts, "World", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query(fmt.Sprintf("select * from %s", table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	expected := []string{"42", "4.5", "Hello", "01AB", "1.50", "2020-01-01", "2020-01-01T12:00:00.5", "World"}
+	values := make([][]byte, len(expected)+1)
+	dest := make([]*[]byte, len(values))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if !rows.Next() {
+		t.Fatal("row expected")
+	}
+	if err := ScanText(rows, dest...); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, v := range expected {
+		if string(values[i]) != v {
+			t.Fatalf("column %d: value %s - expected %s", i, values[i], v)
+		}
+	}
+	if values[len(expected)] != nil {
+		t.Fatalf("value %s - expected nil", values[len(expected)])
+	}
+}
+
+func testInTransaction(db *sql.DB, t *testing.T) {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inTransaction := func() (b bool) {
+		if err := conn.Raw(func(driverConn interface{}) error {
+			b = driverConn.(Conn).InTransaction()
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	if inTransaction() {
+		t.Fatal("connection should not be in transaction")
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !inTransaction() {
+		t.Fatal("connection should be in transaction")
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if inTransaction() {
+		t.Fatal("connection should not be in transaction")
+	}
+
+	// leak transaction by starting it on driver level
+	if err := conn.Raw(func(driverConn interface{}) error {
+		_, err := driverConn.(driver.ConnBeginTx).BeginTx(ctx, driver.TxOptions{})
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !inTransaction() {
+		t.Fatal("connection should be in transaction")
+	}
+	if err := conn.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// session reset needs to rollback the leaked transaction
+	conn, err = db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if inTransaction() {
+		t.Fatal("connection should not be in transaction after session reset")
+	}
+}
+
+func testUnexpectedResultset(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("unexpectedResultset_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s (i integer)", table)); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// exec needs to consume a returned result set and still return a result
+	for i := 0; i < 3; i++ {
+		if _, err := conn.ExecContext(ctx, "select * from dummy"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := conn.ExecContext(ctx, "select * from dummy where dummy = ?", "X"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// query needs to return no rows for DML
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("insert into %s values (?)", table), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows.Next() {
+		t.Fatal("no rows expected")
+	}
+	if err := rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// connection needs to be usable
+	var i int
+	if err := conn.QueryRowContext(ctx, fmt.Sprintf("select i from %s", table)).Scan(&i); err != nil {
+		t.Fatal(err)
+	}
+	if i != 1 {
+		t.Fatalf("value %d - expected %d", i, 1)
+	}
+}
+
+func testSessionInfo(db *sql.DB, t *testing.T) {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var info *SessionInfo
+	if err := conn.Raw(func(driverConn interface{}) error {
+		info, err = driverConn.(Conn).SessionInfo(ctx)
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var connectionID int64
+	var user, schema string
+	if err := conn.QueryRowContext(ctx, "select current_connection, current_user, current_schema from dummy").Scan(&connectionID, &user, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if info.ConnectionID != connectionID || info.User != user || info.Schema != schema {
+		t.Fatalf("session info %v - expected connection id %d user %s schema %s", info, connectionID, user, schema)
+	}
+	if info.IsolationLevel == "" {
+		t.Fatal("isolation level expected")
+	}
+}
+
+func testStatementPlan(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("statementPlan_")
+	if _, err := db.Exec(fmt.Sprintf("create column table %s (i integer primary key, s varchar(10))", table)); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var plan string
+	if err := conn.Raw(func(driverConn interface{}) error {
+		plan, err = driverConn.(Conn).StatementPlan(ctx, fmt.Sprintf("select s from %s where i = ?", table))
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(plan, string(table)) {
+		t.Fatalf("plan %q does not reference table %s", plan, table)
+	}
+	// explain plan table entries are removed
+	var numEntry int
+	if err := conn.QueryRowContext(ctx, "select count(*) from explain_plan_table where statement_name like 'go-hdb-plan-%'").Scan(&numEntry); err != nil {
+		t.Fatal(err)
+	}
+	if numEntry != 0 {
+		t.Fatalf("number of explain plan table entries %d - expected 0", numEntry)
+	}
+}
+
 func checkAffectedRows(t *testing.T, result sql.Result, rowsExpected int64) {
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
@@ -225,6 +1485,33 @@ func TestDriver(t *testing.T) {
 		{"queryAttributeAlias", testQueryAttributeAlias},
 		{"rowsAffected", testRowsAffected},
 		{"upsert", testUpsert},
+		{"invalidate", testInvalidate},
+		{"describe", testDescribe},
+		{"columnTypeLengthSemantics", testColumnTypeLengthSemantics},
+		{"queryScalar", testQueryScalar},
+		{"exists", testExists},
+		{"scanPointer", testScanPointer},
+		{"withSchema", testWithSchema},
+		{"stPointColumn", testSTPointColumn},
+		{"geometryColumn", testGeometryColumn},
+		{"scannerSourceTypes", testScannerSourceTypes},
+		{"smallDecimalColumn", testSmallDecimalColumn},
+		{"alphanumPadding", testAlphanumPadding},
+		{"rawCESU8Column", testRawCESU8Column},
+		{"stringLength", testStringLength},
+		{"intBoolColumn", testIntBoolColumn},
+		{"booleanColumn", testBooleanColumn},
+		{"grouping", testGrouping},
+		{"decimalBigInt", testDecimalBigInt},
+		{"scanAny", testScanAny},
+		{"scanText", testScanText},
+		{"copyRows", testCopyRows},
+		{"pipeline", testPipeline},
+		{"namedParameters", testNamedParameters},
+		{"inTransaction", testInTransaction},
+		{"unexpectedResultset", testUnexpectedResultset},
+		{"sessionInfo", testSessionInfo},
+		{"statementPlan", testStatementPlan},
 	}
 
 	for _, test := range tests {
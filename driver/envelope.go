@@ -0,0 +1,178 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+)
+
+var errEmptyGeometry = errors.New("spatial: empty geometry")
+
+// envelope is the bounding box of the x and y coordinates of a geometry.
+type envelope struct {
+	minX, minY, maxX, maxY float64
+	empty                  bool
+}
+
+func (e *envelope) add(x, y float64) {
+	if e.empty {
+		e.minX, e.minY, e.maxX, e.maxY = x, y, x, y
+		e.empty = false
+		return
+	}
+	e.minX, e.minY = math.Min(e.minX, x), math.Min(e.minY, y)
+	e.maxX, e.maxY = math.Max(e.maxX, x), math.Max(e.maxY, y)
+}
+
+// envelope adds the coordinates of the next geometry to e without building the geometry.
+func (d *wkbDecoder) envelope(e *envelope) {
+	g := d.header()
+	if g == nil {
+		return
+	}
+
+	switch g.typ {
+	case gtPoint:
+		d.envelopePoints(e, g, 1)
+	case gtLineString:
+		d.envelopePoints(e, g, int(d.uint32()))
+	case gtPolygon:
+		n := int(d.uint32())
+		for i := 0; i < n && d.err == nil; i++ {
+			d.envelopePoints(e, g, int(d.uint32()))
+		}
+	case gtMultiPoint, gtMultiLineString, gtMultiPolygon, gtGeometryCollection:
+		n := int(d.uint32())
+		for i := 0; i < n && d.err == nil; i++ {
+			d.envelope(e)
+		}
+	default:
+		d.err = fmt.Errorf("spatial: unsupported geometry type %d", g.typ)
+	}
+}
+
+func (d *wkbDecoder) envelopePoints(e *envelope, g *geometry, n int) {
+	dim := g.dim()
+	for i := 0; i < n && d.err == nil; i++ {
+		x, y := d.float64(), d.float64()
+		for j := 2; j < dim; j++ { // skip z and m
+			d.float64()
+		}
+		if d.err == nil && !math.IsNaN(x) && !math.IsNaN(y) { // skip empty points
+			e.add(x, y)
+		}
+	}
+}
+
+/*
+Geometry is a scan target for spatial database values (ST_GEOMETRY, ST_POINT) keeping the value
+in (extended) well-known binary format as returned by the database. A database NULL value is
+scanned as nil.
+
+Geometry can be used as statement argument for spatial parameters as well, where the value
+can either be provided in (extended) well-known binary format or in (extended) well-known text
+format, e.g.
+
+	Geometry("MULTIPOLYGON(((0 0, 1 0, 1 1, 0 0)), ((2 2, 3 2, 3 3, 2 2)))")
+
+As spatial parameters are bound as binary values, a value in text format is converted into
+well-known binary format by the driver (like ST_GeomFromText would do on database side).
+As the spatial reference system of a value is defined by the column, an SRID of the value
+is not sent to the database.
+*/
+type Geometry []byte
+
+// Scan implements the database/sql/Scanner interface.
+func (g *Geometry) Scan(src interface{}) error {
+	if src == nil {
+		*g = nil
+		return nil
+	}
+	b, err := scanSpatialBytes(src)
+	if err != nil {
+		return err
+	}
+	*g = append((*g)[:0], b...) // src might be reused by the driver
+	return nil
+}
+
+// Value implements the database/sql/Valuer interface.
+func (g Geometry) Value() (driver.Value, error) {
+	if g == nil {
+		return nil, nil
+	}
+	var geo *geometry
+	var err error
+	if isWKT(g) {
+		geo, err = decodeWKT(string(g))
+	} else {
+		geo, err = decodeWKB(g)
+	}
+	if err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	encodeWKB(buf, geo)
+	return buf.Bytes(), nil
+}
+
+// WKT returns the geometry in (extended) well-known text format (e.g. SRID=4326;POINT (1 2)).
+func (g Geometry) WKT() (string, error) {
+	geo, err := decodeWKB(g)
+	if err != nil {
+		return "", err
+	}
+	buf := new(bytes.Buffer)
+	writeWKT(buf, geo, true)
+	return buf.String(), nil
+}
+
+// SRID returns the spatial reference system identifier of the geometry (0: not set).
+func (g Geometry) SRID() (int32, error) {
+	d := &wkbDecoder{b: g}
+	h := d.header()
+	if d.err != nil {
+		return 0, d.err
+	}
+	return h.srid, nil
+}
+
+/*
+Envelope returns the bounding box of the geometry. The geometry is scanned for the x and y
+coordinates only, without building the geometry (z and m coordinates are ignored), so that
+Envelope is considerably cheaper than converting the value (e.g. to GeoJSON).
+
+The coordinates are returned unchanged in the spatial reference system of the value (see SRID),
+like the envelope computed by the database via ST_Envelope.
+An error is returned for an empty geometry.
+*/
+func (g Geometry) Envelope() (minX, minY, maxX, maxY float64, err error) {
+	d := &wkbDecoder{b: g}
+	e := &envelope{empty: true}
+	d.envelope(e)
+	if d.err != nil {
+		return 0, 0, 0, 0, d.err
+	}
+	if e.empty {
+		return 0, 0, 0, 0, errEmptyGeometry
+	}
+	return e.minX, e.minY, e.maxX, e.maxY, nil
+}
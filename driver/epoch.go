@@ -0,0 +1,100 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+/*
+EpochSeconds is a time value stored in an integer (BIGINT, INTEGER) or DECIMAL column
+as number of seconds since the Unix epoch (1970-01-01 00:00:00 UTC).
+Bound as parameter, the time value is converted into the epoch value truncated to seconds;
+scanned, the epoch value is converted into a time value in UTC. A NULL value scans as the zero time.
+
+Other than the temporal database types, epoch columns need an explicit conversion:
+
+	err := db.QueryRow("select created from legacy").Scan((*driver.EpochSeconds)(&t))
+	_, err = db.Exec("insert into legacy values (?)", driver.EpochSeconds(t))
+*/
+type EpochSeconds time.Time
+
+// Scan implements the database/sql/Scanner interface.
+func (e *EpochSeconds) Scan(src interface{}) error {
+	v, ok, err := scanEpoch(src)
+	if err != nil || !ok {
+		*e = EpochSeconds(time.Time{})
+		return err
+	}
+	*e = EpochSeconds(time.Unix(v, 0).UTC())
+	return nil
+}
+
+// Value implements the database/sql/Valuer interface.
+func (e EpochSeconds) Value() (driver.Value, error) {
+	return time.Time(e).Unix(), nil
+}
+
+/*
+EpochMillis is a time value stored in an integer (BIGINT) or DECIMAL column as number of
+milliseconds since the Unix epoch (1970-01-01 00:00:00 UTC).
+Bound as parameter, the time value is converted into the epoch value truncated to milliseconds;
+scanned, the epoch value is converted into a time value in UTC. A NULL value scans as the zero time.
+*/
+type EpochMillis time.Time
+
+// Scan implements the database/sql/Scanner interface.
+func (e *EpochMillis) Scan(src interface{}) error {
+	v, ok, err := scanEpoch(src)
+	if err != nil || !ok {
+		*e = EpochMillis(time.Time{})
+		return err
+	}
+	*e = EpochMillis(time.Unix(v/1000, v%1000*int64(time.Millisecond)).UTC())
+	return nil
+}
+
+// Value implements the database/sql/Valuer interface.
+func (e EpochMillis) Value() (driver.Value, error) {
+	t := time.Time(e)
+	return t.Unix()*1000 + int64(t.Nanosecond())/int64(time.Millisecond), nil
+}
+
+// scanEpoch returns the integer value of an epoch column (ok == false: NULL value).
+func scanEpoch(src interface{}) (int64, bool, error) {
+	switch src := src.(type) {
+	case nil:
+		return 0, false, nil
+	case int64:
+		return src, true, nil
+	case []byte: // decimal
+		var d Decimal
+		if err := d.Scan(src); err != nil {
+			return 0, false, err
+		}
+		r := (*big.Rat)(&d)
+		if !r.IsInt() || !r.Num().IsInt64() {
+			return 0, false, fmt.Errorf("epoch: invalid decimal value %s", r.RatString())
+		}
+		return r.Num().Int64(), true, nil
+	default:
+		return 0, false, fmt.Errorf("epoch: invalid data type %T", src)
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"testing"
+	"time"
+)
+
+func testEpochMillis(t *testing.T) {
+	testData := []struct {
+		t time.Time
+		v int64
+	}{
+		{time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), 0},
+		{time.Date(2020, 1, 1, 12, 30, 45, 123456789, time.UTC), 1577881845123},
+		{time.Date(1969, 12, 31, 23, 59, 59, 500000000, time.UTC), -500},
+	}
+
+	for i, d := range testData {
+		v, err := EpochMillis(d.t).Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != d.v {
+			t.Fatalf("test %d: value %v - expected %d", i, v, d.v)
+		}
+		var e EpochMillis
+		if err := e.Scan(v); err != nil {
+			t.Fatal(err)
+		}
+		if !time.Time(e).Equal(d.t.Truncate(time.Millisecond)) {
+			t.Fatalf("test %d: time %s - expected %s", i, time.Time(e), d.t.Truncate(time.Millisecond))
+		}
+	}
+}
+
+func testEpochSeconds(t *testing.T) {
+	tm := time.Date(2020, 1, 1, 12, 30, 45, 123456789, time.UTC)
+
+	v, err := EpochSeconds(tm).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != int64(1577881845) {
+		t.Fatalf("value %v - expected %d", v, 1577881845)
+	}
+
+	// decimal column
+	b, err := convertDecimalArg(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var e EpochSeconds
+	if err := e.Scan(b); err != nil {
+		t.Fatal(err)
+	}
+	if !time.Time(e).Equal(tm.Truncate(time.Second)) {
+		t.Fatalf("time %s - expected %s", time.Time(e), tm.Truncate(time.Second))
+	}
+
+	// non integer decimal value
+	if b, err = convertDecimalArg("1.5"); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Scan(b); err == nil {
+		t.Fatal("invalid decimal value error expected")
+	}
+
+	// null value
+	if err := e.Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if !time.Time(e).IsZero() {
+		t.Fatalf("time %s - expected zero time", time.Time(e))
+	}
+}
+
+func TestEpoch(t *testing.T) {
+	tests := []struct {
+		name string
+		fct  func(t *testing.T)
+	}{
+		{"epochMillis", testEpochMillis},
+		{"epochSeconds", testEpochSeconds},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.fct(t)
+		})
+	}
+}
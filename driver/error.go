@@ -16,6 +16,10 @@ limitations under the License.
 
 package driver
 
+import (
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
 // HDB error levels.
 const (
 	HdbWarning    = 0
@@ -23,17 +27,43 @@ const (
 	HdbFatalError = 2
 )
 
-// Error represents errors send by the database server.
+// HDB error codes.
+const (
+	HdbErrLockWaitTimeout      = 131 // transaction rolled back by lock wait timeout
+	HdbErrDeadlock             = 133 // transaction rolled back by detected deadlock
+	HdbErrSerializationFailure = 138 // transaction serialization failure
+	HdbErrResourceBusy         = 146 // resource busy and acquire with NOWAIT specified
+	HdbErrInvalidTableName     = 259 // invalid table name
+	HdbErrUniqueViolation      = 301 // unique constraint violated
+	HdbErrLobStreaming         = 596 // lob streaming is not permitted in auto-commit mode
+)
+
+/*
+Error represents errors send by the database server.
+
+In case the database server returns more than one error (e.g. on a bulk insert with duplicate
+keys) all errors are accessible by setting the error index via SetIdx in the range of 0 <= index < NumError().
+*/
 type Error interface {
-	Error() string   // Implements the golang error interface.
-	NumError() int   // NumError returns the number of errors.
-	SetIdx(idx int)  // Sets the error index in case number of errors are greater 1 in the range of 0 <= index < NumError().
-	StmtNo() int     // Returns the statement number of the error in multi statement contexts (e.g. bulk insert).
-	Code() int       // Code return the database error code.
-	Position() int   // Position returns the start position of erroneous sql statements sent to the database server.
-	Level() int      // Level return one of the database server predefined error levels.
-	Text() string    // Text return the error description sent from database server.
-	IsWarning() bool // IsWarning returns true if the HDB error level equals 0.
-	IsError() bool   // IsError returns true if the HDB error level equals 1.
-	IsFatal() bool   // IsFatal returns true if the HDB error level equals 2.
+	Error() string    // Implements the golang error interface.
+	NumError() int    // NumError returns the number of errors.
+	SetIdx(idx int)   // Sets the error index in case number of errors are greater 1 in the range of 0 <= index < NumError().
+	StmtNo() int      // Returns the statement number of the error in multi statement contexts (e.g. bulk insert).
+	Code() int        // Code return the database error code.
+	Position() int    // Position returns the start position of erroneous sql statements sent to the database server.
+	Level() int       // Level return one of the database server predefined error levels.
+	Text() string     // Text return the error description sent from database server.
+	SQLState() string // SQLState returns the SQLSTATE code sent from database server.
+	IsWarning() bool  // IsWarning returns true if the HDB error level equals 0.
+	IsError() bool    // IsError returns true if the HDB error level equals 1.
+	IsFatal() bool    // IsFatal returns true if the HDB error level equals 2.
 }
+
+// HasErrorCode returns true if err is an Error and any of its single errors matches one of the codes.
+func HasErrorCode(err error, codes ...int) bool { return p.HasErrorCode(err, codes...) }
+
+// IsUniqueViolation returns true if err is an Error reporting a unique constraint violation.
+func IsUniqueViolation(err error) bool { return p.HasErrorCode(err, HdbErrUniqueViolation) }
+
+// IsDeadlock returns true if err is an Error reporting a transaction rollback caused by a detected deadlock.
+func IsDeadlock(err error) bool { return p.HasErrorCode(err, HdbErrDeadlock) }
@@ -0,0 +1,55 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql/driver"
+)
+
+type fetchSizeCtxKey struct{}
+
+/*
+WithFetchSize returns a copy of the context with the fetch size set.
+The fetch size overrides the connector fetch size (see SetFetchSize) for the FETCH requests
+of queries executed with the returned context, e.g. a large fetch size for an export query
+and the connector default for small lookups on the same connection.
+Like SetFetchSize and DSNFetchSize fetch sizes less than one are invalid: for such fetch sizes
+the context is returned unchanged, so that the connector fetch size is used.
+*/
+func WithFetchSize(ctx context.Context, fetchSize int) context.Context {
+	if fetchSize < minFetchSize {
+		return ctx
+	}
+	return context.WithValue(ctx, fetchSizeCtxKey{}, fetchSize)
+}
+
+// fetchSizeSetter is the interface wrapping the SetFetchSize method of query result sets.
+type fetchSizeSetter interface {
+	SetFetchSize(fetchSize int)
+}
+
+// setFetchSize sets the fetch size of rows if the context does provide one.
+func setFetchSize(ctx context.Context, rows driver.Rows) {
+	fetchSize, ok := ctx.Value(fetchSizeCtxKey{}).(int)
+	if !ok {
+		return
+	}
+	if s, ok := rows.(fetchSizeSetter); ok {
+		s.SetFetchSize(fetchSize)
+	}
+}
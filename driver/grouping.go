@@ -0,0 +1,75 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+/*
+Grouping is the scan type of a GROUPING(<column>) result column. In results of queries with
+GROUPING SETS, ROLLUP or CUBE a NULL value of a grouped column is ambiguous: it might be a
+real NULL value of a detail row or the indicator that the column is aggregated (super-aggregate row).
+Grouping is true if the column is aggregated, so that the NULL value is a grouping NULL.
+
+The NULL values of the grouped columns itself scan into Null types (e.g. sql.NullString) like any
+other NULL value. Use GroupingColumns to add the grouping columns to the select list:
+
+	query := fmt.Sprintf("select region, product, sum(amount), %s from sales group by rollup (region, product)",
+		driver.GroupingColumns("region", "product"))
+
+	var (
+		region, product                 sql.NullString
+		amount                          driver.Decimal
+		regionGrouping, productGrouping driver.Grouping
+	)
+	err := rows.Scan(&region, &product, &amount, &regionGrouping, &productGrouping)
+	// productGrouping == true: aggregate row of region (product is a grouping NULL)
+*/
+type Grouping bool
+
+// Scan implements the database/sql/Scanner interface.
+func (g *Grouping) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*g = false
+	case int64:
+		*g = src != 0
+	default:
+		return fmt.Errorf("grouping: invalid data type %T", src)
+	}
+	return nil
+}
+
+// Value implements the database/sql/Valuer interface.
+func (g Grouping) Value() (driver.Value, error) {
+	if g {
+		return int64(1), nil
+	}
+	return int64(0), nil
+}
+
+// GroupingColumns returns the select list expression of GROUPING(<column>) columns for the given columns.
+func GroupingColumns(columns ...string) string {
+	exprs := make([]string, len(columns))
+	for i, column := range columns {
+		exprs[i] = fmt.Sprintf("grouping(%s)", column)
+	}
+	return strings.Join(exprs, ", ")
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+/*
+IntBool is a scan destination for boolean values stored as integers (e.g. TINYINT columns used as
+boolean in schemas predating the BOOLEAN data type): 0 is scanned as false and any other value as true.
+
+Whereas scanning an integer column into *bool is supported by database/sql as well, the conversion
+is strict: 0 and 1 are converted into false and true and any other value results in an error.
+IntBool is the lenient alternative for columns which might contain other non-zero values.
+
+A NULL value results in an error like for *bool - please use **IntBool for nullable columns.
+Bound as parameter IntBool is converted into 0 or 1.
+*/
+type IntBool bool
+
+// Scan implements the database/sql/Scanner interface.
+func (b *IntBool) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case int64:
+		*b = src != 0
+	case bool:
+		*b = IntBool(src)
+	case nil:
+		return fmt.Errorf("int bool: converting NULL to %T is unsupported", b)
+	default:
+		return fmt.Errorf("int bool: invalid data type %T", src)
+	}
+	return nil
+}
+
+// Value implements the database/sql/driver/Valuer interface.
+func (b IntBool) Value() (driver.Value, error) {
+	if b {
+		return int64(1), nil
+	}
+	return int64(0), nil
+}
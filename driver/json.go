@@ -0,0 +1,91 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+var jsonNull = []byte("null")
+
+/*
+A JSONValue binds and scans go values as JSON documents stored in character columns
+(e.g. NVARCHAR, NCLOB). See JSON.
+*/
+type JSONValue struct {
+	v interface{}
+}
+
+/*
+JSON returns a JSONValue wrapping v:
+- used as statement parameter, v is marshaled (encoding/json) and bound as JSON text
+- used as scan destination, the JSON text of the column is unmarshaled into v, so v needs to be a pointer
+
+Example:
+
+	m := map[string]interface{}{"name": "go-hdb"}
+	db.Exec("insert into documents values (?)", driver.JSON(m))
+
+	var m map[string]interface{}
+	db.QueryRow("select doc from documents").Scan(driver.JSON(&m))
+
+The JSON text is converted to the database encoding (CESU-8) like any other character value.
+A nil value is bound as NULL and scanning NULL unmarshals JSON null into v.
+*/
+func JSON(v interface{}) *JSONValue { return &JSONValue{v: v} }
+
+// Value implements the database/sql/Valuer interface.
+func (j *JSONValue) Value() (driver.Value, error) {
+	if j.v == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(j.v)
+	if err != nil {
+		return nil, fmt.Errorf("json: %w", err)
+	}
+	return string(b), nil
+}
+
+// Scan implements the database/sql/Scanner interface.
+func (j *JSONValue) Scan(src interface{}) error {
+	var b []byte
+	switch src := src.(type) {
+	case nil:
+		b = jsonNull
+	case []byte:
+		b = src
+	case string:
+		b = []byte(src)
+	case p.WriterSetter: // lob
+		buf := new(bytes.Buffer)
+		if err := src.SetWriter(buf); err != nil {
+			return err
+		}
+		b = buf.Bytes()
+	default:
+		return fmt.Errorf("json: invalid scan type %T", src)
+	}
+	if err := json.Unmarshal(b, j.v); err != nil {
+		return fmt.Errorf("json: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testJSONValue(t *testing.T) {
+	m := map[string]interface{}{"name": "go-hdb", "version": 1.0, "tags": []interface{}{"hana", "ä€"}}
+
+	v, err := JSON(m).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, ok := v.(string)
+	if !ok {
+		t.Fatalf("value type %T - expected string", v)
+	}
+
+	var result map[string]interface{}
+	if err := JSON(&result).Scan([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, m) {
+		t.Fatalf("value %v - expected %v", result, m)
+	}
+}
+
+func testJSONNull(t *testing.T) {
+	v, err := JSON(nil).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != nil {
+		t.Fatalf("value %v - expected nil", v)
+	}
+
+	result := map[string]interface{}{"k": "v"}
+	if err := JSON(&result).Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if result != nil {
+		t.Fatalf("value %v - expected nil", result)
+	}
+}
+
+func testJSONInvalid(t *testing.T) {
+	if _, err := JSON(make(chan int)).Value(); err == nil {
+		t.Fatal("marshal error expected")
+	}
+	var result map[string]interface{}
+	if err := JSON(&result).Scan([]byte("{invalid")); err == nil {
+		t.Fatal("unmarshal error expected")
+	}
+	if err := JSON(&result).Scan(42); err == nil {
+		t.Fatal("invalid scan type error expected")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		fct  func(t *testing.T)
+	}{
+		{"jsonValue", testJSONValue},
+		{"jsonNull", testJSONNull},
+		{"jsonInvalid", testJSONInvalid},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.fct(t)
+		})
+	}
+}
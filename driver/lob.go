@@ -17,6 +17,7 @@ limitations under the License.
 package driver
 
 import (
+	"bytes"
 	"database/sql/driver"
 	"fmt"
 	"io"
@@ -29,9 +30,27 @@ import (
 // A Lob object uses an io.Writer object as destination for reading content from a database lob field.
 // A Lob can be created by contructor method NewLob with io.Reader and io.Writer as parameters or
 // created by new, setting io.Reader and io.Writer by SetReader and SetWriter methods.
+//
+// Scanning a database lob field into a Lob without io.Writer reads the content into an internal buffer,
+// which is accessible via Bytes:
+//
+//	lob := new(Lob)
+//	if err := db.QueryRow("select clob from t").Scan(lob); err != nil {
+//		...
+//	}
+//	s := string(lob.Bytes())
+//
+// A Lob created by NewLazyLob reads the content lazily: instead of writing the whole content
+// into a writer on Scan, Reader returns an io.Reader streaming the content from the database on demand,
+// so that large lobs do not need to be held in memory. The lob content can only be read this way as long as
+// the rows (or the connection for output parameters) are not closed - afterwards the reader returns
+// ErrLobReaderClosed. As sql.Rows are closed automatically after the last row, the lob content needs to be read
+// before calling Rows.Next again.
 type Lob struct {
-	rd io.Reader
-	wr io.Writer
+	rd   io.Reader
+	wr   io.Writer
+	buf  *bytes.Buffer // destination of scans without writer
+	lazy bool
 }
 
 // NewLob creates a new Lob instance with the io.Reader and io.Writer given as parameters.
@@ -39,11 +58,34 @@ func NewLob(rd io.Reader, wr io.Writer) *Lob {
 	return &Lob{rd: rd, wr: wr}
 }
 
+// NewLobFromReader creates a new Lob instance with the io.Reader source given as parameter
+// (e.g. for inserting a lob field).
+func NewLobFromReader(rd io.Reader) *Lob {
+	return &Lob{rd: rd}
+}
+
+// NewLazyLob creates a new Lob instance reading the content of a scanned lob field on demand via Reader.
+func NewLazyLob() *Lob {
+	return &Lob{lazy: true}
+}
+
+// ErrLobReaderClosed is returned by the lazy lob reader (see NewLazyLob) after the rows or the connection
+// the lob was read from are closed.
+var ErrLobReaderClosed = p.ErrLobReaderClosed
+
 // Reader returns the io.Reader of the Lob.
 func (l Lob) Reader() io.Reader {
 	return l.rd
 }
 
+// Bytes returns the content of a lob field scanned into a Lob without io.Writer.
+func (l Lob) Bytes() []byte {
+	if l.buf == nil {
+		return nil
+	}
+	return l.buf.Bytes()
+}
+
 // SetReader sets the io.Reader source for a lob field to be written to database
 // and return *Lob, to enable simple call chaining.
 func (l *Lob) SetReader(rd io.Reader) *Lob {
@@ -65,8 +107,13 @@ func (l *Lob) SetWriter(wr io.Writer) *Lob {
 
 // Scan implements the database/sql/Scanner interface.
 func (l *Lob) Scan(src interface{}) error {
-	if l.wr == nil {
-		return fmt.Errorf("lob error: initial writer %[1]T %[1]v", l)
+	if l.lazy && l.wr == nil {
+		rg, ok := src.(p.ReaderGetter)
+		if !ok {
+			return fmt.Errorf("lob: invalid scan type %T", src)
+		}
+		l.rd = rg.Reader()
+		return nil
 	}
 
 	ws, ok := src.(p.WriterSetter)
@@ -74,7 +121,16 @@ func (l *Lob) Scan(src interface{}) error {
 		return fmt.Errorf("lob: invalid scan type %T", src)
 	}
 
-	if err := ws.SetWriter(l.wr); err != nil {
+	wr := l.wr
+	if wr == nil {
+		if l.buf == nil {
+			l.buf = new(bytes.Buffer)
+		}
+		l.buf.Reset()
+		wr = l.buf
+	}
+
+	if err := ws.SetWriter(wr); err != nil {
 		return err
 	}
 	return nil
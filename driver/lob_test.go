@@ -21,8 +21,10 @@ import (
 	"bytes"
 	"crypto/rand"
 	"database/sql"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"sync"
 	"testing"
 )
@@ -127,6 +129,128 @@ func testLobPipe(db *sql.DB, t *testing.T) {
 	wg.Wait()
 }
 
+func testLobAutoTx(db *sql.DB, t *testing.T) {
+	const lobSize = 10000
+
+	table := RandomIdentifier("lobAutoTx")
+
+	if _, err := db.Exec(fmt.Sprintf("create table %s (b blob)", table)); err != nil {
+		t.Fatalf("create table failed: %s", err)
+	}
+
+	wrBuf := &bytes.Buffer{}
+	wrBuf.ReadFrom(io.LimitReader(randReader{}, lobSize))
+
+	// no explicit transaction: statement is executed in implicit transaction
+	lob := &Lob{}
+	lob.SetReader(bytes.NewReader(wrBuf.Bytes()))
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (?)", table), lob); err != nil {
+		t.Fatal(err)
+	}
+
+	rdBuf := &bytes.Buffer{}
+	lob.SetWriter(rdBuf)
+	if err := db.QueryRow(fmt.Sprintf("select * from %s", table)).Scan(lob); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(rdBuf.Bytes(), wrBuf.Bytes()) {
+		t.Fatalf("read buffer is not equal to write buffer")
+	}
+}
+
+func testLobLazy(db *sql.DB, t *testing.T) {
+	const lobSize = 100000 // exceeds lob chunk size
+
+	table := RandomIdentifier("lobLazy")
+
+	if _, err := db.Exec(fmt.Sprintf("create table %s (i integer, b blob)", table)); err != nil {
+		t.Fatalf("create table failed: %s", err)
+	}
+
+	wrBuf := &bytes.Buffer{}
+	wrBuf.ReadFrom(io.LimitReader(randReader{}, lobSize))
+
+	for i := 0; i < 2; i++ {
+		if _, err := db.Exec(fmt.Sprintf("insert into %s values (?, ?)", table), i, NewLob(bytes.NewReader(wrBuf.Bytes()), nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// lob locators need a transaction
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(fmt.Sprintf("select b from %s order by i", table))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// first row: read lazily
+	if !rows.Next() {
+		t.Fatal(rows.Err())
+	}
+	lob := NewLazyLob()
+	if err := rows.Scan(lob); err != nil {
+		t.Fatal(err)
+	}
+	rdBuf := &bytes.Buffer{}
+	if _, err := rdBuf.ReadFrom(lob.Reader()); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(rdBuf.Bytes(), wrBuf.Bytes()) {
+		t.Fatalf("read buffer is not equal to write buffer")
+	}
+
+	// second row: read after rows are closed
+	if !rows.Next() {
+		t.Fatal(rows.Err())
+	}
+	lob = NewLazyLob()
+	if err := rows.Scan(lob); err != nil {
+		t.Fatal(err)
+	}
+	if err := rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rdBuf.ReadFrom(lob.Reader()); !errors.Is(err, ErrLobReaderClosed) {
+		t.Fatalf("error %v - expected %v", err, ErrLobReaderClosed)
+	}
+}
+
+func testLobBytes(db *sql.DB, t *testing.T) {
+	const (
+		lobSize = 100000 // exceeds lob chunk size
+		clob    = "Hello, 世界"
+	)
+
+	table := RandomIdentifier("lobBytes")
+
+	if _, err := db.Exec(fmt.Sprintf("create table %s (b blob, c nclob)", table)); err != nil {
+		t.Fatalf("create table failed: %s", err)
+	}
+
+	wrBuf := &bytes.Buffer{}
+	wrBuf.ReadFrom(io.LimitReader(randReader{}, lobSize))
+
+	if _, err := db.Exec(fmt.Sprintf("insert into %s values (?, ?)", table), NewLobFromReader(bytes.NewReader(wrBuf.Bytes())), NewLobFromReader(strings.NewReader(clob))); err != nil {
+		t.Fatal(err)
+	}
+
+	b, c := new(Lob), new(Lob) // no writer: read into internal buffer
+	if err := db.QueryRow(fmt.Sprintf("select * from %s", table)).Scan(b, c); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b.Bytes(), wrBuf.Bytes()) {
+		t.Fatalf("read buffer is not equal to write buffer")
+	}
+	if string(c.Bytes()) != clob {
+		t.Fatalf("clob %s - expected %s", c.Bytes(), clob)
+	}
+}
+
 func TestLob(t *testing.T) {
 	tests := []struct {
 		name string
@@ -134,6 +258,9 @@ func TestLob(t *testing.T) {
 	}{
 		{"insert", testLobInsert},
 		{"pipe", testLobPipe},
+		{"autoTx", testLobAutoTx},
+		{"lazy", testLobLazy},
+		{"bytes", testLobBytes},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,141 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metadata
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Queryer is implemented by sql.DB, sql.Conn and sql.Tx.
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Load status values of column store tables and columns (see M_CS_TABLES, M_CS_COLUMNS).
+const (
+	LoadedFalse     = "FALSE"
+	LoadedTrue      = "TRUE"
+	LoadedPartially = "PARTIALLY"
+)
+
+// ColumnStoreColumnInfo contains the column store statistics of a table column.
+type ColumnStoreColumnInfo struct {
+	Name          string
+	Loaded        string // Load status (LoadedFalse, LoadedTrue, LoadedPartially).
+	MemorySize    int64  // Total memory size in bytes.
+	DistinctCount int64  // Estimated number of distinct values (maximum of all partitions).
+}
+
+// ColumnStoreInfo contains the column store statistics of a table aggregated over all table partitions.
+type ColumnStoreInfo struct {
+	Schema      string
+	Table       string
+	Loaded      string // Load status (LoadedFalse, LoadedTrue, LoadedPartially).
+	MemorySize  int64  // Total memory size in bytes.
+	RecordCount int64  // Number of records.
+	Columns     []ColumnStoreColumnInfo
+}
+
+const (
+	csTableQuery  = "select loaded, memory_size_in_total, record_count from m_cs_tables where schema_name = ? and table_name = ?"
+	csColumnQuery = "select column_name, loaded, memory_size_in_total, distinct_count from m_cs_columns where schema_name = ? and table_name = ? order by column_name, part_id"
+)
+
+/*
+ReadColumnStoreInfo reads the column store statistics (load status, memory size, record count and
+distinct value estimates per column) of a column store table from the monitoring views M_CS_TABLES
+and M_CS_COLUMNS. The statistics of partitioned tables are aggregated over all partitions.
+Reading the monitoring views might need the MONITORING or CATALOG READ privilege.
+*/
+func ReadColumnStoreInfo(ctx context.Context, q Queryer, schema, table string) (*ColumnStoreInfo, error) {
+	info := &ColumnStoreInfo{Schema: schema, Table: table}
+
+	numPart := 0
+	if err := queryRows(ctx, q, csTableQuery, []interface{}{schema, table}, func(rows *sql.Rows) error {
+		var (
+			loaded                  string
+			memorySize, recordCount sql.NullInt64
+		)
+		if err := rows.Scan(&loaded, &memorySize, &recordCount); err != nil {
+			return err
+		}
+		info.Loaded = aggregateLoaded(info.Loaded, loaded, numPart)
+		info.MemorySize += memorySize.Int64
+		info.RecordCount += recordCount.Int64
+		numPart++
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if numPart == 0 {
+		return nil, fmt.Errorf("column store table %s.%s not found", schema, table)
+	}
+
+	colParts := map[string]int{}
+	if err := queryRows(ctx, q, csColumnQuery, []interface{}{schema, table}, func(rows *sql.Rows) error {
+		var (
+			name, loaded              string
+			memorySize, distinctCount sql.NullInt64
+		)
+		if err := rows.Scan(&name, &loaded, &memorySize, &distinctCount); err != nil {
+			return err
+		}
+		n := len(info.Columns)
+		if n == 0 || info.Columns[n-1].Name != name {
+			info.Columns = append(info.Columns, ColumnStoreColumnInfo{Name: name})
+			n++
+		}
+		c := &info.Columns[n-1]
+		c.Loaded = aggregateLoaded(c.Loaded, loaded, colParts[name])
+		c.MemorySize += memorySize.Int64
+		if distinctCount.Int64 > c.DistinctCount {
+			c.DistinctCount = distinctCount.Int64
+		}
+		colParts[name]++
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// aggregateLoaded aggregates the load status of the partition with index part with the load status of the previous partitions.
+func aggregateLoaded(prev, loaded string, part int) string {
+	if part == 0 || prev == loaded {
+		return loaded
+	}
+	return LoadedPartially
+}
+
+func queryRows(ctx context.Context, q Queryer, query string, args []interface{}, fn func(rows *sql.Rows) error) error {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := fn(rows); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return rows.Close()
+}
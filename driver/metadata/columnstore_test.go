@@ -0,0 +1,46 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metadata
+
+import (
+	"testing"
+)
+
+func TestAggregateLoaded(t *testing.T) {
+	testData := []struct {
+		loaded []string
+		result string
+	}{
+		{[]string{LoadedTrue}, LoadedTrue},
+		{[]string{LoadedFalse}, LoadedFalse},
+		{[]string{LoadedTrue, LoadedTrue}, LoadedTrue},
+		{[]string{LoadedFalse, LoadedFalse}, LoadedFalse},
+		{[]string{LoadedTrue, LoadedFalse}, LoadedPartially},
+		{[]string{LoadedFalse, LoadedTrue, LoadedTrue}, LoadedPartially},
+		{[]string{LoadedPartially, LoadedPartially}, LoadedPartially},
+	}
+
+	for i, d := range testData {
+		result := ""
+		for part, loaded := range d.loaded {
+			result = aggregateLoaded(result, loaded, part)
+		}
+		if result != d.result {
+			t.Fatalf("test %d: loaded %s - expected %s", i, result, d.result)
+		}
+	}
+}
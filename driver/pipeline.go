@@ -0,0 +1,103 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type pipelineExec struct {
+	query string
+	args  []interface{}
+}
+
+/*
+Pipeline queues the executions of (different) statements to be executed together by Exec.
+
+As the hdb protocol executes one statement per request, the executions are combined into as few requests as
+possible: consecutive executions of the same statement (e.g. several INSERTs into one table) are sent as one
+bulk request (see ExecBatch), whereas executions of different statements need a request each. The statements
+are prepared on the connection of the transaction only once per consecutive run, so that no additional
+prepare requests are needed for the executions of a run.
+
+Example:
+
+	var pl driver.Pipeline
+	pl.Queue("insert into orders values (?, ?)", 1, "order 1")
+	pl.Queue("insert into orders values (?, ?)", 2, "order 2") // same request as the previous execution
+	pl.Queue("insert into audit values (?)", "orders 1, 2")
+	results, err := pl.Exec(ctx, tx)
+*/
+type Pipeline struct {
+	execs []pipelineExec
+}
+
+// Queue queues the execution of query with arguments args.
+func (p *Pipeline) Queue(query string, args ...interface{}) {
+	p.execs = append(p.execs, pipelineExec{query: query, args: append([]interface{}(nil), args...)})
+}
+
+// Len returns the number of queued executions.
+func (p *Pipeline) Len() int { return len(p.execs) }
+
+/*
+Exec executes the queued executions in queue order within transaction tx and returns one result per
+execution. The result of an execution being part of a bulk request reports the rows affected by the
+bulk request as a whole. The queue is emptied, so that the pipeline can be reused afterwards.
+
+Exec stops at the first failing request and returns an error identifying the execution. As the executions
+of the requests sent before are not reverted, rolling back tx (all-or-nothing) or committing tx
+(executions before the failing request) is up to the caller.
+*/
+func (p *Pipeline) Exec(ctx context.Context, tx *sql.Tx) ([]sql.Result, error) {
+	execs := p.execs
+	p.execs = nil
+
+	results := make([]sql.Result, len(execs))
+	for i := 0; i < len(execs); {
+		j := i + 1
+		for len(execs[i].args) != 0 && j < len(execs) && execs[j].query == execs[i].query { // no bulk execution without arguments
+			j++
+		}
+		r, err := p.execRun(ctx, tx, execs[i:j])
+		if err != nil {
+			return nil, fmt.Errorf("pipeline execution %d (%s): %w", i, execs[i].query, err)
+		}
+		for k := i; k < j; k++ {
+			results[k] = r
+		}
+		i = j
+	}
+	return results, nil
+}
+
+// execRun executes a run of executions of the same statement as bulk request.
+func (p *Pipeline) execRun(ctx context.Context, tx *sql.Tx, execs []pipelineExec) (sql.Result, error) {
+	stmt, err := tx.PrepareContext(ctx, execs[0].query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows := make([][]interface{}, len(execs))
+	for i, e := range execs {
+		rows[i] = e.args
+	}
+	return ExecBatch(ctx, stmt, rows)
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+const (
+	planIDQuery     = "select plan_id from m_prepared_statements where statement_id = %d"
+	explainPlanStmt = "explain plan set statement_name = '%s' for sql plan cache entry %d"
+	planQuery       = "select level, operator_name, operator_details, table_name, estimated_output_row_count from explain_plan_table where statement_name = '%s' order by operator_id"
+	deletePlanStmt  = "delete from explain_plan_table where statement_name = '%s'"
+)
+
+// prepareStmtID prepares the query and returns the statement id of the prepared statement.
+func (c *conn) prepareStmtID(ctx context.Context, query string) (stmtID uint64, err error) {
+	done := make(chan struct{})
+	go func() {
+		var (
+			qd *p.QueryDescr
+			pr *p.PrepareResult
+		)
+
+		qd, err = p.NewQueryDescr(query, c.scanner)
+		if err != nil {
+			goto done
+		}
+		pr, err = c.session.Prepare(qd.PrepareQuery())
+		if err != nil {
+			goto done
+		}
+		stmtID = pr.StmtID()
+	done:
+		close(done)
+	}()
+
+	if err := c.wait(ctx, done, func() {
+		if stmtID != 0 { // prepared before the cancellation: release the statement
+			c.session.DropStatementID(stmtID)
+		}
+	}); err != nil {
+		return 0, err
+	}
+	return stmtID, err
+}
+
+// queryValues executes the query and returns the values of all rows.
+func (c *conn) queryValues(ctx context.Context, query string) ([][]driver.Value, error) {
+	rows, err := c.QueryContext(ctx, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result [][]driver.Value
+	for {
+		values := make([]driver.Value, len(rows.Columns()))
+		if err := rows.Next(values); err != nil {
+			if err == io.EOF {
+				return result, nil
+			}
+			return nil, err
+		}
+		for i, v := range values {
+			if b, ok := v.([]byte); ok { // values might be reused by the driver
+				values[i] = string(b)
+			}
+		}
+		result = append(result, values)
+	}
+}
+
+func (c *conn) statementPlan(ctx context.Context, query string) (plan string, err error) {
+	stmtID, err := c.prepareStmtID(ctx, query)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if dropErr := c.session.DropStatementID(stmtID); err == nil {
+			err = dropErr
+		}
+	}()
+
+	values, err := c.queryValues(ctx, fmt.Sprintf(planIDQuery, stmtID))
+	if err != nil {
+		return "", err
+	}
+	if len(values) == 0 || values[0][0] == nil {
+		return "", fmt.Errorf("statement plan: no plan cache entry found for statement id %d", stmtID)
+	}
+	planID, ok := values[0][0].(int64)
+	if !ok {
+		return "", fmt.Errorf("statement plan: invalid plan id type %T", values[0][0])
+	}
+
+	b := make([]byte, 8)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("go-hdb-plan-%x", b)
+
+	if _, err := c.ExecContext(ctx, fmt.Sprintf(explainPlanStmt, name, planID), nil); err != nil {
+		return "", err
+	}
+	defer func() {
+		if _, delErr := c.ExecContext(ctx, fmt.Sprintf(deletePlanStmt, name), nil); err == nil {
+			err = delErr
+		}
+	}()
+
+	if values, err = c.queryValues(ctx, fmt.Sprintf(planQuery, name)); err != nil {
+		return "", err
+	}
+	return formatPlan(values), nil
+}
+
+// formatPlan formats the explain plan table rows (level, operator name, details, table name, estimated row count)
+// as indented operator tree.
+func formatPlan(rows [][]driver.Value) string {
+	buf := new(bytes.Buffer)
+	for _, values := range rows {
+		level, _ := values[0].(int64)
+		if level > 0 {
+			buf.WriteString(strings.Repeat("  ", int(level-1)))
+		}
+		fmt.Fprint(buf, values[1])
+		if table, ok := values[3].(string); ok && table != "" {
+			fmt.Fprintf(buf, " %s", table)
+		}
+		if details, ok := values[2].(string); ok && details != "" {
+			fmt.Fprintf(buf, " (%s)", strings.Join(strings.Fields(details), " "))
+		}
+		if values[4] != nil {
+			fmt.Fprintf(buf, " rows: %v", values[4])
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
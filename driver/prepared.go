@@ -0,0 +1,107 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"strings"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+// stmtCacheKey returns the statement cache key of a statement query prepared in schema.
+func stmtCacheKey(schema Identifier, query string) string {
+	return string(schema) + "\x00" + strings.TrimSpace(query)
+}
+
+// nextUse returns the next statement usage sequence number.
+func (c *conn) nextUse() uint64 {
+	c.useSeq++
+	return c.useSeq
+}
+
+// lruStmt returns the least recently used statement holding a statement handle
+// excluding keep and statements with not flushed bulk records.
+func (c *conn) lruStmt(keep *stmt) *stmt {
+	var lru *stmt
+	for s := range c.stmts {
+		if s == keep || s.dropped || len(s.args) != 0 {
+			continue
+		}
+		if lru == nil || s.lastUse < lru.lastUse {
+			lru = s
+		}
+	}
+	return lru
+}
+
+// limitPrepared drops the handles of the least recently used statements
+// if the number of prepared statements exceeds the connector limit.
+func (c *conn) limitPrepared(keep *stmt) error {
+	if c.maxPrepared <= 0 {
+		return nil
+	}
+	for c._stats.numPrepared() > c.maxPrepared {
+		s := c.lruStmt(keep)
+		if s == nil { // all statements in use
+			return nil
+		}
+		if err := s.drop(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// drop releases the statement handle. The statement is prepared again on next usage.
+func (s *stmt) drop() error {
+	if err := s.session.DropStatementID(s.pr.StmtID()); err != nil {
+		return err
+	}
+	s.dropped = true
+	s.conn._stats.trackPrepared(-1)
+	return nil
+}
+
+// prepare prepares the statement query in the schema the statement was prepared in initially.
+func (s *stmt) prepare() (pr *p.PrepareResult, err error) {
+	err = s.conn.inSchema(s.schema, func() error { pr, err = s.session.Prepare(s.query); return err })
+	return pr, err
+}
+
+// use marks the statement as recently used and prepares the statement again in case
+// the statement handle was dropped or the result lag hint needs to be removed (see SetReadYourWrites).
+func (s *stmt) use() error {
+	s.lastUse = s.conn.nextUse()
+	if s.currentQuery != "" && s.conn.wrote { // read your writes: prepare the statement without result lag hint
+		s.query, s.currentQuery = s.currentQuery, ""
+		if err := s.reprepare(); err != nil {
+			return err
+		}
+	}
+	if !s.dropped {
+		return nil
+	}
+	pr, err := s.prepare()
+	if err != nil {
+		return err
+	}
+	s.pr = pr
+	s.maxBulkNum = s.conn.maxBulkNum(pr.NumField())
+	s.dropped = false
+	s.conn._stats.trackPrepared(1)
+	return s.conn.limitPrepared(s)
+}
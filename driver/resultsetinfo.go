@@ -0,0 +1,64 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql/driver"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+type resultSetInfosCtxKey struct{}
+
+/*
+WithResultSetInfos returns a copy of the context storing the column descriptions of all result sets of
+a query in infos, so that the shape of all result sets is known before iterating the rows.
+For procedure calls infos describes the table output parameters in parameter order, for queries
+the result set of the query. As the database returns the metadata of all result sets with the execution,
+infos is set when the query returns.
+
+Example:
+
+	var infos []driver.ResultSetInfo
+	rows, err := db.QueryContext(driver.WithResultSetInfos(ctx, &infos), "call myproc(?)", 1)
+	// len(infos) is the number of table output parameters of myproc
+*/
+func WithResultSetInfos(ctx context.Context, infos *[]ResultSetInfo) context.Context {
+	return context.WithValue(ctx, resultSetInfosCtxKey{}, infos)
+}
+
+// resultSetFielder is the interface wrapping the ResultSetFields method of query result sets.
+type resultSetFielder interface {
+	ResultSetFields() [][]p.Field
+}
+
+// setResultSetInfos stores the result set descriptions of rows if the context does request it.
+func setResultSetInfos(ctx context.Context, rows driver.Rows) {
+	infos, ok := ctx.Value(resultSetInfosCtxKey{}).(*[]ResultSetInfo)
+	if !ok || infos == nil {
+		return
+	}
+	*infos = nil
+	r, ok := rows.(resultSetFielder)
+	if !ok {
+		return
+	}
+	for _, fields := range r.ResultSetFields() {
+		*infos = append(*infos, newResultSetInfo(fields))
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"time"
+
+	"github.com/SAP/go-hdb/driver/sqltrace"
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+// defaultRetriableErrorCodes are the error codes of transient database errors.
+var defaultRetriableErrorCodes = []int{
+	HdbErrLockWaitTimeout,      // transaction rolled back by lock wait timeout
+	HdbErrDeadlock,             // transaction rolled back by detected deadlock
+	HdbErrSerializationFailure, // transaction serialization failure
+	HdbErrResourceBusy,         // resource busy and acquire with NOWAIT specified
+}
+
+/*
+DefaultRetriableErrorCodes returns the codes of the database errors considered retriable by default:
+- HdbErrLockWaitTimeout (131): transaction rolled back by lock wait timeout
+- HdbErrDeadlock (133): transaction rolled back by detected deadlock
+- HdbErrSerializationFailure (138): transaction serialization failure
+- HdbErrResourceBusy (146): resource busy and acquire with NOWAIT specified
+*/
+func DefaultRetriableErrorCodes() []int { return append([]int(nil), defaultRetriableErrorCodes...) }
+
+/*
+IsRetriable returns true if err is an Error reporting a transient database error (see DefaultRetriableErrorCodes).
+IsRetriable does not consider error codes set via Connector.SetRetriableErrorCodes, please use Connector.IsRetriable instead.
+*/
+func IsRetriable(err error) bool { return p.HasErrorCode(err, defaultRetriableErrorCodes...) }
+
+// hasReaderArgs returns true if any argument is an io.Reader (e.g. a lob), which cannot be read again.
+func hasReaderArgs(args []driver.NamedValue) bool {
+	for _, arg := range args {
+		if _, ok := arg.Value.(io.Reader); ok {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+retry executes f and, if idempotent, re-executes it up to maxRetries times as long as f fails with a
+retriable error (see SetMaxRetries). The wait time before a retry starts with the retry backoff and doubles with each retry.
+Statements executed within a transaction are not retried, as the database did roll back the whole transaction.
+*/
+func (c *conn) retry(ctx context.Context, idempotent bool, f func() error) error {
+	if !idempotent || c.maxRetries == 0 || c.session.InTx() {
+		return f()
+	}
+	backoff := c.retryBackoff
+	for i := 0; ; i++ {
+		err := f()
+		if err == nil || i == c.maxRetries || !p.HasErrorCode(err, c.retriableErrorCodes...) || c.session.IsBad() {
+			return err
+		}
+		sqltrace.Tracef("retry %d of %d after %s: %s", i+1, c.maxRetries, backoff, err)
+		c._stats.trackRetry()
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+)
+
+var errInvalidRowID = errors.New("rowid: invalid zero value")
+
+/*
+RowID is an opaque token representing the row id of a database row. It can be used as scan target
+for the $rowid$ pseudo column (BIGINT) as well as for ROWID and UROWID columns and binds back
+as parameter value of the same type, e.g.:
+
+	var id driver.RowID
+	db.QueryRow("select \"$rowid$\" from mytable where ...").Scan(&id)
+	db.Exec("update mytable set ... where \"$rowid$\" = ?", id)
+
+Please note that per database semantics row ids are only stable within the same session
+(transaction). Rows might get new row ids by database operations like table reorganization
+or delta merge, so a row id must not be stored for later use.
+*/
+type RowID struct {
+	v driver.Value // int64 ($rowid$) or []byte (ROWID, UROWID)
+}
+
+// Scan implements the database/sql/Scanner interface.
+func (r *RowID) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case int64:
+		r.v = src
+	case []byte:
+		b := make([]byte, len(src))
+		copy(b, src)
+		r.v = b
+	default:
+		return fmt.Errorf("rowid: invalid data type %T", src)
+	}
+	return nil
+}
+
+// Value implements the database/sql/Valuer interface.
+func (r RowID) Value() (driver.Value, error) {
+	if r.v == nil {
+		return nil, errInvalidRowID
+	}
+	return r.v, nil
+}
+
+// String implements the Stringer interface.
+func (r RowID) String() string {
+	switch v := r.v.(type) {
+	case []byte:
+		return fmt.Sprintf("%x", v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testRowIDScan(t *testing.T) {
+	testData := []struct {
+		src interface{}
+		str string
+	}{
+		{int64(4711), "4711"},
+		{[]byte{0x01, 0x02, 0xff}, "0102ff"},
+	}
+
+	for i, d := range testData {
+		var r RowID
+		if err := r.Scan(d.src); err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		v, err := r.Value()
+		if err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		if !reflect.DeepEqual(v, d.src) {
+			t.Fatalf("test %d: value %v - expected %v", i, v, d.src)
+		}
+		if r.String() != d.str {
+			t.Fatalf("test %d: string %s - expected %s", i, r.String(), d.str)
+		}
+	}
+}
+
+func testRowIDInvalid(t *testing.T) {
+	var r RowID
+	if err := r.Scan("4711"); err == nil {
+		t.Fatal("invalid data type error expected")
+	}
+	if _, err := r.Value(); err != errInvalidRowID {
+		t.Fatalf("error %v - expected %v", err, errInvalidRowID)
+	}
+}
+
+func TestRowID(t *testing.T) {
+	tests := []struct {
+		name string
+		fct  func(t *testing.T)
+	}{
+		{"rowIDScan", testRowIDScan},
+		{"rowIDInvalid", testRowIDInvalid},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.fct(t)
+		})
+	}
+}
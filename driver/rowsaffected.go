@@ -0,0 +1,57 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql/driver"
+)
+
+type rowsAffectedCtxKey struct{}
+
+/*
+WithRowsAffected returns a copy of the context storing the number of rows affected by a statement
+executed via Query (e.g. a MERGE or UPSERT statement or a DML statement with a result set) in n,
+so that both the result rows and the affected rows count are available without knowing the statement kind.
+n is set when the query returns (0 for queries not modifying data).
+
+Example:
+
+	var n int64
+	rows, err := db.QueryContext(driver.WithRowsAffected(ctx, &n), "merge into ...")
+*/
+func WithRowsAffected(ctx context.Context, n *int64) context.Context {
+	return context.WithValue(ctx, rowsAffectedCtxKey{}, n)
+}
+
+// rowsAffecter is the interface wrapping the RowsAffected method of query result sets.
+type rowsAffecter interface {
+	RowsAffected() int64
+}
+
+// setRowsAffected stores the rows affected count of rows if the context does request it.
+func setRowsAffected(ctx context.Context, rows driver.Rows) {
+	n, ok := ctx.Value(rowsAffectedCtxKey{}).(*int64)
+	if !ok || n == nil {
+		return
+	}
+	if r, ok := rows.(rowsAffecter); ok {
+		*n = r.RowsAffected()
+	} else {
+		*n = 0
+	}
+}
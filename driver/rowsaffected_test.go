@@ -0,0 +1,74 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+)
+
+func testWithRowsAffected(db *sql.DB, t *testing.T) {
+	table := RandomIdentifier("rowsAffected_")
+	if _, err := db.Exec(fmt.Sprintf("create table %s (i integer primary key, s nvarchar(10))", table)); err != nil {
+		t.Fatal(err)
+	}
+
+	// direct query and prepared query
+	for i, args := range [][]interface{}{nil, {2, "B"}} {
+		query := fmt.Sprintf("upsert %s values (1, 'A') with primary key", table)
+		if args != nil {
+			query = fmt.Sprintf("upsert %s values (?, ?) with primary key", table)
+		}
+		n := int64(-1)
+		rows, err := db.QueryContext(WithRowsAffected(context.Background(), &n), query, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows.Close()
+		if n != 1 {
+			t.Fatalf("test %d: rows affected %d - expected %d", i, n, 1)
+		}
+	}
+
+	// select
+	n := int64(-1)
+	rows, err := db.QueryContext(WithRowsAffected(context.Background(), &n), fmt.Sprintf("select * from %s", table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows.Close()
+	if n != 0 {
+		t.Fatalf("rows affected %d - expected %d", n, 0)
+	}
+}
+
+func TestRowsAffected(t *testing.T) {
+	tests := []struct {
+		name string
+		fct  func(db *sql.DB, t *testing.T)
+	}{
+		{"withRowsAffected", testWithRowsAffected},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.fct(TestDB, t)
+		})
+	}
+}
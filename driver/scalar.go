@@ -0,0 +1,101 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Queryer is implemented by sql.DB, sql.Conn and sql.Tx.
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// ErrScalarMultipleRows is returned by QueryScalar if the query returns more than one row.
+var ErrScalarMultipleRows = errors.New("query scalar: multiple rows returned")
+
+/*
+QueryScalar executes a query which is expected to return exactly one row with exactly one column
+and scans the column value into dest, e.g.
+
+	var cnt int64
+	err := driver.QueryScalar(ctx, db, &cnt, "select count(*) from t")
+
+The value is converted like in sql.Rows.Scan, so dest might be any scan destination type
+(e.g. *int64, *string, *Decimal, *sql.NullString, ...).
+QueryScalar returns sql.ErrNoRows if the query does not return any row, ErrScalarMultipleRows if the
+query returns more than one row and an error if the number of columns is not one.
+The rows are closed before QueryScalar returns.
+*/
+func QueryScalar(ctx context.Context, q Queryer, dest interface{}, query string, args ...interface{}) error {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	if len(columns) != 1 {
+		return fmt.Errorf("query scalar: number of columns %d - expected 1", len(columns))
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	if err := rows.Scan(dest); err != nil {
+		return fmt.Errorf("query scalar: column %s: %w", columns[0], err)
+	}
+	if rows.Next() {
+		return ErrScalarMultipleRows
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+const existsQuery = "select 1 from dummy where exists (%s)"
+
+/*
+Exists returns true if the query returns at least one row.
+The query needs to be a select statement. It is executed as subquery of an EXISTS predicate,
+so that the database stops the evaluation after the first matching row and does not
+materialize more than one row, e.g.
+
+	ok, err := driver.Exists(ctx, db, "select * from orders where customer = ?", customer)
+*/
+func Exists(ctx context.Context, q Queryer, query string, args ...interface{}) (bool, error) {
+	var i int
+	switch err := QueryScalar(ctx, q, &i, fmt.Sprintf(existsQuery, strings.TrimRight(query, " \t\r\n;")), args...); err {
+	case nil:
+		return true, nil
+	case sql.ErrNoRows:
+		return false, nil
+	default:
+		return false, err
+	}
+}
@@ -0,0 +1,295 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	timeReflectType         = reflect.TypeOf((*time.Time)(nil)).Elem()
+	decimalReflectType      = reflect.TypeOf((*Decimal)(nil)).Elem()
+	lobReflectType          = reflect.TypeOf((*Lob)(nil)).Elem()
+	decimalArrayReflectType = reflect.TypeOf((*DecimalArray)(nil)).Elem()
+	stPointReflectType      = reflect.TypeOf((*STPoint)(nil)).Elem()
+)
+
+// charLobTypeNames are the database type names of character based lobs.
+var charLobTypeNames = map[string]bool{"CLOB": true, "NCLOB": true, "TEXT": true, "BINTEXT": true}
+
+// anyScanner scans a column value into its natural go type based on the column scan type.
+type anyScanner struct {
+	ct *sql.ColumnType
+	v  interface{}
+}
+
+// Scan implements the database/sql/Scanner interface.
+func (s *anyScanner) Scan(src interface{}) error {
+	if src == nil {
+		s.v = nil
+		return nil
+	}
+
+	scanType := s.ct.ScanType()
+	switch scanType {
+	case timeReflectType:
+		t, ok := src.(time.Time)
+		if !ok {
+			return fmt.Errorf("invalid time value type %T", src)
+		}
+		s.v = t
+		return nil
+	case decimalReflectType:
+		d := new(Decimal)
+		if err := d.Scan(src); err != nil {
+			return err
+		}
+		s.v = d
+		return nil
+	case lobReflectType:
+		b := new(bytes.Buffer)
+		if err := NewLob(nil, b).Scan(src); err != nil {
+			return err
+		}
+		if charLobTypeNames[s.ct.DatabaseTypeName()] {
+			s.v = b.String()
+		} else {
+			s.v = b.Bytes()
+		}
+		return nil
+	case decimalArrayReflectType:
+		a := new(DecimalArray)
+		if err := a.Scan(src); err != nil {
+			return err
+		}
+		s.v = a
+		return nil
+	case stPointReflectType:
+		var pt STPoint
+		if err := pt.Scan(src); err != nil {
+			return err
+		}
+		s.v = pt
+		return nil
+	}
+
+	switch scanType.Kind() {
+	case reflect.Uint8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i sql.NullInt64
+		if err := i.Scan(src); err != nil {
+			return err
+		}
+		s.v = i.Int64
+	case reflect.Float32, reflect.Float64:
+		var f sql.NullFloat64
+		if err := f.Scan(src); err != nil {
+			return err
+		}
+		s.v = f.Float64
+	case reflect.String:
+		var str sql.NullString
+		if err := str.Scan(src); err != nil {
+			return err
+		}
+		s.v = str.String
+	default:
+		if b, ok := src.([]byte); ok { // src might be reused by the driver
+			s.v = append([]byte(nil), b...)
+		} else {
+			s.v = src
+		}
+	}
+	return nil
+}
+
+/*
+ScanAny scans the values of the current row into the natural go type of the columns
+based on the column scan type (see sql.ColumnType.ScanType), e.g. for a generic row dumper
+or a REPL-style tool:
+- integer types are scanned as int64
+- floating point types are scanned as float64
+- date and time types are scanned as time.Time
+- character types (including character based lobs) are scanned as string
+- binary types (including binary lobs) are scanned as []byte
+- decimal types are scanned as *Decimal
+- decimal digit arrays are scanned as *DecimalArray
+- spatial points (ST_POINT) are scanned as STPoint
+NULL values are scanned as nil.
+
+Whereas sql.Rows.Scan into *interface{} returns the raw driver values (e.g. []byte for
+character and decimal types), ScanAny resolves the value type via the column metadata.
+The number of dest values needs to match the number of columns.
+
+Example:
+
+	values := make([]interface{}, len(columns))
+	dest := make([]*interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := driver.ScanAny(rows, dest...); err != nil {
+			...
+		}
+	}
+*/
+func ScanAny(rows *sql.Rows, dest ...*interface{}) error {
+	cts, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+	if len(dest) != len(cts) {
+		return fmt.Errorf("scan any: expected %d destination arguments - got %d", len(cts), len(dest))
+	}
+	scanners := make([]interface{}, len(cts))
+	for i, ct := range cts {
+		scanners[i] = &anyScanner{ct: ct}
+	}
+	if err := rows.Scan(scanners...); err != nil {
+		return err
+	}
+	for i, s := range scanners {
+		*dest[i] = s.(*anyScanner).v
+	}
+	return nil
+}
+
+// textTimeLayouts are the ISO-8601 layouts of the date and time types (default: timestamp).
+var textTimeLayouts = map[string]string{
+	"DATE":       "2006-01-02",
+	"DAYDATE":    "2006-01-02",
+	"TIME":       "15:04:05",
+	"SECONDTIME": "15:04:05",
+	"SECONDDATE": "2006-01-02T15:04:05",
+}
+
+const textTimestampLayout = "2006-01-02T15:04:05.9999999"
+
+// textScanner scans a column value into its textual representation based on the column scan type.
+type textScanner struct {
+	ct *sql.ColumnType
+	b  []byte
+}
+
+// Scan implements the database/sql/Scanner interface.
+func (s *textScanner) Scan(src interface{}) error {
+	if src == nil {
+		s.b = nil
+		return nil
+	}
+
+	switch s.ct.ScanType() {
+	case timeReflectType:
+		t, ok := src.(time.Time)
+		if !ok {
+			return fmt.Errorf("invalid time value type %T", src)
+		}
+		layout, ok := textTimeLayouts[s.ct.DatabaseTypeName()]
+		if !ok {
+			layout = textTimestampLayout
+		}
+		s.b = t.AppendFormat(nil, layout)
+		return nil
+	case decimalReflectType:
+		switch src := src.(type) {
+		case float64: // decimal float mode DecimalFloatRound
+			s.b = strconv.AppendFloat(nil, src, 'g', -1, 64)
+		case string: // decimal float mode DecimalString
+			s.b = []byte(src)
+		case []byte:
+			d := new(Decimal)
+			if err := d.Scan(src); err != nil {
+				return err
+			}
+			s.b = []byte(d.Text(decimalScale(src)))
+		case *big.Rat: // fixed decimal
+			_, scale, _ := s.ct.DecimalSize()
+			s.b = []byte(src.FloatString(int(scale)))
+		default:
+			return fmt.Errorf("invalid decimal value type %T", src)
+		}
+		return nil
+	}
+
+	as := &anyScanner{ct: s.ct}
+	if err := as.Scan(src); err != nil {
+		return err
+	}
+	switch v := as.v.(type) {
+	case int64:
+		s.b = strconv.AppendInt(nil, v, 10)
+	case float64:
+		s.b = strconv.AppendFloat(nil, v, 'g', -1, 64)
+	case string:
+		s.b = []byte(v)
+	case []byte:
+		b := make([]byte, hex.EncodedLen(len(v)))
+		hex.Encode(b, v)
+		s.b = bytes.ToUpper(b)
+	case *DecimalArray:
+		s.b = []byte(fmt.Sprint(v.Strings()))
+	case STPoint:
+		s.b = []byte(fmt.Sprintf("POINT (%s %s)", strconv.FormatFloat(v.X, 'g', -1, 64), strconv.FormatFloat(v.Y, 'g', -1, 64)))
+	default:
+		s.b = []byte(fmt.Sprint(v))
+	}
+	return nil
+}
+
+/*
+ScanText scans the values of the current row into their textual representation based on the
+column type, e.g. for a generic export tool:
+- integer and floating point types are formatted as decimal numbers
+- decimal types are formatted with their scale (e.g. '1.50')
+- date and time types are formatted in ISO-8601 format (e.g. '2006-01-02T15:04:05.1234567')
+- character types (including character based lobs) are returned as UTF-8 bytes
+- binary types (including binary lobs) are formatted as upper case hexadecimal digits
+- spatial points (ST_POINT) are formatted in well-known text format (e.g. 'POINT (1 2)')
+NULL values are scanned as nil.
+
+As BOOLEAN values are transferred as integers (see Boolean values), they are formatted as '0' and '1'.
+Whereas sql.Rows.Scan into *[]byte returns the raw driver values for some types (e.g. the decimal128
+encoding of decimals), ScanText resolves the representation via the column metadata.
+The number of dest values needs to match the number of columns. The returned slices are owned by the caller.
+*/
+func ScanText(rows *sql.Rows, dest ...*[]byte) error {
+	cts, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+	if len(dest) != len(cts) {
+		return fmt.Errorf("scan text: expected %d destination arguments - got %d", len(cts), len(dest))
+	}
+	scanners := make([]interface{}, len(cts))
+	for i, ct := range cts {
+		scanners[i] = &textScanner{ct: ct}
+	}
+	if err := rows.Scan(scanners...); err != nil {
+		return err
+	}
+	for i, s := range scanners {
+		*dest[i] = s.(*textScanner).b
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+)
+
+const currentSchemaQuery = "select current_schema from dummy"
+
+type schemaCtxKey struct{}
+
+/*
+WithSchema returns a copy of the context with the schema set.
+Statements prepared or executed directly with the returned context resolve unqualified database
+object names in schema instead of the current schema of the session: the current schema is set to
+schema (SET SCHEMA) for the statement and restored afterwards, so that one connection can target
+different schemas per statement without changing the session schema for subsequent statements.
+As object names of prepared statements are resolved on preparation, the schema is applied to
+the preparation of statements (including a re-preparation, see Conn.Invalidate) and not to their
+execution.
+*/
+func WithSchema(ctx context.Context, schema Identifier) context.Context {
+	return context.WithValue(ctx, schemaCtxKey{}, schema)
+}
+
+// contextSchema returns the schema set by WithSchema (empty if not set).
+func contextSchema(ctx context.Context) Identifier {
+	schema, _ := ctx.Value(schemaCtxKey{}).(Identifier)
+	return schema
+}
+
+// currentSchema returns the current schema of the session.
+func (c *conn) currentSchema() (Identifier, error) {
+	rows, err := c.session.QueryDirect(currentSchemaQuery)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	values := make([]driver.Value, 1)
+	if err := rows.Next(values); err != nil {
+		if err == io.EOF {
+			return "", fmt.Errorf("current schema: no row returned")
+		}
+		return "", err
+	}
+	switch v := values[0].(type) {
+	case []byte:
+		return Identifier(v), nil
+	case string:
+		return Identifier(v), nil
+	default:
+		return "", fmt.Errorf("current schema: invalid value type %T", v)
+	}
+}
+
+// inSchema executes f with schema set as current schema of the session and restores
+// the current schema afterwards. If schema is empty f is executed without changing the schema.
+func (c *conn) inSchema(schema Identifier, f func() error) error {
+	if schema == "" {
+		return f()
+	}
+	prev, err := c.currentSchema()
+	if err != nil {
+		return err
+	}
+	if prev == schema {
+		return f()
+	}
+	if _, err := c.session.ExecDirect(fmt.Sprintf(defaultSchema, schema)); err != nil {
+		return err
+	}
+	err = f()
+	if _, restoreErr := c.session.ExecDirect(fmt.Sprintf(defaultSchema, prev)); err == nil {
+		err = restoreErr
+	}
+	return err
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+)
+
+// SessionInfo contains the metadata of a database session.
+type SessionInfo struct {
+	ConnectionID   int64  // Database connection id (CURRENT_CONNECTION).
+	User           string // Current user (CURRENT_USER).
+	SessionUser    string // Session user (SESSION_USER).
+	Schema         string // Current schema (CURRENT_SCHEMA).
+	IsolationLevel string // Transaction isolation level (CURRENT_TRANSACTION_ISOLATION_LEVEL).
+}
+
+const sessionInfoQuery = "select current_connection, current_user, session_user, current_schema, current_transaction_isolation_level from dummy"
+
+func (c *conn) sessionInfo(ctx context.Context) (*SessionInfo, error) {
+	rows, err := c.QueryContext(ctx, sessionInfoQuery, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make([]driver.Value, len(rows.Columns()))
+	if err := rows.Next(values); err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("session info: no row returned")
+		}
+		return nil, err
+	}
+
+	info := &SessionInfo{}
+	connectionID, ok := values[0].(int64)
+	if !ok {
+		return nil, fmt.Errorf("session info: invalid connection id type %T", values[0])
+	}
+	info.ConnectionID = connectionID
+	for i, s := range []*string{&info.User, &info.SessionUser, &info.Schema, &info.IsolationLevel} {
+		switch v := values[i+1].(type) {
+		case []byte:
+			*s = string(v)
+		case nil:
+		default:
+			return nil, fmt.Errorf("session info: invalid value type %T of column %s", v, rows.Columns()[i+1])
+		}
+	}
+	return info, nil
+}
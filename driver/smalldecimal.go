@@ -0,0 +1,117 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+/*
+SmallDecimal is a scan target for decimal database values (SMALLDECIMAL, DECIMAL) retaining the scale
+(number of fractional digits) of the value as transferred by the database. Whereas the big.Rat
+representation of Decimal normalizes the value (e.g. 1.50 to 3/2), String reconstructs the exact
+representation stored by the database including trailing zeros (e.g. '1.50').
+
+On binding a SmallDecimal the value is sent with its scale, so that the trailing zeros are kept
+by floating point decimal columns (SMALLDECIMAL, DECIMAL without precision).
+As a NULL value cannot be scanned into SmallDecimal, please use NullSmallDecimal for nullable columns.
+SmallDecimal does not support the decimal float mode DecimalFloatRound.
+*/
+type SmallDecimal struct {
+	Decimal Decimal
+	Scale   int
+}
+
+// Scan implements the database/sql/Scanner interface.
+func (d *SmallDecimal) Scan(src interface{}) error {
+	if s, ok := src.(string); ok { // decimal float mode DecimalString
+		if err := d.Decimal.Scan(s); err != nil {
+			return err
+		}
+		d.Scale = 0
+		if i := strings.IndexByte(s, '.'); i >= 0 {
+			d.Scale = len(s) - i - 1
+		}
+		return nil
+	}
+
+	b, ok := src.([]byte)
+	if !ok {
+		if src == nil {
+			return fmt.Errorf("decimal: cannot scan NULL value into %T - use NullSmallDecimal", d)
+		}
+		return fmt.Errorf("decimal: invalid data type %T", src)
+	}
+	if err := d.Decimal.Scan(b); err != nil {
+		return err
+	}
+	d.Scale = decimalScale(b)
+	return nil
+}
+
+// Value implements the database/sql/Valuer interface.
+func (d SmallDecimal) Value() (driver.Value, error) {
+	if d.Scale <= 0 || d.Scale > -dec128MinExp {
+		return d.Decimal.Value()
+	}
+	// mantissa with scale fractional digits
+	x := new(big.Rat).Mul((*big.Rat)(&d.Decimal), new(big.Rat).SetInt(exp10(d.Scale)))
+	if !x.IsInt() || digits10(new(big.Int).Abs(x.Num())) > dec128Digits {
+		return d.Decimal.Value() // scale not sufficient or mantissa too large: normalize
+	}
+	m := new(big.Int).Abs(x.Num())
+	return encodeDecimal(m, x.Sign() < 0, -d.Scale)
+}
+
+// String implements the fmt.Stringer interface formatting the decimal with its scale.
+func (d *SmallDecimal) String() string {
+	if d == nil {
+		return "<nil>"
+	}
+	return d.Decimal.Text(d.Scale)
+}
+
+// NullSmallDecimal represents a SmallDecimal that may be null.
+// NullSmallDecimal implements the Scanner interface so it can be used as a scan destination, similar to sql.NullString.
+type NullSmallDecimal struct {
+	SmallDecimal SmallDecimal
+	Valid        bool // Valid is true if SmallDecimal is not NULL
+}
+
+// Scan implements the database/sql/Scanner interface.
+func (n *NullSmallDecimal) Scan(src interface{}) error {
+	if src == nil {
+		n.SmallDecimal, n.Valid = SmallDecimal{}, false
+		return nil
+	}
+	if err := n.SmallDecimal.Scan(src); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// Value implements the database/sql/Valuer interface.
+func (n NullSmallDecimal) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.SmallDecimal.Value()
+}
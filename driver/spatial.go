@@ -0,0 +1,369 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+/*
+Spatial values (ST_GEOMETRY, ST_POINT) are transferred between client and database
+as binary lobs in (extended) well-known binary format (WKB, EWKB).
+*/
+
+// geometryType is the WKB geometry type.
+type geometryType uint32
+
+// WKB geometry types.
+const (
+	gtPoint              geometryType = 1
+	gtLineString         geometryType = 2
+	gtPolygon            geometryType = 3
+	gtMultiPoint         geometryType = 4
+	gtMultiLineString    geometryType = 5
+	gtMultiPolygon       geometryType = 6
+	gtGeometryCollection geometryType = 7
+)
+
+var geometryTypeNames = map[geometryType]string{
+	gtPoint:              "Point",
+	gtLineString:         "LineString",
+	gtPolygon:            "Polygon",
+	gtMultiPoint:         "MultiPoint",
+	gtMultiLineString:    "MultiLineString",
+	gtMultiPolygon:       "MultiPolygon",
+	gtGeometryCollection: "GeometryCollection",
+}
+
+func (t geometryType) String() string {
+	if name, ok := geometryTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("geometryType(%d)", t)
+}
+
+// EWKB flags.
+const (
+	ewkbZ    = 0x80000000
+	ewkbM    = 0x40000000
+	ewkbSRID = 0x20000000
+)
+
+// WKB byte order.
+const (
+	wkbXDR byte = 0 // big endian
+	wkbNDR byte = 1 // little endian
+)
+
+var errInvalidWKB = errors.New("spatial: invalid well-known binary")
+
+/*
+geometry is the driver internal representation of a spatial value.
+- point:                    coord
+- linestring, multipoint:   elems are points
+- polygon, multilinestring: elems are linestrings
+- multipolygon:             elems are polygons
+- geometrycollection:       elems are geometries
+*/
+type geometry struct {
+	typ   geometryType
+	srid  int32 // 0: not set
+	z, m  bool
+	coord []float64 // point coordinates (nil: empty point)
+	elems []*geometry
+}
+
+func (g *geometry) dim() int {
+	dim := 2
+	if g.z {
+		dim++
+	}
+	if g.m {
+		dim++
+	}
+	return dim
+}
+
+type wkbDecoder struct {
+	b     []byte
+	order binary.ByteOrder
+	err   error
+}
+
+func (d *wkbDecoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if len(d.b) < n {
+		d.err = errInvalidWKB
+		return nil
+	}
+	b := d.b[:n]
+	d.b = d.b[n:]
+	return b
+}
+
+func (d *wkbDecoder) byteOrder() {
+	b := d.next(1)
+	if b == nil {
+		return
+	}
+	switch b[0] {
+	case wkbXDR:
+		d.order = binary.BigEndian
+	case wkbNDR:
+		d.order = binary.LittleEndian
+	default:
+		d.err = errInvalidWKB
+	}
+}
+
+func (d *wkbDecoder) uint32() uint32 {
+	b := d.next(4)
+	if b == nil {
+		return 0
+	}
+	return d.order.Uint32(b)
+}
+
+func (d *wkbDecoder) float64() float64 {
+	b := d.next(8)
+	if b == nil {
+		return 0
+	}
+	return math.Float64frombits(d.order.Uint64(b))
+}
+
+// header decodes the byte order, geometry type, dimensions and SRID of a geometry.
+func (d *wkbDecoder) header() *geometry {
+	d.byteOrder()
+	code := d.uint32()
+	if d.err != nil {
+		return nil
+	}
+
+	g := &geometry{}
+	// EWKB flags
+	g.z = code&ewkbZ != 0
+	g.m = code&ewkbM != 0
+	if code&ewkbSRID != 0 {
+		g.srid = int32(d.uint32())
+	}
+	code &= 0x0fffffff
+	// ISO WKB dimensions
+	switch code / 1000 {
+	case 1:
+		g.z = true
+	case 2:
+		g.m = true
+	case 3:
+		g.z, g.m = true, true
+	}
+	g.typ = geometryType(code % 1000)
+	return g
+}
+
+func (d *wkbDecoder) geometry() *geometry {
+	g := d.header()
+	if g == nil {
+		return nil
+	}
+
+	switch g.typ {
+	case gtPoint:
+		d.point(g)
+	case gtLineString:
+		d.points(g)
+	case gtPolygon:
+		n := int(d.uint32())
+		for i := 0; i < n && d.err == nil; i++ {
+			ring := &geometry{typ: gtLineString, z: g.z, m: g.m}
+			d.points(ring)
+			g.elems = append(g.elems, ring)
+		}
+	case gtMultiPoint, gtMultiLineString, gtMultiPolygon, gtGeometryCollection:
+		n := int(d.uint32())
+		for i := 0; i < n && d.err == nil; i++ {
+			g.elems = append(g.elems, d.geometry())
+		}
+	default:
+		d.err = fmt.Errorf("spatial: unsupported geometry type %d", g.typ)
+	}
+	return g
+}
+
+func (d *wkbDecoder) point(g *geometry) {
+	coord := make([]float64, g.dim())
+	empty := true
+	for i := range coord {
+		coord[i] = d.float64()
+		if !math.IsNaN(coord[i]) {
+			empty = false
+		}
+	}
+	if !empty {
+		g.coord = coord
+	}
+}
+
+func (d *wkbDecoder) points(g *geometry) {
+	n := int(d.uint32())
+	for i := 0; i < n && d.err == nil; i++ {
+		pt := &geometry{typ: gtPoint, z: g.z, m: g.m}
+		d.point(pt)
+		g.elems = append(g.elems, pt)
+	}
+}
+
+// decodeWKB decodes a spatial value in (extended) well-known binary format.
+func decodeWKB(b []byte) (*geometry, error) {
+	d := &wkbDecoder{b: b}
+	g := d.geometry()
+	if d.err != nil {
+		return nil, d.err
+	}
+	return g, nil
+}
+
+// scanSpatialBytes reads the binary representation of a spatial database value.
+func scanSpatialBytes(src interface{}) ([]byte, error) {
+	switch src := src.(type) {
+	case []byte:
+		return src, nil
+	case string:
+		return []byte(src), nil
+	case p.WriterSetter:
+		b := new(bytes.Buffer)
+		if err := src.SetWriter(b); err != nil {
+			return nil, err
+		}
+		return b.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("spatial: invalid data type %T", src)
+	}
+}
+
+/*
+GeoJSON is a scan target for spatial database values (ST_GEOMETRY, ST_POINT) converting
+the geometry into a GeoJSON (RFC 7946) geometry object.
+
+The GeoJSON format does not support spatial reference systems other than WGS 84. Therefore
+coordinates are returned unchanged as stored in the database and the SRID of the value is
+represented as follows:
+- no SRID or SRID 4326 (WGS 84): no additional member is added to the geometry object
+- any other SRID: a 'crs' member with the EPSG code is added (as defined in the GeoJSON 2008 specification)
+
+Besides spatial columns, GeoJSON can be used as scan target for values already converted
+to GeoJSON by the database (e.g. via ST_AsGeoJSON). A database NULL value is scanned as the JSON null literal.
+*/
+type GeoJSON string
+
+const sridWGS84 = 4326
+
+// Scan implements the database/sql/Scanner interface.
+func (g *GeoJSON) Scan(src interface{}) error {
+	if src == nil {
+		*g = "null"
+		return nil
+	}
+	b, err := scanSpatialBytes(src)
+	if err != nil {
+		return err
+	}
+	if len(b) != 0 && (b[0] == '{' || b[0] == 'n') { // already JSON
+		*g = GeoJSON(b)
+		return nil
+	}
+	geo, err := decodeWKB(b)
+	if err != nil {
+		return err
+	}
+	buf := new(bytes.Buffer)
+	writeGeoJSON(buf, geo, true)
+	*g = GeoJSON(buf.String())
+	return nil
+}
+
+func writeGeoJSON(buf *bytes.Buffer, g *geometry, top bool) {
+	buf.WriteString(`{"type":"`)
+	buf.WriteString(g.typ.String())
+	buf.WriteString(`",`)
+	if g.typ == gtGeometryCollection {
+		buf.WriteString(`"geometries":[`)
+		for i, e := range g.elems {
+			if i != 0 {
+				buf.WriteByte(',')
+			}
+			writeGeoJSON(buf, e, false)
+		}
+		buf.WriteByte(']')
+	} else {
+		buf.WriteString(`"coordinates":`)
+		writeGeoJSONCoords(buf, g)
+	}
+	if top && g.srid != 0 && g.srid != sridWGS84 {
+		fmt.Fprintf(buf, `,"crs":{"type":"name","properties":{"name":"EPSG:%d"}}`, g.srid)
+	}
+	buf.WriteByte('}')
+}
+
+func writeGeoJSONCoords(buf *bytes.Buffer, g *geometry) {
+	buf.WriteByte('[')
+	if g.typ == gtPoint {
+		for i, c := range geoJSONPosition(g) {
+			if i != 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(strconv.FormatFloat(c, 'f', -1, 64))
+		}
+	} else {
+		i := 0
+		for _, e := range g.elems {
+			if e.typ == gtPoint && geoJSONPosition(e) == nil { // skip empty points like envelope does
+				continue
+			}
+			if i != 0 {
+				buf.WriteByte(',')
+			}
+			writeGeoJSONCoords(buf, e)
+			i++
+		}
+	}
+	buf.WriteByte(']')
+}
+
+/*
+geoJSONPosition returns the GeoJSON position of a point or nil for an empty point (NaN coordinates are not valid JSON):
+- GeoJSON does not support measures, so that m is omitted
+- a NaN z coordinate is omitted
+*/
+func geoJSONPosition(g *geometry) []float64 {
+	if len(g.coord) < 2 || math.IsNaN(g.coord[0]) || math.IsNaN(g.coord[1]) {
+		return nil
+	}
+	if g.z && !math.IsNaN(g.coord[2]) {
+		return g.coord[:3]
+	}
+	return g.coord[:2]
+}
@@ -0,0 +1,316 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func testGeoJSON(t *testing.T) {
+	testData := []struct {
+		wkb     string
+		geoJSON GeoJSON
+	}{
+		// POINT(1 2) - little endian
+		{"0101000000000000000000f03f0000000000000040", `{"type":"Point","coordinates":[1,2]}`},
+		// POINT(1 2) - big endian
+		{"00000000013ff00000000000004000000000000000", `{"type":"Point","coordinates":[1,2]}`},
+		// POINT EMPTY
+		{"0101000000000000000000f87f000000000000f87f", `{"type":"Point","coordinates":[]}`},
+		// SRID=3857;POINT(1 2) - EWKB
+		{"0101000020110f0000000000000000f03f0000000000000040", `{"type":"Point","coordinates":[1,2],"crs":{"type":"name","properties":{"name":"EPSG:3857"}}}`},
+		// SRID=4326;POINT(1 2) - EWKB
+		{"0101000020e6100000000000000000f03f0000000000000040", `{"type":"Point","coordinates":[1,2]}`},
+		// LINESTRING(0 0,1 1)
+		{"01020000000200000000000000000000000000000000000000000000000000f03f000000000000f03f", `{"type":"LineString","coordinates":[[0,0],[1,1]]}`},
+		// POLYGON((0 0,1 0,1 1,0 0))
+		{"0103000000010000000400000000000000000000000000000000000000000000000000f03f0000000000000000000000000000f03f000000000000f03f00000000000000000000000000000000",
+			`{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}`},
+		// MULTIPOINT((1 2))
+		{"0104000000010000000101000000000000000000f03f0000000000000040", `{"type":"MultiPoint","coordinates":[[1,2]]}`},
+		// GEOMETRYCOLLECTION(POINT(1 2))
+		{"0107000000010000000101000000000000000000f03f0000000000000040", `{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[1,2]}]}`},
+		// POINT Z(1 2 3) - ISO WKB
+		{"01e9030000000000000000f03f00000000000000400000000000000840", `{"type":"Point","coordinates":[1,2,3]}`},
+		// POINT Z(1 2 NaN) - NaN z coordinate is omitted
+		{"01e9030000000000000000f03f0000000000000040000000000000f87f", `{"type":"Point","coordinates":[1,2]}`},
+		// MULTIPOINT((1 2), EMPTY) - empty point is skipped
+		{"0104000000020000000101000000000000000000f03f00000000000000400101000000000000000000f87f000000000000f87f", `{"type":"MultiPoint","coordinates":[[1,2]]}`},
+	}
+
+	for i, d := range testData {
+		b, err := hex.DecodeString(d.wkb)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var g GeoJSON
+		if err := g.Scan(b); err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		if g != d.geoJSON {
+			t.Fatalf("test %d: value %s - expected %s", i, g, d.geoJSON)
+		}
+	}
+}
+
+func testGeoJSONInvalid(t *testing.T) {
+	testData := []string{
+		"",
+		"02",
+		"0101000000000000000000f03f",         // truncated
+		"0109000000000000000000f03f00000000", // unsupported type
+	}
+
+	for i, d := range testData {
+		b, err := hex.DecodeString(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var g GeoJSON
+		if err := g.Scan(b); err == nil {
+			t.Fatalf("test %d: error expected", i)
+		}
+	}
+}
+
+func testEnvelope(t *testing.T) {
+	testData := []struct {
+		wkb                    string
+		minX, minY, maxX, maxY float64
+		srid                   int32
+	}{
+		// POINT(1 2)
+		{"0101000000000000000000f03f0000000000000040", 1, 2, 1, 2, 0},
+		// SRID=3857;POINT(1 2) - EWKB
+		{"0101000020110f0000000000000000f03f0000000000000040", 1, 2, 1, 2, 3857},
+		// LINESTRING(0 0,1 1)
+		{"01020000000200000000000000000000000000000000000000000000000000f03f000000000000f03f", 0, 0, 1, 1, 0},
+		// POLYGON((0 0,1 0,1 1,0 0))
+		{"0103000000010000000400000000000000000000000000000000000000000000000000f03f0000000000000000000000000000f03f000000000000f03f00000000000000000000000000000000", 0, 0, 1, 1, 0},
+		// GEOMETRYCOLLECTION(POINT(1 2))
+		{"0107000000010000000101000000000000000000f03f0000000000000040", 1, 2, 1, 2, 0},
+		// POINT Z(1 2 3) - ISO WKB
+		{"01e9030000000000000000f03f00000000000000400000000000000840", 1, 2, 1, 2, 0},
+	}
+
+	for i, d := range testData {
+		b, err := hex.DecodeString(d.wkb)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var g Geometry
+		if err := g.Scan(b); err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		minX, minY, maxX, maxY, err := g.Envelope()
+		if err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		if minX != d.minX || minY != d.minY || maxX != d.maxX || maxY != d.maxY {
+			t.Fatalf("test %d: envelope %f %f %f %f - expected %f %f %f %f", i, minX, minY, maxX, maxY, d.minX, d.minY, d.maxX, d.maxY)
+		}
+		srid, err := g.SRID()
+		if err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		if srid != d.srid {
+			t.Fatalf("test %d: srid %d - expected %d", i, srid, d.srid)
+		}
+	}
+
+	// POINT EMPTY
+	b, _ := hex.DecodeString("0101000000000000000000f87f000000000000f87f")
+	if _, _, _, _, err := Geometry(b).Envelope(); err == nil {
+		t.Fatal("empty geometry error expected")
+	}
+}
+
+func testSTPoint(t *testing.T) {
+	testData := []struct {
+		wkb string
+		pt  STPoint
+	}{
+		// POINT(1 2) - little endian
+		{"0101000000000000000000f03f0000000000000040", STPoint{X: 1, Y: 2}},
+		// POINT(1 2) - big endian
+		{"00000000013ff00000000000004000000000000000", STPoint{X: 1, Y: 2}},
+		// SRID=3857;POINT(1 2) - EWKB
+		{"0101000020110f0000000000000000f03f0000000000000040", STPoint{X: 1, Y: 2, SRID: 3857}},
+		// POINT Z(1 2 3) - ISO WKB
+		{"01e9030000000000000000f03f00000000000000400000000000000840", STPoint{X: 1, Y: 2}},
+	}
+
+	for i, d := range testData {
+		b, err := hex.DecodeString(d.wkb)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var pt STPoint
+		if err := pt.Scan(b); err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		if pt != d.pt {
+			t.Fatalf("test %d: point %v - expected %v", i, pt, d.pt)
+		}
+		// round-trip (without SRID)
+		v, err := pt.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var rpt STPoint
+		if err := rpt.Scan(v); err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		if rpt.X != pt.X || rpt.Y != pt.Y || rpt.SRID != 0 {
+			t.Fatalf("test %d: point %v - expected %v", i, rpt, STPoint{X: pt.X, Y: pt.Y})
+		}
+	}
+
+	var pt STPoint
+	for i, d := range []interface{}{
+		nil,
+		mustDecodeHex(t, "0101000000000000000000f87f000000000000f87f"),                                         // POINT EMPTY
+		mustDecodeHex(t, "01020000000200000000000000000000000000000000000000000000000000f03f000000000000f03f"), // LINESTRING
+	} {
+		if err := pt.Scan(d); err == nil {
+			t.Fatalf("test %d: error expected", i)
+		}
+	}
+
+	var npt NullSTPoint
+	if err := npt.Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if npt.Valid {
+		t.Fatal("invalid null point expected")
+	}
+	if v, err := npt.Value(); err != nil || v != nil {
+		t.Fatalf("value %v error %v - expected nil", v, err)
+	}
+}
+
+func testWKT(t *testing.T) {
+	testData := []struct {
+		wkt, exp string
+	}{
+		{"POINT (1 2)", ""},
+		{"point(1.5 -2.5)", "POINT (1.5 -2.5)"},
+		{"POINT Z (1 2 3)", ""},
+		{"POINT(1 2 3)", "POINT Z (1 2 3)"},
+		{"POINT ZM (1 2 3 4)", ""},
+		{"POINT EMPTY", ""},
+		{"SRID=3857;POINT (1 2)", ""},
+		{"LINESTRING (0 0, 1 1, 2 0)", ""},
+		{"POLYGON ((0 0, 4 0, 4 4, 0 0), (1 1, 2 1, 2 2, 1 1))", ""},
+		{"MULTIPOINT ((1 2), (3 4))", ""},
+		{"MULTIPOINT (1 2, 3 4)", "MULTIPOINT ((1 2), (3 4))"},
+		{"MULTILINESTRING ((0 0, 1 1), (2 2, 3 3))", ""},
+		{"MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)), ((2 2, 3 2, 3 3, 2 2), (2.1 2.1, 2.2 2.1, 2.2 2.2, 2.1 2.1)))", ""},
+		{"MULTIPOLYGON EMPTY", ""},
+		{"GEOMETRYCOLLECTION (POINT (1 2), LINESTRING (0 0, 1 1), POLYGON ((0 0, 1 0, 1 1, 0 0)))", ""},
+		{"GEOMETRYCOLLECTION (POINT (1 2), GEOMETRYCOLLECTION (MULTIPOINT ((3 4)), POINT EMPTY))", ""},
+	}
+
+	for i, d := range testData {
+		exp := d.exp
+		if exp == "" {
+			exp = d.wkt
+		}
+		// WKT -> WKB
+		v, err := Geometry(d.wkt).Value()
+		if err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		var g Geometry
+		if err := g.Scan(v); err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		// WKB -> WKT
+		wkt, err := g.WKT()
+		if err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		if strings.HasPrefix(exp, "SRID=") { // SRID is not encoded
+			exp = exp[strings.Index(exp, ";")+1:]
+		}
+		if wkt != exp {
+			t.Fatalf("test %d: wkt %s - expected %s", i, wkt, exp)
+		}
+		// WKB -> WKB
+		v2, err := g.Value()
+		if err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		if !bytes.Equal(v.([]byte), v2.([]byte)) {
+			t.Fatalf("test %d: wkb %x - expected %x", i, v2, v)
+		}
+	}
+
+	// EWKB SRID
+	g := Geometry(mustDecodeHex(t, "0101000020110f0000000000000000f03f0000000000000040"))
+	if wkt, err := g.WKT(); err != nil || wkt != "SRID=3857;POINT (1 2)" {
+		t.Fatalf("wkt %s error %v - expected %s", wkt, err, "SRID=3857;POINT (1 2)")
+	}
+
+	for i, wkt := range []string{
+		"POINT",
+		"POINT (1)",
+		"POINT (1 2",
+		"POINT (1 2) x",
+		"CIRCLE (1 2)",
+		"LINESTRING (0 0, 1 1 1)",
+		"SRID=x;POINT (1 2)",
+	} {
+		if _, err := Geometry(wkt).Value(); err == nil {
+			t.Fatalf("test %d: error expected for %s", i, wkt)
+		}
+	}
+
+	if v, err := Geometry(nil).Value(); err != nil || v != nil {
+		t.Fatalf("value %v error %v - expected nil", v, err)
+	}
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestSpatial(t *testing.T) {
+	tests := []struct {
+		name string
+		fct  func(t *testing.T)
+	}{
+		{"geoJSON", testGeoJSON},
+		{"geoJSONInvalid", testGeoJSONInvalid},
+		{"envelope", testEnvelope},
+		{"stPoint", testSTPoint},
+		{"wkt", testWKT},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.fct(t)
+		})
+	}
+}
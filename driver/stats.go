@@ -0,0 +1,202 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql/driver"
+	"sync"
+	"time"
+)
+
+// ConnStats contains diagnostic information of a database connection.
+type ConnStats struct {
+	BytesRead    uint64    // Number of bytes read from the database connection.
+	BytesWritten uint64    // Number of bytes written to the database connection.
+	NumRequest   int64     // Number of database requests (round trips).
+	NumStmt      int64     // Number of executed statements and queries.
+	NumError     int64     // Number of failed statement and query executions.
+	NumBatch     int64     // Number of database requests executing bulk (batched) statement parameters.
+	NumRetry     int64     // Number of statement re-executions caused by retriable errors (see SetMaxRetries).
+	NumFetch     int64     // Number of FETCH requests of result set rows.
+	NumLobChunk  int64     // Number of requests reading or writing lob chunks.
+	NumPrepared  int       // Number of prepared statement handles held by the connection.
+	NumCached    int       // Number of prepared statements cached by the connection (see SetStmtCacheSize).
+	Compressed   bool      // Network compression enabled (see SetCompression).
+	LastUsed     time.Time // Time of last statement or query execution.
+	LastError    error     // Last statement or query execution error (nil if no error occurred).
+}
+
+// connStats collects the statement statistics of a connection.
+type connStats struct {
+	mu        sync.Mutex
+	numStmt   int64
+	numError  int64
+	numBatch  int64
+	numRetry  int64
+	numPrep   int
+	lastUsed  time.Time
+	lastError error
+}
+
+// track records a statement execution.
+func (s *connStats) track(err error) {
+	if err == driver.ErrSkip { // statement not executed by this method
+		return
+	}
+	s.mu.Lock()
+	s.numStmt++
+	s.lastUsed = time.Now()
+	if err != nil {
+		s.numError++
+		s.lastError = err
+	}
+	s.mu.Unlock()
+}
+
+// trackBatch records a database request executing bulk statement parameters.
+func (s *connStats) trackBatch() {
+	s.mu.Lock()
+	s.numBatch++
+	s.mu.Unlock()
+}
+
+// trackRetry records a statement re-execution caused by a retriable error.
+func (s *connStats) trackRetry() {
+	s.mu.Lock()
+	s.numRetry++
+	s.mu.Unlock()
+}
+
+// trackPrepared records the creation (delta 1) or release (delta -1) of a prepared statement handle.
+func (s *connStats) trackPrepared(delta int) {
+	s.mu.Lock()
+	s.numPrep += delta
+	s.mu.Unlock()
+}
+
+func (s *connStats) numPrepared() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.numPrep
+}
+
+func (c *conn) stats() ConnStats {
+	c._stats.mu.Lock()
+	defer c._stats.mu.Unlock()
+	return ConnStats{
+		BytesRead:    c.session.BytesRead(),
+		BytesWritten: c.session.BytesWritten(),
+		NumRequest:   int64(c.session.NumRequest()),
+		NumStmt:      c._stats.numStmt,
+		NumError:     c._stats.numError,
+		NumBatch:     c._stats.numBatch,
+		NumRetry:     c._stats.numRetry,
+		NumFetch:     int64(c.session.NumFetch()),
+		NumLobChunk:  int64(c.session.NumLobChunk()),
+		NumPrepared:  c._stats.numPrep,
+		NumCached:    c.session.NumCachedStmt(),
+		Compressed:   c.session.Compressed(),
+		LastUsed:     c._stats.lastUsed,
+		LastError:    c._stats.lastError,
+	}
+}
+
+/*
+Stats contains the diagnostic information of all connections created by a connector.
+The counters are accumulated over the open and the already closed connections, whereas the
+number of prepared and cached statements refer to the open connections only.
+*/
+type Stats struct {
+	OpenConnections int    // Number of open connections.
+	BytesRead       uint64 // Number of bytes read from the database connections.
+	BytesWritten    uint64 // Number of bytes written to the database connections.
+	NumRequest      int64  // Number of database requests (round trips).
+	NumStmt         int64  // Number of executed statements and queries.
+	NumError        int64  // Number of failed statement and query executions.
+	NumBatch        int64  // Number of database requests executing bulk (batched) statement parameters.
+	NumRetry        int64  // Number of statement re-executions caused by retriable errors.
+	NumFetch        int64  // Number of FETCH requests of result set rows.
+	NumLobChunk     int64  // Number of requests reading or writing lob chunks.
+	NumPrepared     int    // Number of prepared statement handles held by the open connections.
+	NumCached       int    // Number of prepared statements cached by the open connections.
+	NumCompressed   int    // Number of open connections with network compression enabled.
+}
+
+// add adds the counters of the connection statistics s.
+func (st *Stats) add(s ConnStats) {
+	st.BytesRead += s.BytesRead
+	st.BytesWritten += s.BytesWritten
+	st.NumRequest += s.NumRequest
+	st.NumStmt += s.NumStmt
+	st.NumError += s.NumError
+	st.NumBatch += s.NumBatch
+	st.NumRetry += s.NumRetry
+	st.NumFetch += s.NumFetch
+	st.NumLobChunk += s.NumLobChunk
+}
+
+// connRegistry keeps track of the open connections of a connector.
+type connRegistry struct {
+	mu     sync.Mutex
+	conns  map[*conn]struct{}
+	closed Stats // accumulated counters of closed connections
+}
+
+func (r *connRegistry) add(c *conn) {
+	r.mu.Lock()
+	if r.conns == nil {
+		r.conns = map[*conn]struct{}{}
+	}
+	r.conns[c] = struct{}{}
+	r.mu.Unlock()
+}
+
+func (r *connRegistry) remove(c *conn) {
+	r.mu.Lock()
+	if _, ok := r.conns[c]; ok {
+		delete(r.conns, c)
+		r.closed.add(c.stats())
+	}
+	r.mu.Unlock()
+}
+
+func (r *connRegistry) stats() []ConnStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := make([]ConnStats, 0, len(r.conns))
+	for c := range r.conns {
+		stats = append(stats, c.stats())
+	}
+	return stats
+}
+
+func (r *connRegistry) totalStats() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	st := r.closed
+	st.OpenConnections = len(r.conns)
+	for c := range r.conns {
+		s := c.stats()
+		st.add(s)
+		st.NumPrepared += s.NumPrepared
+		st.NumCached += s.NumCached
+		if s.Compressed {
+			st.NumCompressed++
+		}
+	}
+	return st
+}
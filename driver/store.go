@@ -0,0 +1,60 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+
+	p "github.com/SAP/go-hdb/internal/protocol"
+)
+
+/*
+StorePreference defines the preferred execution engine (column or row store engine) of a query.
+*/
+type StorePreference string
+
+// StorePreference constants.
+const (
+	// StoreColumn prefers the column store engine (hint CS_JOIN).
+	StoreColumn StorePreference = "column"
+	// StoreRow prefers the row store engine (hint NO_CS_JOIN).
+	StoreRow StorePreference = "row"
+)
+
+var storeHints = map[StorePreference]string{
+	StoreColumn: "CS_JOIN",
+	StoreRow:    "NO_CS_JOIN",
+}
+
+type storeCtxKey struct{}
+
+/*
+WithStorePreference returns a copy of the context with the store preference set.
+The store preference is applied to select statements executed (or prepared) with the returned context
+overriding the optimizer's choice of the execution engine. Unknown store preferences are ignored.
+*/
+func WithStorePreference(ctx context.Context, pref StorePreference) context.Context {
+	return context.WithValue(ctx, storeCtxKey{}, pref)
+}
+
+func storeHint(ctx context.Context, kind p.QueryKind) string {
+	if kind != p.QkSelect {
+		return ""
+	}
+	pref, _ := ctx.Value(storeCtxKey{}).(StorePreference)
+	return storeHints[pref]
+}
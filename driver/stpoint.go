@@ -0,0 +1,102 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+const wkbPointSize = 1 + 4 + 2*8 // byte order, geometry type, x, y
+
+/*
+STPoint is the scan type of spatial point database values (ST_POINT).
+The point is transferred in well-known binary format (WKB). As the spatial reference system
+of a value is defined by the column, SRID is set on scanning if provided by the database
+but is not sent to the database on binding a STPoint value.
+Z and M coordinates are not supported: scanning a point with Z or M coordinates returns the X and Y coordinates only.
+
+As a NULL value cannot be scanned into STPoint, please use NullSTPoint for nullable columns.
+Empty points (POINT EMPTY) are not supported.
+*/
+type STPoint struct {
+	X, Y float64
+	SRID int32
+}
+
+// Scan implements the database/sql/Scanner interface.
+func (pt *STPoint) Scan(src interface{}) error {
+	if src == nil {
+		return fmt.Errorf("spatial: cannot scan NULL value into %T - use NullSTPoint", pt)
+	}
+	b, err := scanSpatialBytes(src)
+	if err != nil {
+		return err
+	}
+	g, err := decodeWKB(b)
+	if err != nil {
+		return err
+	}
+	if g.typ != gtPoint {
+		return fmt.Errorf("spatial: cannot scan %s into %T", g.typ, pt)
+	}
+	if g.coord == nil {
+		return fmt.Errorf("spatial: cannot scan empty point into %T", pt)
+	}
+	pt.X, pt.Y, pt.SRID = g.coord[0], g.coord[1], g.srid
+	return nil
+}
+
+// Value implements the database/sql/Valuer interface.
+func (pt STPoint) Value() (driver.Value, error) {
+	b := make([]byte, wkbPointSize)
+	b[0] = wkbNDR
+	binary.LittleEndian.PutUint32(b[1:], uint32(gtPoint))
+	binary.LittleEndian.PutUint64(b[5:], math.Float64bits(pt.X))
+	binary.LittleEndian.PutUint64(b[13:], math.Float64bits(pt.Y))
+	return b, nil
+}
+
+// NullSTPoint represents a STPoint that may be null.
+// NullSTPoint implements the Scanner interface so it can be used as a scan destination, similar to sql.NullString.
+type NullSTPoint struct {
+	STPoint STPoint
+	Valid   bool // Valid is true if STPoint is not NULL
+}
+
+// Scan implements the database/sql/Scanner interface.
+func (n *NullSTPoint) Scan(src interface{}) error {
+	if src == nil {
+		n.STPoint, n.Valid = STPoint{}, false
+		return nil
+	}
+	if err := n.STPoint.Scan(src); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// Value implements the database/sql/Valuer interface.
+func (n NullSTPoint) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.STPoint.Value()
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const structTag = "hdb"
+
+var valuerReflectType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+/*
+structField describes a struct field bound as statement parameter.
+*/
+type structField struct {
+	name  string
+	index []int
+}
+
+/*
+structFields returns the fields of a struct type to be bound as statement parameters in field order:
+- unexported fields and fields tagged with `hdb:"-"` are skipped
+- the column name of a field is the name of the `hdb` tag or the field name if no tag is set
+- fields of embedded structs are included unless the embedded struct implements the driver.Valuer interface
+*/
+func structFields(t reflect.Type, index []int) []structField {
+	var fields []structField
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get(structTag)
+		if tag == "-" {
+			continue
+		}
+
+		fieldIndex := append(append([]int{}, index...), i)
+
+		if f.Anonymous && tag == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && !f.Type.Implements(valuerReflectType) && !reflect.PtrTo(f.Type).Implements(valuerReflectType) {
+				fields = append(fields, structFields(ft, fieldIndex)...)
+				continue
+			}
+		}
+
+		if f.PkgPath != "" { // unexported
+			continue
+		}
+
+		name := f.Name
+		if tag != "" {
+			name = strings.Split(tag, ",")[0]
+		}
+		fields = append(fields, structField{name: name, index: fieldIndex})
+	}
+	return fields
+}
+
+func structValue(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return rv, fmt.Errorf("invalid nil struct pointer %T", v)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return rv, fmt.Errorf("invalid argument type %T - struct expected", v)
+	}
+	return rv, nil
+}
+
+/*
+structArgs returns the bindable field values of struct v in field order.
+If named is true, the field values are returned as named arguments (sql.Named) using the column names.
+*/
+func structArgs(v interface{}, named bool) ([]interface{}, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, err
+	}
+	fields := structFields(rv.Type(), nil)
+	args := make([]interface{}, 0, len(fields))
+	for _, f := range fields {
+		if named {
+			args = append(args, sql.Named(f.name, fieldValue(rv, f.index)))
+		} else {
+			args = append(args, fieldValue(rv, f.index))
+		}
+	}
+	return args, nil
+}
+
+// fieldValue returns the value of a (nested) field or nil in case of a nil embedded struct pointer.
+func fieldValue(rv reflect.Value, index []int) interface{} {
+	for i, idx := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return nil
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(idx)
+	}
+	return rv.Interface()
+}
+
+/*
+StructColumns returns the column names of the struct fields which are bound as parameters by
+ExecStruct in binding order. The column names can be used to build the statement
+(e.g. insert into <table> (<columns>) values (?, ...)) ensuring that the order of the statement
+parameters is matching the struct field order.
+*/
+func StructColumns(v interface{}) ([]string, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, err
+	}
+	fields := structFields(rv.Type(), nil)
+	columns := make([]string, len(fields))
+	for i, f := range fields {
+		columns[i] = f.name
+	}
+	return columns, nil
+}
+
+/*
+ExecStruct executes a prepared statement binding the fields of struct v as statement parameters.
+
+The fields are bound in field order (see StructColumns), so the order of the statement parameters
+needs to match the order of the struct fields. The number of bound fields needs to match the number of
+statement parameters, otherwise an error is returned. To bind the fields by column name use ExecStructNamed.
+Field values are converted like any other statement
+argument, so types implementing the driver.Valuer interface (e.g. sql.NullString, Decimal, NullDecimal, ...)
+are supported.
+
+Example:
+
+	type Order struct {
+		ID       int64          `hdb:"ID"`
+		Customer string         `hdb:"CUSTOMER"`
+		Amount   Decimal        `hdb:"AMOUNT"`
+		Comment  sql.NullString `hdb:"COMMENT"`
+		Internal string         `hdb:"-"`
+	}
+
+	columns, _ := driver.StructColumns(Order{})
+	// insert into orders (ID, CUSTOMER, AMOUNT, COMMENT) values (?, ?, ?, ?)
+*/
+func ExecStruct(stmt *sql.Stmt, v interface{}) (sql.Result, error) {
+	return ExecStructContext(context.Background(), stmt, v)
+}
+
+// ExecStructContext is like ExecStruct with context.
+func ExecStructContext(ctx context.Context, stmt *sql.Stmt, v interface{}) (sql.Result, error) {
+	args, err := structArgs(v, false)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.ExecContext(ctx, args...)
+}
+
+/*
+ExecStructNamed executes a prepared statement binding the fields of struct v to the named statement
+parameters (:name) by column name (see StructColumns), so that the order of the struct fields does not
+need to match the order of the statement parameters. Each bound field needs to match a named parameter.
+
+Example:
+
+	// insert into orders (ID, CUSTOMER, AMOUNT, COMMENT) values (:ID, :CUSTOMER, :AMOUNT, :COMMENT)
+*/
+func ExecStructNamed(stmt *sql.Stmt, v interface{}) (sql.Result, error) {
+	return ExecStructNamedContext(context.Background(), stmt, v)
+}
+
+// ExecStructNamedContext is like ExecStructNamed with context.
+func ExecStructNamedContext(ctx context.Context, stmt *sql.Stmt, v interface{}) (sql.Result, error) {
+	args, err := structArgs(v, true)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.ExecContext(ctx, args...)
+}
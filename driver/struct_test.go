@@ -0,0 +1,95 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+type testStructBase struct {
+	ID int64 `hdb:"ID"`
+}
+
+type testStruct struct {
+	testStructBase
+	Name    string         `hdb:"NAME"`
+	Comment sql.NullString `hdb:"COMMENT,optional"`
+	Amount  float64
+	Skip    string `hdb:"-"`
+	private int
+}
+
+func testStructColumns(t *testing.T) {
+	columns, err := StructColumns(&testStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"ID", "NAME", "COMMENT", "Amount"}
+	if !reflect.DeepEqual(columns, expected) {
+		t.Fatalf("columns %v - expected %v", columns, expected)
+	}
+
+	if _, err := StructColumns(42); err == nil {
+		t.Fatal("invalid argument type error expected")
+	}
+}
+
+func testStructArgs(t *testing.T) {
+	v := testStruct{
+		testStructBase: testStructBase{ID: 1},
+		Name:           "name",
+		Comment:        sql.NullString{String: "comment", Valid: true},
+		Amount:         4.2,
+		Skip:           "skip",
+		private:        1,
+	}
+	args, err := structArgs(v, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []interface{}{int64(1), "name", sql.NullString{String: "comment", Valid: true}, 4.2}
+	if !reflect.DeepEqual(args, expected) {
+		t.Fatalf("args %v - expected %v", args, expected)
+	}
+
+	args, err = structArgs(v, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = []interface{}{sql.Named("ID", int64(1)), sql.Named("NAME", "name"), sql.Named("COMMENT", sql.NullString{String: "comment", Valid: true}), sql.Named("Amount", 4.2)}
+	if !reflect.DeepEqual(args, expected) {
+		t.Fatalf("args %v - expected %v", args, expected)
+	}
+}
+
+func TestStruct(t *testing.T) {
+	tests := []struct {
+		name string
+		fct  func(t *testing.T)
+	}{
+		{"structColumns", testStructColumns},
+		{"structArgs", testStructArgs},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.fct(t)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+)
+
+type warningHandlerCtxKey struct{}
+
+/*
+WithWarningHandler returns a copy of the context with the warning handler h set.
+The warnings returned by the database for the preparation and execution of statements executed
+with the returned context are passed to h instead of the connector warning handler (see SetWarningHandler),
+e.g. to log the truncation and implicit conversion warnings of a single query together with the query.
+Warnings do not abort the statement execution. Warnings returned by subsequent FETCH requests of a query
+are passed to the connector warning handler.
+
+Example:
+
+	var warnings []driver.Error
+	ctx := driver.WithWarningHandler(ctx, func(warning driver.Error) { warnings = append(warnings, warning) })
+	rows, err := db.QueryContext(ctx, "select ...")
+*/
+func WithWarningHandler(ctx context.Context, h func(warning Error)) context.Context {
+	return context.WithValue(ctx, warningHandlerCtxKey{}, h)
+}
+
+// warningFunc wraps the warning handler h as function of the session configuration.
+func warningFunc(h func(warning Error)) func(warning error) {
+	if h == nil {
+		return nil
+	}
+	return func(warning error) {
+		if w, ok := warning.(Error); ok {
+			h(w)
+		}
+	}
+}
+
+/*
+useWarningHandler sets the warning handler of the context (if any) for the database requests of a
+statement and returns the function restoring the connector warning handler.
+*/
+func (c *conn) useWarningHandler(ctx context.Context) (restore func()) {
+	h, ok := ctx.Value(warningHandlerCtxKey{}).(func(warning Error))
+	if !ok || h == nil {
+		return func() {}
+	}
+	prev := c.session.SetWarningHandler(warningFunc(h))
+	return func() { c.session.SetWarningHandler(prev) }
+}
@@ -0,0 +1,414 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var geometryTypeTags = map[string]geometryType{
+	"POINT":              gtPoint,
+	"LINESTRING":         gtLineString,
+	"POLYGON":            gtPolygon,
+	"MULTIPOINT":         gtMultiPoint,
+	"MULTILINESTRING":    gtMultiLineString,
+	"MULTIPOLYGON":       gtMultiPolygon,
+	"GEOMETRYCOLLECTION": gtGeometryCollection,
+}
+
+// wktDecoder decodes a spatial value in (extended) well-known text format (WKT, EWKT).
+type wktDecoder struct {
+	s      string
+	pos    int
+	err    error
+	inferZ bool // z coordinates without explicit dimension
+}
+
+func (d *wktDecoder) errorf(format string, args ...interface{}) {
+	if d.err == nil {
+		d.err = fmt.Errorf("spatial: invalid well-known text at position %d: %s", d.pos, fmt.Sprintf(format, args...))
+	}
+}
+
+func (d *wktDecoder) skipSpace() {
+	for d.pos < len(d.s) && (d.s[d.pos] == ' ' || d.s[d.pos] == '\t' || d.s[d.pos] == '\n' || d.s[d.pos] == '\r') {
+		d.pos++
+	}
+}
+
+func (d *wktDecoder) peek() byte {
+	d.skipSpace()
+	if d.pos >= len(d.s) {
+		return 0
+	}
+	return d.s[d.pos]
+}
+
+func (d *wktDecoder) expect(c byte) {
+	if d.err != nil {
+		return
+	}
+	if d.peek() != c {
+		d.errorf("%q expected", c)
+		return
+	}
+	d.pos++
+}
+
+func isWKTLetter(c byte) bool { return (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') }
+
+// word returns the next keyword in upper case ("" if the next token is not a keyword).
+func (d *wktDecoder) word() string {
+	d.skipSpace()
+	start := d.pos
+	for d.pos < len(d.s) && isWKTLetter(d.s[d.pos]) {
+		d.pos++
+	}
+	return strings.ToUpper(d.s[start:d.pos])
+}
+
+func (d *wktDecoder) number() float64 {
+	if d.err != nil {
+		return 0
+	}
+	d.skipSpace()
+	start := d.pos
+	for d.pos < len(d.s) && strings.IndexByte("+-.0123456789eE", d.s[d.pos]) != -1 {
+		d.pos++
+	}
+	f, err := strconv.ParseFloat(d.s[start:d.pos], 64)
+	if err != nil {
+		d.pos = start
+		d.errorf("number expected")
+	}
+	return f
+}
+
+// srid decodes the optional EWKT SRID prefix (SRID=<srid>;).
+func (d *wktDecoder) srid() int32 {
+	d.skipSpace()
+	if !strings.HasPrefix(strings.ToUpper(d.s[d.pos:]), "SRID=") {
+		return 0
+	}
+	d.pos += len("SRID=")
+	start := d.pos
+	for d.pos < len(d.s) && d.s[d.pos] != ';' {
+		d.pos++
+	}
+	srid, err := strconv.ParseInt(strings.TrimSpace(d.s[start:d.pos]), 10, 32)
+	if err != nil {
+		d.pos = start
+		d.errorf("invalid SRID")
+		return 0
+	}
+	d.expect(';')
+	return int32(srid)
+}
+
+// geometry decodes a tagged geometry. For nested geometries the dimensions of parent are inherited.
+func (d *wktDecoder) geometry(parent *geometry) *geometry {
+	tag := d.word()
+	typ, ok := geometryTypeTags[tag]
+	if !ok {
+		d.errorf("geometry type expected - got %q", tag)
+		return nil
+	}
+	g := &geometry{typ: typ}
+	if parent != nil {
+		g.z, g.m = parent.z, parent.m
+	}
+
+	if d.peek() != '(' {
+		switch d.word() {
+		case "":
+		case "Z":
+			g.z = true
+		case "M":
+			g.m = true
+		case "ZM":
+			g.z, g.m = true, true
+		case "EMPTY":
+			return g
+		default:
+			d.errorf("dimension or EMPTY expected")
+			return nil
+		}
+		if d.peek() != '(' {
+			if d.word() != "EMPTY" {
+				d.errorf("EMPTY expected")
+			}
+			return g
+		}
+	}
+	d.body(g)
+	return g
+}
+
+func (d *wktDecoder) body(g *geometry) {
+	switch g.typ {
+	case gtPoint:
+		d.expect('(')
+		d.point(g)
+		d.expect(')')
+	case gtLineString:
+		d.points(g)
+	case gtPolygon, gtMultiLineString:
+		d.list(func() {
+			ls := &geometry{typ: gtLineString, z: g.z, m: g.m}
+			d.points(ls)
+			g.elems = append(g.elems, ls)
+		})
+	case gtMultiPoint:
+		d.list(func() {
+			pt := &geometry{typ: gtPoint, z: g.z, m: g.m}
+			switch c := d.peek(); {
+			case c == '(': // MULTIPOINT((1 2), (3 4)) as well as MULTIPOINT(1 2, 3 4)
+				d.pos++
+				d.point(pt)
+				d.expect(')')
+			case isWKTLetter(c):
+				if d.word() != "EMPTY" {
+					d.errorf("EMPTY expected")
+				}
+			default:
+				d.point(pt)
+			}
+			g.elems = append(g.elems, pt)
+		})
+	case gtMultiPolygon:
+		d.list(func() {
+			poly := &geometry{typ: gtPolygon, z: g.z, m: g.m}
+			d.body(poly)
+			g.elems = append(g.elems, poly)
+		})
+	case gtGeometryCollection:
+		d.list(func() {
+			g.elems = append(g.elems, d.geometry(g))
+		})
+	}
+}
+
+// list decodes a parenthesized, comma separated list of elements.
+func (d *wktDecoder) list(elem func()) {
+	d.expect('(')
+	for d.err == nil {
+		elem()
+		if d.peek() != ',' {
+			break
+		}
+		d.pos++
+	}
+	d.expect(')')
+}
+
+func (d *wktDecoder) points(g *geometry) {
+	d.list(func() {
+		pt := &geometry{typ: gtPoint, z: g.z, m: g.m}
+		d.point(pt)
+		g.elems = append(g.elems, pt)
+	})
+}
+
+// point decodes the coordinates of a point. Without explicit dimension a third coordinate is interpreted as z.
+func (d *wktDecoder) point(g *geometry) {
+	var coord []float64
+	for d.err == nil {
+		c := d.peek()
+		if c == ',' || c == ')' || c == 0 {
+			break
+		}
+		coord = append(coord, d.number())
+	}
+	if d.err != nil {
+		return
+	}
+	switch {
+	case len(coord) == g.dim():
+	case len(coord) == 3 && !g.z && !g.m:
+		d.inferZ = true
+	default:
+		d.errorf("%d coordinates expected - got %d", g.dim(), len(coord))
+		return
+	}
+	g.coord = coord
+}
+
+// setZ sets the z dimension of g and its elements and checks the number of point coordinates.
+func setZ(g *geometry) error {
+	g.z = true
+	if g.coord != nil && len(g.coord) != g.dim() {
+		return fmt.Errorf("spatial: invalid well-known text: mixed coordinate dimensions")
+	}
+	for _, e := range g.elems {
+		if err := setZ(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeWKT decodes a spatial value in (extended) well-known text format.
+func decodeWKT(s string) (*geometry, error) {
+	d := &wktDecoder{s: s}
+	srid := d.srid()
+	g := d.geometry(nil)
+	if d.err == nil && d.peek() != 0 {
+		d.errorf("unexpected trailing characters")
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	if d.inferZ {
+		if err := setZ(g); err != nil {
+			return nil, err
+		}
+	}
+	g.srid = srid
+	return g, nil
+}
+
+// isWKT returns true if b is a value in (extended) well-known text format.
+func isWKT(b []byte) bool {
+	b = bytes.TrimLeft(b, " \t\n\r")
+	return len(b) != 0 && isWKTLetter(b[0])
+}
+
+// encodeWKB encodes a geometry in (little endian, ISO) well-known binary format. The SRID is not encoded.
+func encodeWKB(buf *bytes.Buffer, g *geometry) {
+	code := uint32(g.typ)
+	switch {
+	case g.z && g.m:
+		code += 3000
+	case g.z:
+		code += 1000
+	case g.m:
+		code += 2000
+	}
+	buf.WriteByte(wkbNDR)
+	writeWKBUint32(buf, code)
+
+	switch g.typ {
+	case gtPoint:
+		writeWKBCoord(buf, g)
+	case gtLineString:
+		writeWKBUint32(buf, uint32(len(g.elems)))
+		for _, pt := range g.elems {
+			writeWKBCoord(buf, pt)
+		}
+	case gtPolygon:
+		writeWKBUint32(buf, uint32(len(g.elems)))
+		for _, ring := range g.elems {
+			writeWKBUint32(buf, uint32(len(ring.elems)))
+			for _, pt := range ring.elems {
+				writeWKBCoord(buf, pt)
+			}
+		}
+	default:
+		writeWKBUint32(buf, uint32(len(g.elems)))
+		for _, e := range g.elems {
+			encodeWKB(buf, e)
+		}
+	}
+}
+
+func writeWKBUint32(buf *bytes.Buffer, v uint32) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	buf.Write(b[:])
+}
+
+func writeWKBCoord(buf *bytes.Buffer, g *geometry) {
+	var b [8]byte
+	for i := 0; i < g.dim(); i++ {
+		f := math.NaN() // empty point
+		if g.coord != nil {
+			f = g.coord[i]
+		}
+		binary.LittleEndian.PutUint64(b[:], math.Float64bits(f))
+		buf.Write(b[:])
+	}
+}
+
+// writeWKT writes a geometry in (extended) well-known text format.
+func writeWKT(buf *bytes.Buffer, g *geometry, top bool) {
+	if top && g.srid != 0 {
+		fmt.Fprintf(buf, "SRID=%d;", g.srid)
+	}
+	buf.WriteString(strings.ToUpper(g.typ.String()))
+	switch {
+	case g.z && g.m:
+		buf.WriteString(" ZM")
+	case g.z:
+		buf.WriteString(" Z")
+	case g.m:
+		buf.WriteString(" M")
+	}
+	if (g.typ == gtPoint && g.coord == nil) || (g.typ != gtPoint && len(g.elems) == 0) {
+		buf.WriteString(" EMPTY")
+		return
+	}
+	buf.WriteByte(' ')
+	writeWKTBody(buf, g)
+}
+
+func writeWKTBody(buf *bytes.Buffer, g *geometry) {
+	buf.WriteByte('(')
+	switch g.typ {
+	case gtPoint:
+		writeWKTCoord(buf, g)
+	case gtLineString:
+		for i, pt := range g.elems {
+			if i != 0 {
+				buf.WriteString(", ")
+			}
+			writeWKTCoord(buf, pt)
+		}
+	case gtGeometryCollection:
+		for i, e := range g.elems {
+			if i != 0 {
+				buf.WriteString(", ")
+			}
+			writeWKT(buf, e, false)
+		}
+	default:
+		for i, e := range g.elems {
+			if i != 0 {
+				buf.WriteString(", ")
+			}
+			if e.typ == gtPoint && e.coord == nil {
+				buf.WriteString("EMPTY")
+			} else {
+				writeWKTBody(buf, e)
+			}
+		}
+	}
+	buf.WriteByte(')')
+}
+
+func writeWKTCoord(buf *bytes.Buffer, g *geometry) {
+	for i, f := range g.coord {
+		if i != 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(strconv.FormatFloat(f, 'f', -1, 64))
+	}
+}
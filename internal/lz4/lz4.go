@@ -0,0 +1,172 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lz4 implements the LZ4 block format used by the hdb protocol network compression.
+package lz4
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	minMatch     = 4  // minimal match length
+	lastLiterals = 5  // the last bytes of a block are always literals
+	mfLimit      = 12 // the last match starts at least mfLimit bytes before the end of a block
+	maxOffset    = 1<<16 - 1
+	hashLog      = 14
+)
+
+// ErrCorrupt is returned by Decode in case of invalid compressed data.
+var ErrCorrupt = errors.New("lz4: corrupt block")
+
+// CompressBound returns the maximal size of the compressed data of n bytes.
+func CompressBound(n int) int { return n + n/255 + 16 }
+
+// DecompressBound returns the maximal size of the data decompressed from n compressed bytes
+// (a length byte of a sequence extends the literal or match length by at most 255 bytes).
+func DecompressBound(n int) int { return n * 255 }
+
+func hash(v uint32) uint32 { return (v * 2654435761) >> (32 - hashLog) }
+
+// appendLiterals appends the token of a sequence with match length matchLen - minMatch and the literals.
+func appendLiterals(dst, literals []byte, matchLen int) []byte {
+	numLiteral := len(literals)
+	token := byte(matchLen)
+	if matchLen >= 15 {
+		token = 15
+	}
+	if numLiteral >= 15 {
+		token |= 15 << 4
+	} else {
+		token |= byte(numLiteral) << 4
+	}
+	dst = append(dst, token)
+	if numLiteral >= 15 {
+		dst = appendLength(dst, numLiteral-15)
+	}
+	return append(dst, literals...)
+}
+
+func appendLength(dst []byte, n int) []byte {
+	for ; n >= 255; n -= 255 {
+		dst = append(dst, 255)
+	}
+	return append(dst, byte(n))
+}
+
+// Encode appends the compressed block of src to dst and returns the extended buffer.
+func Encode(dst, src []byte) []byte {
+	n := len(src)
+	anchor := 0
+
+	if n > mfLimit {
+		table := make([]int32, 1<<hashLog) // position + 1 of the last occurrence of a hash value
+		for i, limit := 0, n-mfLimit; i < limit; {
+			v := binary.LittleEndian.Uint32(src[i:])
+			h := hash(v)
+			ref := int(table[h]) - 1
+			table[h] = int32(i + 1)
+			if ref < 0 || i-ref > maxOffset || binary.LittleEndian.Uint32(src[ref:]) != v {
+				i++
+				continue
+			}
+			matchLen := minMatch
+			for i+matchLen < n-lastLiterals && src[ref+matchLen] == src[i+matchLen] {
+				matchLen++
+			}
+			dst = appendLiterals(dst, src[anchor:i], matchLen-minMatch)
+			dst = append(dst, byte(i-ref), byte((i-ref)>>8))
+			if matchLen-minMatch >= 15 {
+				dst = appendLength(dst, matchLen-minMatch-15)
+			}
+			i += matchLen
+			anchor = i
+		}
+	}
+	return appendLiterals(dst, src[anchor:], 0) // last sequence: literals only
+}
+
+// Decode decompresses the block src into dst and returns the number of bytes written to dst.
+// dst needs to be large enough to hold the decompressed data.
+func Decode(dst, src []byte) (int, error) {
+	si, di := 0, 0
+	for {
+		if si >= len(src) {
+			return 0, ErrCorrupt
+		}
+		token := src[si]
+		si++
+
+		numLiteral := int(token >> 4)
+		if numLiteral == 15 {
+			l, n := decodeLength(src[si:])
+			if n == 0 {
+				return 0, ErrCorrupt
+			}
+			numLiteral += l
+			si += n
+		}
+		if numLiteral > len(src)-si || numLiteral > len(dst)-di {
+			return 0, ErrCorrupt
+		}
+		di += copy(dst[di:], src[si:si+numLiteral])
+		si += numLiteral
+
+		if si == len(src) { // last sequence
+			return di, nil
+		}
+
+		if len(src)-si < 2 {
+			return 0, ErrCorrupt
+		}
+		offset := int(src[si]) | int(src[si+1])<<8
+		si += 2
+		if offset == 0 || offset > di {
+			return 0, ErrCorrupt
+		}
+
+		matchLen := int(token & 0x0f)
+		if matchLen == 15 {
+			l, n := decodeLength(src[si:])
+			if n == 0 {
+				return 0, ErrCorrupt
+			}
+			matchLen += l
+			si += n
+		}
+		matchLen += minMatch
+		if matchLen > len(dst)-di {
+			return 0, ErrCorrupt
+		}
+		for i := 0; i < matchLen; i++ { // byte wise copy: match might overlap
+			dst[di+i] = dst[di-offset+i]
+		}
+		di += matchLen
+	}
+}
+
+// decodeLength decodes an extended length and returns the length and the number of bytes read (0: invalid).
+func decodeLength(b []byte) (int, int) {
+	l := 0
+	for i, v := range b {
+		l += int(v)
+		if v != 255 {
+			return l, i + 1
+		}
+	}
+	return 0, 0
+}
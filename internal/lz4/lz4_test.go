@@ -0,0 +1,84 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lz4
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestLZ4(t *testing.T) {
+	random := make([]byte, 100000)
+	rand.Read(random)
+
+	testData := [][]byte{
+		{},
+		[]byte("a"),
+		[]byte("abcdefghijklm"),
+		bytes.Repeat([]byte("a"), 1000),
+		[]byte(strings.Repeat("select * from dummy; ", 500)),
+		random,
+		append(bytes.Repeat([]byte{0}, 70000), random[:70000]...), // offsets exceeding maxOffset
+	}
+
+	for i, src := range testData {
+		c := Encode(nil, src)
+		if len(c) > CompressBound(len(src)) {
+			t.Fatalf("%d: compressed size %d exceeds bound %d", i, len(c), CompressBound(len(src)))
+		}
+		dst := make([]byte, len(src))
+		n, err := Decode(dst, c)
+		if err != nil {
+			t.Fatalf("%d: %s", i, err)
+		}
+		if !bytes.Equal(dst[:n], src) {
+			t.Fatalf("%d: decoded data differs from source data", i)
+		}
+	}
+
+	// compression of repetitive data
+	src := []byte(strings.Repeat("select * from dummy; ", 500))
+	if c := Encode(nil, src); len(c) >= len(src)/10 {
+		t.Fatalf("compressed size %d - expected < %d", len(c), len(src)/10)
+	}
+}
+
+func TestLZ4Decode(t *testing.T) {
+	// block of reference implementation: "abcabcabcabcabcabc" + "xyzxy" (literals abc, match offset 3 length 15, literals)
+	block := []byte{0x3b, 'a', 'b', 'c', 0x03, 0x00, 0x50, 'x', 'y', 'z', 'x', 'y'}
+	dst := make([]byte, 23)
+	n, err := Decode(dst, block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(dst[:n]); s != "abcabcabcabcabcabcxyzxy" {
+		t.Fatalf("decoded %s - expected %s", s, "abcabcabcabcabcabcxyzxy")
+	}
+
+	// corrupt blocks
+	for i, block := range [][]byte{
+		{},
+		{0x3b, 'a', 'b', 'c', 0x04, 0x00}, // offset exceeds output
+		{0xf0, 0xff},                      // missing length
+	} {
+		if _, err := Decode(make([]byte, 100), block); err != ErrCorrupt {
+			t.Fatalf("%d: error %v - expected %v", i, err, ErrCorrupt)
+		}
+	}
+}
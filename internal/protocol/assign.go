@@ -0,0 +1,105 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+)
+
+/*
+assignValue assigns a database value to an output parameter destination (sql.Out Dest).
+- destinations implementing the sql.Scanner interface scan the value
+- otherwise dest needs to be a non nil pointer to a variable the value can be assigned or converted to
+*/
+func assignValue(dest interface{}, v driver.Value) error {
+	if scanner, ok := dest.(sql.Scanner); ok {
+		return scanner.Scan(v)
+	}
+
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("invalid output parameter destination %T - non nil pointer expected", dest)
+	}
+	return assignReflectValue(rv.Elem(), v)
+}
+
+func assignReflectValue(ev reflect.Value, v driver.Value) error {
+	if v == nil {
+		switch ev.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+			ev.Set(reflect.Zero(ev.Type()))
+			return nil
+		}
+		return fmt.Errorf("cannot assign NULL value to %s", ev.Type())
+	}
+
+	sv := reflect.ValueOf(v)
+
+	switch {
+	case ev.Kind() == reflect.Ptr:
+		pv := reflect.New(ev.Type().Elem())
+		if err := assignReflectValue(pv.Elem(), v); err != nil {
+			return err
+		}
+		ev.Set(pv)
+		return nil
+	case sv.Type().AssignableTo(ev.Type()):
+		if b, ok := v.([]byte); ok { // copy bytes
+			v = append([]byte(nil), b...)
+			sv = reflect.ValueOf(v)
+		}
+		ev.Set(sv)
+		return nil
+	}
+
+	switch ev.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i, ok := v.(int64); ok && !ev.OverflowInt(i) {
+			ev.SetInt(i)
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if i, ok := v.(int64); ok && i >= 0 && !ev.OverflowUint(uint64(i)) {
+			ev.SetUint(uint64(i))
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		switch f := v.(type) {
+		case float64:
+			if !ev.OverflowFloat(f) {
+				ev.SetFloat(f)
+				return nil
+			}
+		case int64:
+			ev.SetFloat(float64(f))
+			return nil
+		}
+	case reflect.String:
+		switch s := v.(type) {
+		case []byte:
+			ev.SetString(string(s))
+			return nil
+		case string:
+			ev.SetString(s)
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot assign value %v of type %T to %s", v, v, ev.Type())
+}
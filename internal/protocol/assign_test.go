@@ -0,0 +1,74 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestAssignValue(t *testing.T) {
+	var (
+		i   int
+		i8  int8
+		u   uint16
+		f32 float32
+		s   string
+		b   []byte
+		tm  time.Time
+		pi  *int
+		ns  sql.NullString
+	)
+	now := time.Now()
+
+	testData := []struct {
+		dest interface{}
+		v    interface{}
+		ok   bool
+	}{
+		{&i, int64(42), true},
+		{&i8, int64(42), true},
+		{&i8, int64(4711), false}, // overflow
+		{&u, int64(42), true},
+		{&u, int64(-1), false},
+		{&f32, float64(4.5), true},
+		{&s, []byte("Hello"), true},
+		{&b, []byte("Hello"), true},
+		{&tm, now, true},
+		{&pi, int64(42), true},
+		{&pi, nil, true},
+		{&i, nil, false},
+		{&ns, "Hello", true},
+		{&s, int64(42), false},
+		{i, int64(42), false}, // no pointer
+	}
+
+	for j, d := range testData {
+		err := assignValue(d.dest, d.v)
+		if d.ok && err != nil {
+			t.Fatalf("test %d: %s", j, err)
+		}
+		if !d.ok && err == nil {
+			t.Fatalf("test %d: error expected", j)
+		}
+	}
+
+	if i != 42 || i8 != 42 || u != 42 || f32 != 4.5 || s != "Hello" || string(b) != "Hello" || !tm.Equal(now) || pi != nil || !ns.Valid || ns.String != "Hello" {
+		t.Fatalf("invalid assigned values")
+	}
+}
@@ -37,6 +37,47 @@ const (
 	mnSCRAMPBKDF2SHA256 = "SCRAMPBKDF2SHA256" // pbkdf2
 )
 
+// Authentication method names.
+const (
+	AuthMethodSCRAMSHA256       = mnSCRAMSHA256
+	AuthMethodSCRAMPBKDF2SHA256 = mnSCRAMPBKDF2SHA256
+	AuthMethodJWT               = mnJWT
+	AuthMethodSAML              = mnSAML
+	AuthMethodX509              = mnX509
+)
+
+// IsAuthMethod returns true if method is the name of a supported authentication method.
+func IsAuthMethod(method string) bool {
+	switch method {
+	case mnSCRAMSHA256, mnSCRAMPBKDF2SHA256, mnJWT, mnSAML, mnX509:
+		return true
+	}
+	return false
+}
+
+// scramMethods are the password based authentication methods in order of preference.
+var scramMethods = []string{mnSCRAMPBKDF2SHA256, mnSCRAMSHA256}
+
+/*
+allowedAuthMethods returns the methods included in allowed in the order of allowed.
+If allowed is empty all methods are returned in the given order.
+*/
+func allowedAuthMethods(methods, allowed []string) []string {
+	if len(allowed) == 0 {
+		return methods
+	}
+	var r []string
+	for _, a := range allowed {
+		for _, m := range methods {
+			if m == a {
+				r = append(r, m)
+				break
+			}
+		}
+	}
+	return r
+}
+
 const (
 	clientChallengeSize = 64
 	serverChallengeSize = 48
@@ -91,6 +132,60 @@ func (_authShortBytes) encode(enc *encoding.Encoder, b []byte) error {
 	return nil
 }
 
+/*
+auth field sizes:
+- up to authMaxShortSize bytes the size is encoded in one byte
+- larger fields (e.g. tokens) are preceded by authLongSizeIndicator and the size as big endian uint16
+*/
+const (
+	authMaxShortSize      = 250
+	authLongSizeIndicator = 0xff
+)
+
+func authFieldSize(size int) int {
+	if size <= authMaxShortSize {
+		return size + 1
+	}
+	return size + 3
+}
+
+func encodeAuthFieldSize(enc *encoding.Encoder, size int) error {
+	switch {
+	case size <= authMaxShortSize:
+		enc.Byte(byte(size))
+	case size <= math.MaxUint16:
+		enc.Byte(authLongSizeIndicator)
+		enc.Byte(byte(size >> 8)) // big endian
+		enc.Byte(byte(size))
+	default:
+		return fmt.Errorf("invalid auth parameter length %d", size)
+	}
+	return nil
+}
+
+func encodeAuthField(enc *encoding.Encoder, b []byte) error {
+	if err := encodeAuthFieldSize(enc, len(b)); err != nil {
+		return err
+	}
+	enc.Bytes(b)
+	return nil
+}
+
+func decodeAuthFieldSize(dec *encoding.Decoder) int {
+	size := int(dec.Byte())
+	if size == authLongSizeIndicator {
+		size = int(dec.Byte())<<8 | int(dec.Byte()) // big endian
+	}
+	return size
+}
+
+func checkAuthMethod(method, expected string) error {
+	if method != expected {
+		return fmt.Errorf("invalid authentication method %s - expected %s", method, expected)
+	}
+	return nil
+}
+
 type authMethod struct {
 	method          string
 	clientChallenge []byte
@@ -368,16 +463,16 @@ type auth struct {
 	initRep            *authInitRep
 }
 
-func newAuth(username, password string) *auth {
-	return &auth{
+func newAuth(username, password string, methods []string) *auth {
+	a := &auth{
 		username: username,
 		password: password,
-		methods: []*authMethod{
-			{method: mnSCRAMPBKDF2SHA256, clientChallenge: clientChallenge()},
-			{method: mnSCRAMSHA256, clientChallenge: clientChallenge()},
-		},
-		initRep: &authInitRep{},
+		initRep:  &authInitRep{},
+	}
+	for _, m := range methods {
+		a.methods = append(a.methods, &authMethod{method: m, clientChallenge: clientChallenge()})
 	}
+	return a
 }
 
 func (a *auth) clientChallenge(method string) []byte {
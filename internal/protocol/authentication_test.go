@@ -17,7 +17,14 @@ limitations under the License.
 package protocol
 
 import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"strings"
 	"testing"
+
+	"github.com/SAP/go-hdb/internal/protocol/encoding"
 )
 
 func TestAuthentication(t *testing.T) {
@@ -70,3 +77,161 @@ func TestAuthentication(t *testing.T) {
 
 	}
 }
+
+func TestJWTAuthentication(t *testing.T) {
+	for _, token := range []string{"header.payload.signature", strings.Repeat("x", 1024)} { // short and long field size
+		buf := new(bytes.Buffer)
+		initReq := &authTokenInitReq{method: mnJWT, token: []byte(token)}
+		if err := initReq.encode(encoding.NewEncoder(buf)); err != nil {
+			t.Fatal(err)
+		}
+		if buf.Len() != initReq.size() {
+			t.Fatalf("token length %d: encoded size %d - expected %d", len(token), buf.Len(), initReq.size())
+		}
+		decReq := &authTokenInitReq{method: mnJWT}
+		if err := decReq.decode(encoding.NewDecoder(buf), nil); err != nil {
+			t.Fatal(err)
+		}
+		if string(decReq.token) != token {
+			t.Fatalf("token %s - expected %s", decReq.token, token)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	finalReq := &authTokenFinalReq{method: mnJWT, username: "JWTUSER"}
+	if err := finalReq.encode(encoding.NewEncoder(buf)); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != finalReq.size() {
+		t.Fatalf("encoded size %d - expected %d", buf.Len(), finalReq.size())
+	}
+	decReq := &authTokenFinalReq{method: mnJWT}
+	if err := decReq.decode(encoding.NewDecoder(buf), nil); err != nil {
+		t.Fatal(err)
+	}
+	if decReq.username != finalReq.username {
+		t.Fatalf("username %s - expected %s", decReq.username, finalReq.username)
+	}
+
+	if _, err := newJWTAuth("").next(); err == nil {
+		t.Fatal("empty token error expected")
+	}
+}
+
+func TestSAMLAuthentication(t *testing.T) {
+	assertion := []byte("<saml2:Assertion>" + strings.Repeat("x", 1024) + "</saml2:Assertion>")
+	a := newSAMLAuth(assertion)
+
+	initReq, err := a.next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	if err := initReq.encode(encoding.NewEncoder(buf)); err != nil {
+		t.Fatal(err)
+	}
+	decReq := &authTokenInitReq{method: mnJWT}
+	if err := decReq.decode(encoding.NewDecoder(buf), nil); err == nil {
+		t.Fatal("invalid authentication method error expected")
+	}
+
+	initRep, _ := a.next()
+	initRep.(*authTokenInitRep).username = "SAMLUSER"
+	finalReq, _ := a.next()
+	if username := finalReq.(*authTokenFinalReq).username; username != "SAMLUSER" {
+		t.Fatalf("username %s - expected %s", username, "SAMLUSER")
+	}
+	finalRep, _ := a.next()
+	finalRep.(*authTokenFinalRep).sessionCookie = []byte("cookie")
+
+	if a.sessionUsername() != "SAMLUSER" || string(a.sessionCookie()) != "cookie" {
+		t.Fatalf("session cookie %s %s - expected %s %s", a.sessionUsername(), a.sessionCookie(), "SAMLUSER", "cookie")
+	}
+
+	// session cookie authentication: username of the cookie
+	a = newSessionCookieAuth("SAMLUSER", []byte("cookie"))
+	initReq, _ = a.next()
+	if username := initReq.(*authTokenInitReq).username; username != "SAMLUSER" {
+		t.Fatalf("username %s - expected %s", username, "SAMLUSER")
+	}
+	a.next() // init reply without username
+	finalReq, _ = a.next()
+	if username := finalReq.(*authTokenFinalReq).username; username != "SAMLUSER" {
+		t.Fatalf("username %s - expected %s", username, "SAMLUSER")
+	}
+	a.next()
+	if a.sessionCookie() != nil {
+		t.Fatalf("session cookie %v - expected nil", a.sessionCookie())
+	}
+}
+
+func TestAllowedAuthMethods(t *testing.T) {
+	testData := []struct {
+		allowed, methods []string
+	}{
+		{nil, scramMethods},
+		{[]string{mnSCRAMSHA256}, []string{mnSCRAMSHA256}},
+		{[]string{mnSCRAMSHA256, mnSCRAMPBKDF2SHA256}, []string{mnSCRAMSHA256, mnSCRAMPBKDF2SHA256}}, // order of preference
+		{[]string{mnJWT, mnSCRAMPBKDF2SHA256}, []string{mnSCRAMPBKDF2SHA256}},
+		{[]string{mnJWT}, nil},
+	}
+
+	for i, d := range testData {
+		methods := allowedAuthMethods(scramMethods, d.allowed)
+		if strings.Join(methods, ",") != strings.Join(d.methods, ",") {
+			t.Fatalf("test %d: methods %v - expected %v", i, methods, d.methods)
+		}
+	}
+}
+
+func TestX509Authentication(t *testing.T) {
+	x509Cert, key, err := CreateTestCert("X509USER", false, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der := x509Cert.Raw
+	// large chain: long auth field size encoding
+	cert := &tls.Certificate{Certificate: [][]byte{der, der, der}, PrivateKey: key}
+
+	a := newX509Auth(cert)
+	initReq, err := a.next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	if err := initReq.encode(encoding.NewEncoder(buf)); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != initReq.size() {
+		t.Fatalf("encoded size %d - expected %d", buf.Len(), initReq.size())
+	}
+
+	initRep, err := a.next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverNonce := make([]byte, x509ServerNonceSize)
+	rand.Read(serverNonce)
+	initRep.(*authX509InitRep).serverNonce = serverNonce
+
+	finalReq, err := a.next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err := finalReq.encode(encoding.NewEncoder(buf)); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != finalReq.size() {
+		t.Fatalf("encoded size %d - expected %d", buf.Len(), finalReq.size())
+	}
+
+	prms := finalReq.(*authX509FinalReq).prms
+	if len(prms.chain) != 2 {
+		t.Fatalf("number of chain certificates %d - expected %d", len(prms.chain), 2)
+	}
+	message := bytes.Join([][]byte{der, der, der, serverNonce}, nil)
+	if err := x509Cert.CheckSignature(x509.ECDSAWithSHA256, message, prms.signature); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+// Token based authentication: JSON Web Token (JWT), SAML assertion (SAML) and session cookie (SessionCookie)
+
+import (
+	"fmt"
+
+	"github.com/SAP/go-hdb/internal/protocol/encoding"
+	"github.com/SAP/go-hdb/internal/unicode/cesu8"
+)
+
+const (
+	mnJWT           = "JWT"
+	mnSAML          = "SAML"
+	mnSessionCookie = "SessionCookie"
+)
+
+type authTokenInitReq struct {
+	method   string
+	username string // empty for JWT and SAML (the database user is identified by the token)
+	token    []byte
+}
+
+func (r *authTokenInitReq) String() string {
+	return fmt.Sprintf("username %s method %s token length %d", r.username, r.method, len(r.token))
+}
+
+func (r *authTokenInitReq) size() int {
+	size := int16Size // no of parameters
+	size += authFieldSize(cesu8.StringSize(r.username))
+	size += authFieldSize(len(r.method))
+	size += authFieldSize(len(r.token))
+	return size
+}
+
+func (r *authTokenInitReq) decode(dec *encoding.Decoder, ph *partHeader) error {
+	numPrm := int(dec.Int16())
+	if numPrm != 3 {
+		return fmt.Errorf("invalid number of parameters %d - expected %d", numPrm, 3)
+	}
+	r.username = authShortCESU8String.decode(dec)
+	if err := checkAuthMethod(string(authShortBytes.decode(dec)), r.method); err != nil {
+		return err
+	}
+	r.token = make([]byte, decodeAuthFieldSize(dec))
+	dec.Bytes(r.token)
+	return nil
+}
+
+func (r *authTokenInitReq) encode(enc *encoding.Encoder) error {
+	enc.Int16(3)
+	if err := authShortCESU8String.encode(enc, r.username); err != nil {
+		return err
+	}
+	if err := encodeAuthField(enc, []byte(r.method)); err != nil {
+		return err
+	}
+	return encodeAuthField(enc, r.token)
+}
+
+type authTokenInitRep struct {
+	method   string
+	username string // database user identified by the token
+}
+
+func (r *authTokenInitRep) String() string                     { return fmt.Sprintf("username %s", r.username) }
+func (r *authTokenInitRep) size() int                          { panic("not implemented") }
+func (r *authTokenInitRep) encode(enc *encoding.Encoder) error { panic("not implemented") }
+
+func (r *authTokenInitRep) decode(dec *encoding.Decoder, ph *partHeader) error {
+	numPrm := int(dec.Int16())
+	if numPrm != 2 {
+		return fmt.Errorf("invalid number of parameters %d - expected %d", numPrm, 2)
+	}
+	if err := checkAuthMethod(string(authShortBytes.decode(dec)), r.method); err != nil {
+		return err
+	}
+	r.username = string(dec.CESU8Bytes(decodeAuthFieldSize(dec)))
+	return nil
+}
+
+type authTokenFinalReq struct {
+	method   string
+	username string
+}
+
+func (r *authTokenFinalReq) String() string {
+	return fmt.Sprintf("username %s method %s", r.username, r.method)
+}
+
+func (r *authTokenFinalReq) size() int {
+	size := int16Size // no of parameters
+	size += authFieldSize(cesu8.StringSize(r.username))
+	size += authFieldSize(len(r.method))
+	size++ // empty parameter
+	return size
+}
+
+func (r *authTokenFinalReq) decode(dec *encoding.Decoder, ph *partHeader) error {
+	numPrm := int(dec.Int16())
+	if numPrm != 3 {
+		return fmt.Errorf("invalid number of parameters %d - expected %d", numPrm, 3)
+	}
+	r.username = string(dec.CESU8Bytes(decodeAuthFieldSize(dec)))
+	if err := checkAuthMethod(string(authShortBytes.decode(dec)), r.method); err != nil {
+		return err
+	}
+	dec.Byte() // empty parameter
+	return nil
+}
+
+func (r *authTokenFinalReq) encode(enc *encoding.Encoder) error {
+	enc.Int16(3)
+	if err := authShortCESU8String.encode(enc, r.username); err != nil {
+		return err
+	}
+	if err := encodeAuthField(enc, []byte(r.method)); err != nil {
+		return err
+	}
+	enc.Byte(0) // empty parameter
+	return nil
+}
+
+type authTokenFinalRep struct {
+	method        string
+	sessionCookie []byte
+}
+
+func (r *authTokenFinalRep) String() string {
+	return fmt.Sprintf("method %s session cookie length %d", r.method, len(r.sessionCookie))
+}
+func (r *authTokenFinalRep) size() int                          { panic("not implemented") }
+func (r *authTokenFinalRep) encode(enc *encoding.Encoder) error { panic("not implemented") }
+
+func (r *authTokenFinalRep) decode(dec *encoding.Decoder, ph *partHeader) error {
+	numPrm := int(dec.Int16())
+	if numPrm != 2 {
+		return fmt.Errorf("invalid number of parameters %d - expected %d", numPrm, 2)
+	}
+	if err := checkAuthMethod(string(authShortBytes.decode(dec)), r.method); err != nil {
+		return err
+	}
+	r.sessionCookie = make([]byte, decodeAuthFieldSize(dec))
+	dec.Bytes(r.sessionCookie)
+	return nil
+}
+
+/*
+tokenAuth authenticates a session with a token:
+- JWT: JSON Web Token
+- SAML: SAML assertion
+- SessionCookie: session cookie granted by the database on a previous authentication of username
+*/
+type tokenAuth struct {
+	step     int
+	method   string
+	username string
+	token    []byte
+	initRep  *authTokenInitRep
+	finalRep *authTokenFinalRep
+}
+
+func newTokenAuth(method, username string, token []byte) *tokenAuth {
+	return &tokenAuth{
+		method:   method,
+		username: username,
+		token:    token,
+		initRep:  &authTokenInitRep{method: method},
+		finalRep: &authTokenFinalRep{method: method},
+	}
+}
+
+func newJWTAuth(token string) *tokenAuth { return newTokenAuth(mnJWT, "", []byte(token)) }
+
+func newSAMLAuth(assertion []byte) *tokenAuth { return newTokenAuth(mnSAML, "", assertion) }
+
+func newSessionCookieAuth(username string, cookie []byte) *tokenAuth {
+	return newTokenAuth(mnSessionCookie, username, cookie)
+}
+
+func (a *tokenAuth) next() (partReadWriter, error) {
+	defer func() { a.step++ }()
+
+	switch a.step {
+	case 0:
+		if len(a.token) == 0 {
+			return nil, fmt.Errorf("invalid empty %s token", a.method)
+		}
+		return &authTokenInitReq{method: a.method, username: a.username, token: a.token}, nil
+	case 1:
+		return a.initRep, nil
+	case 2:
+		return &authTokenFinalReq{method: a.method, username: a.sessionUsername()}, nil
+	case 3:
+		return a.finalRep, nil
+	}
+	panic("should never happen")
+}
+
+// sessionUsername returns the database user the session is authenticated for
+// (identified by the token or the username of the session cookie).
+func (a *tokenAuth) sessionUsername() string {
+	if a.initRep.username != "" {
+		return a.initRep.username
+	}
+	return a.username
+}
+
+// sessionCookie returns the session cookie granted by the database (nil if no cookie was granted).
+func (a *tokenAuth) sessionCookie() []byte {
+	if len(a.finalRep.sessionCookie) == 0 {
+		return nil
+	}
+	return a.finalRep.sessionCookie
+}
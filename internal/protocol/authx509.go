@@ -0,0 +1,264 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+// X.509 client certificate authentication (X509)
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"errors"
+	"fmt"
+
+	"github.com/SAP/go-hdb/internal/protocol/encoding"
+)
+
+const mnX509 = "X509"
+
+const x509ServerNonceSize = 64
+
+type authX509InitReq struct{}
+
+func (r *authX509InitReq) String() string { return fmt.Sprintf("method %s", mnX509) }
+
+func (r *authX509InitReq) size() int {
+	size := int16Size // no of parameters
+	size++            // empty username
+	size += authFieldSize(len(mnX509))
+	size++ // empty parameter
+	return size
+}
+
+func (r *authX509InitReq) decode(dec *encoding.Decoder, ph *partHeader) error {
+	numPrm := int(dec.Int16())
+	if numPrm != 3 {
+		return fmt.Errorf("invalid number of parameters %d - expected %d", numPrm, 3)
+	}
+	authShortCESU8String.decode(dec) // empty username
+	if err := checkAuthMethod(string(authShortBytes.decode(dec)), mnX509); err != nil {
+		return err
+	}
+	dec.Byte() // empty parameter
+	return nil
+}
+
+func (r *authX509InitReq) encode(enc *encoding.Encoder) error {
+	enc.Int16(3)
+	// the database user is identified by the certificate
+	if err := authShortCESU8String.encode(enc, ""); err != nil {
+		return err
+	}
+	if err := encodeAuthField(enc, []byte(mnX509)); err != nil {
+		return err
+	}
+	enc.Byte(0) // empty parameter
+	return nil
+}
+
+type authX509InitRep struct {
+	serverNonce []byte
+}
+
+func (r *authX509InitRep) String() string                     { return fmt.Sprintf("serverNonce %v", r.serverNonce) }
+func (r *authX509InitRep) size() int                          { panic("not implemented") }
+func (r *authX509InitRep) encode(enc *encoding.Encoder) error { panic("not implemented") }
+
+func (r *authX509InitRep) decode(dec *encoding.Decoder, ph *partHeader) error {
+	numPrm := int(dec.Int16())
+	if numPrm != 2 {
+		return fmt.Errorf("invalid number of parameters %d - expected %d", numPrm, 2)
+	}
+	if err := checkAuthMethod(string(authShortBytes.decode(dec)), mnX509); err != nil {
+		return err
+	}
+	r.serverNonce = make([]byte, decodeAuthFieldSize(dec))
+	dec.Bytes(r.serverNonce)
+	return nil
+}
+
+/*
+authX509Prms are the sub parameters of the final X509 request:
+- the certificate (DER)
+- the intermediate certificates of the certificate chain (empty or list of certificates)
+- the signature of the certificates and the server nonce
+*/
+type authX509Prms struct {
+	cert, signature []byte
+	chain           [][]byte
+}
+
+func (p *authX509Prms) chainSize() int {
+	size := int16Size // no of certificates
+	for _, c := range p.chain {
+		size += authFieldSize(len(c))
+	}
+	return size
+}
+
+func (p *authX509Prms) size() int {
+	size := int16Size // no of parameters
+	size += authFieldSize(len(p.cert))
+	if len(p.chain) == 0 {
+		size++ // empty parameter
+	} else {
+		size += authFieldSize(p.chainSize())
+	}
+	size += authFieldSize(len(p.signature))
+	return size
+}
+
+func (p *authX509Prms) encode(enc *encoding.Encoder) error {
+	enc.Int16(3)
+	if err := encodeAuthField(enc, p.cert); err != nil {
+		return err
+	}
+	if len(p.chain) == 0 {
+		enc.Byte(0) // empty parameter
+	} else {
+		if err := encodeAuthFieldSize(enc, p.chainSize()); err != nil {
+			return err
+		}
+		enc.Int16(int16(len(p.chain)))
+		for _, c := range p.chain {
+			if err := encodeAuthField(enc, c); err != nil {
+				return err
+			}
+		}
+	}
+	return encodeAuthField(enc, p.signature)
+}
+
+type authX509FinalReq struct {
+	prms *authX509Prms
+}
+
+func (r *authX509FinalReq) String() string {
+	return fmt.Sprintf("method %s certificates %d", mnX509, 1+len(r.prms.chain))
+}
+
+func (r *authX509FinalReq) size() int {
+	size := int16Size // no of parameters
+	size++            // empty username
+	size += authFieldSize(len(mnX509))
+	size += authFieldSize(r.prms.size())
+	return size
+}
+
+func (r *authX509FinalReq) decode(dec *encoding.Decoder, ph *partHeader) error {
+	panic("not implemented")
+}
+
+func (r *authX509FinalReq) encode(enc *encoding.Encoder) error {
+	enc.Int16(3)
+	if err := authShortCESU8String.encode(enc, ""); err != nil {
+		return err
+	}
+	if err := encodeAuthField(enc, []byte(mnX509)); err != nil {
+		return err
+	}
+	if err := encodeAuthFieldSize(enc, r.prms.size()); err != nil {
+		return err
+	}
+	return r.prms.encode(enc)
+}
+
+type authX509FinalRep struct {
+	sessionCookie []byte
+}
+
+func (r *authX509FinalRep) String() string {
+	return fmt.Sprintf("method %s session cookie length %d", mnX509, len(r.sessionCookie))
+}
+func (r *authX509FinalRep) size() int                          { panic("not implemented") }
+func (r *authX509FinalRep) encode(enc *encoding.Encoder) error { panic("not implemented") }
+
+func (r *authX509FinalRep) decode(dec *encoding.Decoder, ph *partHeader) error {
+	numPrm := int(dec.Int16())
+	if numPrm != 2 {
+		return fmt.Errorf("invalid number of parameters %d - expected %d", numPrm, 2)
+	}
+	if err := checkAuthMethod(string(authShortBytes.decode(dec)), mnX509); err != nil {
+		return err
+	}
+	decodeAuthFieldSize(dec) // sub parameters
+	numPrm = int(dec.Int16())
+	if numPrm != 1 {
+		return fmt.Errorf("invalid number of parameters %d - expected %d", numPrm, 1)
+	}
+	r.sessionCookie = make([]byte, decodeAuthFieldSize(dec))
+	dec.Bytes(r.sessionCookie)
+	return nil
+}
+
+// x509Auth authenticates a session with a X.509 client certificate.
+type x509Auth struct {
+	step    int
+	cert    *tls.Certificate
+	initRep *authX509InitRep
+}
+
+func newX509Auth(cert *tls.Certificate) *x509Auth {
+	return &x509Auth{cert: cert, initRep: &authX509InitRep{}}
+}
+
+func (a *x509Auth) next() (partReadWriter, error) {
+	defer func() { a.step++ }()
+
+	switch a.step {
+	case 0:
+		if len(a.cert.Certificate) == 0 {
+			return nil, errors.New("invalid client certificate without certificate data")
+		}
+		return &authX509InitReq{}, nil
+	case 1:
+		return a.initRep, nil
+	case 2:
+		if len(a.initRep.serverNonce) != x509ServerNonceSize {
+			return nil, fmt.Errorf("invalid server nonce size %d - expected %d", len(a.initRep.serverNonce), x509ServerNonceSize)
+		}
+		// signed message: certificates followed by the server nonce
+		var message []byte
+		for _, c := range a.cert.Certificate {
+			message = append(message, c...)
+		}
+		message = append(message, a.initRep.serverNonce...)
+		signature, err := x509Sign(a.cert.PrivateKey, message)
+		if err != nil {
+			return nil, err
+		}
+		return &authX509FinalReq{prms: &authX509Prms{cert: a.cert.Certificate[0], chain: a.cert.Certificate[1:], signature: signature}}, nil
+	case 3:
+		return &authX509FinalRep{}, nil
+	}
+	panic("should never happen")
+}
+
+// x509Sign signs the message with the private key of the client certificate (RSA, ECDSA: SHA-256 digest).
+func x509Sign(key crypto.PrivateKey, message []byte) ([]byte, error) {
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("invalid client certificate private key type %T", key)
+	}
+	if _, ok := signer.Public().(ed25519.PublicKey); ok {
+		return signer.Sign(rand.Reader, message, crypto.Hash(0))
+	}
+	digest := sha256.Sum256(message)
+	return signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+// boolOption returns the value of a boolean connect option (false if not set).
+func (o connectOptions) boolOption(k connectOption) bool {
+	v, ok := o[k].(optBooleanType)
+	return ok && bool(v)
+}
+
+// intOption returns the value of an integer connect option (0 if not set).
+func (o connectOptions) intOption(k connectOption) int {
+	v, ok := o[k].(optIntType)
+	if !ok {
+		return 0
+	}
+	return int(v)
+}
+
+// SupportsInlineTableParams returns true if the server supports table parameters.
+func (s *Session) SupportsInlineTableParams() bool {
+	return s.serverOptions.boolOption(coItabParameter)
+}
+
+// SupportsCompression returns true if the server negotiated network compression.
+func (s *Session) SupportsCompression() bool {
+	return s.serverOptions.intOption(coCompressionLevelAndFlags) != 0
+}
+
+// SupportsClientReconnect returns true if the server supports transparent session recovery.
+func (s *Session) SupportsClientReconnect() bool {
+	return s.serverOptions.intOption(coClientReconnectWaitTimeout) > 0
+}
+
+// SupportsLargeBulkOperations returns true if the server supports bulk operations with more than 32K rows.
+func (s *Session) SupportsLargeBulkOperations() bool {
+	return s.serverOptions.boolOption(coSupportsLargeBulkOperations)
+}
+
+// SupportsImplicitLobStreaming returns true if the server supports implicit lob streaming.
+func (s *Session) SupportsImplicitLobStreaming() bool {
+	return s.serverOptions.boolOption(coImplicitLobStreaming)
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"testing"
+)
+
+func TestCapabilities(t *testing.T) {
+	s := &Session{}
+	if s.SupportsInlineTableParams() || s.SupportsCompression() || s.SupportsClientReconnect() {
+		t.Fatal("expected no capabilities without negotiated connect options")
+	}
+
+	s.serverOptions = connectOptions{}
+	s.serverOptions.set(coItabParameter, optBooleanType(true))
+	s.serverOptions.set(coCompressionLevelAndFlags, optIntType(0))
+	s.serverOptions.set(coClientReconnectWaitTimeout, optIntType(900))
+
+	if !s.SupportsInlineTableParams() {
+		t.Fatal("inline table parameters: expected true")
+	}
+	if s.SupportsCompression() {
+		t.Fatal("compression: expected false")
+	}
+	if !s.SupportsClientReconnect() {
+		t.Fatal("client reconnect: expected true")
+	}
+	if s.SupportsLargeBulkOperations() {
+		t.Fatal("large bulk operations: expected false")
+	}
+}
@@ -18,10 +18,13 @@ package protocol
 
 import (
 	"bytes"
+	"database/sql/driver"
 	"errors"
 	"math"
 	"testing"
 	"time"
+
+	"github.com/SAP/go-hdb/internal/protocol/encoding"
 )
 
 func assertEqualInt(t *testing.T, tc typeCode, v interface{}, r int64) {
@@ -68,6 +71,15 @@ func testConvertInteger(t *testing.T) {
 
 	// integer as string
 	assertEqualInt(t, tcInteger, "42", 42)
+
+	// boolean (tinyint)
+	assertEqualInt(t, tcTinyint, true, 1)
+	assertEqualInt(t, tcTinyint, "1", 1)
+	assertEqualInt(t, tcTinyint, "true", 1)
+	assertEqualInt(t, tcTinyint, "FALSE", 0)
+	if _, err := tcInteger.fieldType().Convert("true"); err == nil {
+		t.Fatal("conversion error expected")
+	}
 }
 
 func assertEqualFloat(t *testing.T, tc typeCode, v interface{}, r float64) {
@@ -121,6 +133,9 @@ func assertEqualTime(t *testing.T, v interface{}, r time.Time) {
 	if !cv.(time.Time).Equal(r) {
 		t.Fatalf("assert equal time failed %v - %v expected", cv, r)
 	}
+	if cv.(time.Time) != cv.(time.Time).Round(0) {
+		t.Fatalf("converted time %v carries monotonic clock reading", cv)
+	}
 }
 
 func testConvertTime(t *testing.T) {
@@ -194,6 +209,217 @@ func testConvertBytes(t *testing.T) {
 
 }
 
+func testCheckLength(t *testing.T) {
+	testData := []struct {
+		tc     typeCode
+		length int16
+		v      interface{}
+		ok     bool
+	}{
+		{tcVarchar, 5, "Hello", true},
+		{tcVarchar, 4, "Hello", false},
+		{tcVarchar, 5, "Hellö", false}, // bytes
+		{tcNvarchar, 5, "Hellö", true}, // characters
+		{tcNvarchar, 4, "Hellö", false},
+		{tcNvarchar, 2, "😀", true}, // surrogate pair
+		{tcNvarchar, 1, "😀", false},
+		{tcVarchar, 4, "𝄞", true}, // UTF-8 bytes
+		{tcVarchar, 3, "𝄞", false},
+		{tcNvarchar, 2, "𝄞", true}, // surrogate pair
+		{tcNvarchar, 1, "𝄞", false},
+		{tcNvarchar, 2, RawCESU8{0xed, 0xa0, 0xbd, 0xed, 0xb8, 0x80}, true}, // surrogate pair in CESU-8
+		{tcNvarchar, 1, RawCESU8{0xed, 0xa0, 0xbd, 0xed, 0xb8, 0x80}, false},
+		{tcNvarchar, 2, RawCESU8{0xc3, 0xb6, 0x41}, true},
+		{tcVarbinary, 2, []byte{0x01, 0x02}, true},
+		{tcVarbinary, 1, []byte{0x01, 0x02}, false},
+		{tcInteger, 1, int64(4711), true}, // no variable length field
+		{tcNvarchar, 1, nil, true},
+	}
+
+	for i, d := range testData {
+		f := &parameterField{tc: d.tc, length: d.length, mode: pmIn}
+		err := CheckLength(f, d.v)
+		if d.ok && err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		if !d.ok {
+			var lengthErr *LengthError
+			if !errors.As(err, &lengthErr) {
+				t.Fatalf("test %d: length error expected - got %v", i, err)
+			}
+			if lengthErr.FieldLength != int64(d.length) || lengthErr.Length <= lengthErr.FieldLength {
+				t.Fatalf("test %d: invalid length error %v", i, lengthErr)
+			}
+		}
+	}
+
+	// CESU-8 size of character values: surrogate pair (2 x 3 bytes) instead of 4 UTF-8 bytes
+	err := CheckLength(&parameterField{tc: tcNvarchar, length: 1, mode: pmIn}, "😀")
+	if lengthErr, ok := err.(*LengthError); !ok || !lengthErr.InChars || lengthErr.Length != 2 || lengthErr.CESU8Size != 6 {
+		t.Fatalf("invalid length error %v", err)
+	}
+}
+
+func testCharLength(t *testing.T) {
+	testData := []struct {
+		s      string
+		length int
+	}{
+		{"", 0},
+		{"Hello", 5},
+		{"Hellö", 5},
+		{"€€", 2},
+		{"𝄞𝄞aa", 6}, // surrogate pairs
+		{"😀", 2},
+		{"\U0001D11E€a", 4},
+	}
+
+	for i, d := range testData {
+		if length := CharLength(d.s); length != d.length {
+			t.Fatalf("test %d: %s length %d - expected %d", i, d.s, length, d.length)
+		}
+		// length needs to match the CESU-8 encoding of the field type
+		b := new(bytes.Buffer)
+		if err := encodeCESU8String(encoding.NewEncoder(b), d.s); err != nil {
+			t.Fatal(err)
+		}
+		if length := cesu8CharLength(b.Bytes()[1:]); length != int64(d.length) { // skip length indicator
+			t.Fatalf("test %d: %s encoded length %d - expected %d", i, d.s, length, d.length)
+		}
+	}
+}
+
+func testLengthInChars(t *testing.T) {
+	testData := []struct {
+		tc    typeCode
+		chars bool
+	}{
+		{tcNchar, true},
+		{tcNvarchar, true},
+		{tcShorttext, true},
+		{tcChar, false},
+		{tcVarchar, false},
+		{tcVarbinary, false},
+		{tcAlphanum, false},
+	}
+
+	for i, d := range testData {
+		f := &resultField{tc: d.tc, length: 10}
+		if chars := LengthInChars(f); chars != d.chars {
+			t.Fatalf("test %d: %s length in chars %t - expected %t", i, d.tc, chars, d.chars)
+		}
+	}
+}
+
+func testConvertDatePrecision(t *testing.T) {
+	testData := []time.Time{
+		time.Date(1, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(1969, time.December, 31, 23, 59, 59, 999999999, time.UTC),
+		time.Date(2000, time.February, 29, 12, 30, 45, 123456789, time.UTC),
+		time.Date(9999, time.December, 31, 23, 59, 59, 999999999, time.UTC),
+	}
+
+	for i, d := range testData {
+		// seconddate: second precision
+		sd := convertSeconddateToTime(convertTimeToSeconddate(d))
+		if sd.Nanosecond() != 0 {
+			t.Fatalf("test %d: seconddate %s - nanoseconds %d", i, sd, sd.Nanosecond())
+		}
+		if !sd.Equal(d.Truncate(time.Second)) {
+			t.Fatalf("test %d: seconddate %s - expected %s", i, sd, d.Truncate(time.Second))
+		}
+		// daydate: day precision
+		dd := convertDaydateToTime(convertTimeToDayDate(d))
+		if dd.Hour() != 0 || dd.Minute() != 0 || dd.Second() != 0 || dd.Nanosecond() != 0 {
+			t.Fatalf("test %d: daydate %s - expected zero clock", i, dd)
+		}
+		if y, m, day := d.Date(); !dd.Equal(time.Date(y, m, day, 0, 0, 0, 0, time.UTC)) {
+			t.Fatalf("test %d: daydate %s - expected %s", i, dd, time.Date(y, m, day, 0, 0, 0, 0, time.UTC))
+		}
+	}
+}
+
+func testConvertTimePrecision(t *testing.T) {
+	roundtrip := func(tc typeCode, v time.Time) time.Time {
+		b := new(bytes.Buffer)
+		if err := encodePrm(encoding.NewEncoder(b), tc, 0, driver.NamedValue{Value: v}); err != nil {
+			t.Fatal(err)
+		}
+		_, r, err := decodePrm(encoding.NewDecoder(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r.(time.Time)
+	}
+
+	testData := []time.Time{
+		time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(1, 1, 1, 12, 30, 45, 1000000, time.UTC),
+		time.Date(1, 1, 1, 23, 59, 59, 999999999, time.UTC),
+	}
+
+	for i, d := range testData {
+		// time: millisecond precision
+		tm := roundtrip(tcTime, d)
+		if !tm.Equal(d.Truncate(time.Millisecond)) {
+			t.Fatalf("test %d: time %s - expected %s", i, tm, d.Truncate(time.Millisecond))
+		}
+		// secondtime: second precision
+		st := roundtrip(tcSecondtime, d)
+		if st.Nanosecond() != 0 {
+			t.Fatalf("test %d: secondtime %s - nanoseconds %d", i, st, st.Nanosecond())
+		}
+		if !st.Equal(d.Truncate(time.Second)) {
+			t.Fatalf("test %d: secondtime %s - expected %s", i, st, d.Truncate(time.Second))
+		}
+	}
+}
+
+func testDecodeAlphanum(t *testing.T) {
+	testData := []struct {
+		dfv    int
+		ind    byte // alphanum indicator byte (data format version > 1)
+		value  string
+		stored string
+	}{
+		{dfvLevel1, 0, "0000000123", "0000000123"},
+		{dfvLevel1, 0, "0a1b", "0a1b"},
+		{4, alphanumNumeric | 10, "123", "0000000123"},
+		{4, alphanumNumeric | 10, "0000000123", "0000000123"},
+		{4, 10, "0a1b", "0a1b"},
+	}
+
+	decode := func(dfv int, ind byte, value string, f func(d *encoding.Decoder) (interface{}, error)) string {
+		b := new(bytes.Buffer)
+		if dfv == dfvLevel1 {
+			b.WriteByte(byte(len(value)))
+		} else {
+			b.WriteByte(byte(len(value) + 1))
+			b.WriteByte(ind)
+		}
+		b.WriteString(value)
+
+		dec := encoding.NewDecoder(b)
+		dec.SetDfv(dfv)
+		v, err := f(dec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(v.([]byte))
+	}
+
+	for i, d := range testData {
+		// values are decoded as transferred
+		if v := decode(d.dfv, d.ind, d.value, alphaType.decode); v != d.value {
+			t.Fatalf("test %d: value %s - expected %s", i, v, d.value)
+		}
+		// padded: values are decoded in stored form
+		if v := decode(d.dfv, d.ind, d.value, decodeAlphanumPadded); v != d.stored {
+			t.Fatalf("test %d: padded value %s - expected %s", i, v, d.stored)
+		}
+	}
+}
+
 func TestConverter(t *testing.T) {
 	tests := []struct {
 		name string
@@ -204,6 +430,12 @@ func TestConverter(t *testing.T) {
 		{"convertTime", testConvertTime},
 		{"convertString", testConvertString},
 		{"convertBytes", testConvertBytes},
+		{"checkLength", testCheckLength},
+		{"lengthInChars", testLengthInChars},
+		{"charLength", testCharLength},
+		{"convertDatePrecision", testConvertDatePrecision},
+		{"convertTimePrecision", testConvertTimePrecision},
+		{"decodeAlphanum", testDecodeAlphanum},
 	}
 
 	for _, test := range tests {
@@ -43,27 +43,31 @@ const (
 	DtBytes
 	DtLob
 	DtRows
+	DtDecimalArray
+	DtSTPoint
 )
 
-// RegisterScanType registers driver owned datatype scantypes (e.g. Decimal, Lob).
+// RegisterScanType registers driver owned datatype scantypes (e.g. Decimal, Lob, DecimalArray, STPoint).
 func RegisterScanType(dt DataType, scanType reflect.Type) {
 	scanTypeMap[dt] = scanType
 }
 
 var scanTypeMap = map[DataType]reflect.Type{
-	DtUnknown:  reflect.TypeOf((*interface{})(nil)).Elem(),
-	DtTinyint:  reflect.TypeOf((*uint8)(nil)).Elem(),
-	DtSmallint: reflect.TypeOf((*int16)(nil)).Elem(),
-	DtInteger:  reflect.TypeOf((*int32)(nil)).Elem(),
-	DtBigint:   reflect.TypeOf((*int64)(nil)).Elem(),
-	DtReal:     reflect.TypeOf((*float32)(nil)).Elem(),
-	DtDouble:   reflect.TypeOf((*float64)(nil)).Elem(),
-	DtTime:     reflect.TypeOf((*time.Time)(nil)).Elem(),
-	DtString:   reflect.TypeOf((*string)(nil)).Elem(),
-	DtBytes:    reflect.TypeOf((*[]byte)(nil)).Elem(),
-	DtDecimal:  nil, // to be registered by driver
-	DtLob:      nil, // to be registered by driver
-	DtRows:     reflect.TypeOf((*sql.Rows)(nil)).Elem(),
+	DtUnknown:      reflect.TypeOf((*interface{})(nil)).Elem(),
+	DtTinyint:      reflect.TypeOf((*uint8)(nil)).Elem(),
+	DtSmallint:     reflect.TypeOf((*int16)(nil)).Elem(),
+	DtInteger:      reflect.TypeOf((*int32)(nil)).Elem(),
+	DtBigint:       reflect.TypeOf((*int64)(nil)).Elem(),
+	DtReal:         reflect.TypeOf((*float32)(nil)).Elem(),
+	DtDouble:       reflect.TypeOf((*float64)(nil)).Elem(),
+	DtTime:         reflect.TypeOf((*time.Time)(nil)).Elem(),
+	DtString:       reflect.TypeOf((*string)(nil)).Elem(),
+	DtBytes:        reflect.TypeOf((*[]byte)(nil)).Elem(),
+	DtDecimal:      nil, // to be registered by driver
+	DtLob:          nil, // to be registered by driver
+	DtRows:         reflect.TypeOf((*sql.Rows)(nil)).Elem(),
+	DtDecimalArray: nil, // to be registered by driver
+	DtSTPoint:      nil, // to be registered by driver
 }
 
 // ScanType return the scan type (reflect.Type) of the corresponding data type.
@@ -21,11 +21,13 @@ func _() {
 	_ = x[DtBytes-10]
 	_ = x[DtLob-11]
 	_ = x[DtRows-12]
+	_ = x[DtDecimalArray-13]
+	_ = x[DtSTPoint-14]
 }
 
-const _DataType_name = "DtUnknownDtTinyintDtSmallintDtIntegerDtBigintDtRealDtDoubleDtDecimalDtTimeDtStringDtBytesDtLobDtRows"
+const _DataType_name = "DtUnknownDtTinyintDtSmallintDtIntegerDtBigintDtRealDtDoubleDtDecimalDtTimeDtStringDtBytesDtLobDtRowsDtDecimalArrayDtSTPoint"
 
-var _DataType_index = [...]uint8{0, 9, 18, 28, 37, 45, 51, 59, 68, 74, 82, 89, 94, 100}
+var _DataType_index = [...]uint8{0, 9, 18, 28, 37, 45, 51, 59, 68, 74, 82, 89, 94, 100, 114, 123}
 
 func (i DataType) String() string {
 	if i >= DataType(len(_DataType_index)-1) {
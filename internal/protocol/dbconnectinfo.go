@@ -0,0 +1,78 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"fmt"
+
+	"github.com/SAP/go-hdb/internal/protocol/encoding"
+)
+
+type dbConnectInfoType int8
+
+// database connect info option
+const (
+	ciDatabaseName dbConnectInfoType = 1 // string
+	ciHost         dbConnectInfoType = 2 // string
+	ciPort         dbConnectInfoType = 3 // int
+	ciIsConnected  dbConnectInfoType = 4 // bool
+)
+
+/*
+dbConnectInfo requests the connect information of a tenant database (by database name)
+and returns the host and port of the tenant database unless the session is connected
+to the tenant database already.
+*/
+type dbConnectInfo plainOptions
+
+func (ci dbConnectInfo) String() string {
+	return fmt.Sprintf("database name %s host %s port %d connected %t", ci.databaseName(), ci.host(), ci.port(), ci.isConnected())
+}
+
+func (ci dbConnectInfo) databaseName() string {
+	v, _ := ci[connectOption(ciDatabaseName)].(optStringType)
+	return string(v)
+}
+
+func (ci dbConnectInfo) host() string {
+	v, _ := ci[connectOption(ciHost)].(optStringType)
+	return string(v)
+}
+
+func (ci dbConnectInfo) port() int {
+	v, _ := ci[connectOption(ciPort)].(optIntType)
+	return int(v)
+}
+
+func (ci dbConnectInfo) isConnected() bool {
+	v, _ := ci[connectOption(ciIsConnected)].(optBooleanType)
+	return bool(v)
+}
+
+func (ci dbConnectInfo) size() int   { return plainOptions(ci).size() }
+func (ci dbConnectInfo) numArg() int { return len(ci) }
+
+func (ci *dbConnectInfo) decode(dec *encoding.Decoder, ph *partHeader) error {
+	*ci = dbConnectInfo{} // no reuse of maps - create new one
+	plainOptions(*ci).decode(dec, ph.numArg())
+	return dec.Error()
+}
+
+func (ci dbConnectInfo) encode(enc *encoding.Encoder) error {
+	plainOptions(ci).encode(enc)
+	return nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"database/sql/driver"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// DecimalFloatMode defines how decimal values are returned by query result sets.
+type DecimalFloatMode byte
+
+// DecimalFloatMode constants.
+const (
+	// DecimalFloatError returns decimal values as exact decimal values (default). Scanning into float types
+	// fails, so precision is never lost silently.
+	DecimalFloatError DecimalFloatMode = iota
+	// DecimalFloatRound returns decimal values as float64 rounded to the nearest float64 value.
+	DecimalFloatRound
+	// DecimalString returns decimal values as exact decimal text formatted with the scale of the field.
+	DecimalString
+)
+
+// maxDecimalScale is the maximum scale of decimal fields with defined precision. Fields of floating point
+// decimal types (SMALLDECIMAL, DECIMAL without precision) report a larger fraction.
+const maxDecimalScale = 38
+
+var float64ReflectType = reflect.TypeOf((*float64)(nil)).Elem()
+
+// decimalFloat64 returns the nearest float64 value of a decimal value in decimal128 format.
+func decimalFloat64(b []byte) float64 {
+	m, exp := decodeDecimal128(b)
+
+	x := new(big.Rat).SetInt(m)
+	p := new(big.Rat).SetInt(exp10(abs(exp)))
+	if exp < 0 {
+		x.Quo(x, p)
+	} else {
+		x.Mul(x, p)
+	}
+	f, _ := x.Float64()
+	return f
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+// convertDecimalFloat converts decimal values into float64 values (DecimalFloatRound).
+func (r *queryResultSet) convertDecimalFloat(dest []driver.Value) {
+	for i, v := range dest {
+		switch v := v.(type) {
+		case []byte:
+			if r.rr.field(i).typeCode().isDecimalType() && len(v) == decimalFieldSize {
+				dest[i] = decimalFloat64(v)
+			}
+		case *big.Rat: // fixed decimal
+			dest[i], _ = v.Float64()
+		}
+	}
+}
+
+// decimalString returns the text of a decimal value in decimal128 format. The fractional part is padded
+// with zeros up to scale digits. Fractional digits exceeding scale are kept, so no precision is lost.
+func decimalString(b []byte, scale int) string {
+	m, exp := decodeDecimal128(b) // negative zero is decoded as zero
+
+	if exp > 0 {
+		m.Mul(m, exp10(exp))
+		exp = 0
+	}
+	if scale >= 0 && scale <= maxDecimalScale && -exp < scale {
+		m.Mul(m, exp10(scale+exp))
+		exp = -scale
+	}
+
+	neg := m.Sign() < 0
+	s := m.Abs(m).String()
+
+	var sb strings.Builder
+	if neg {
+		sb.WriteByte('-')
+	}
+	if frac := -exp; frac > 0 {
+		if len(s) <= frac {
+			s = strings.Repeat("0", frac-len(s)+1) + s
+		}
+		sb.WriteString(s[:len(s)-frac])
+		sb.WriteByte('.')
+		sb.WriteString(s[len(s)-frac:])
+	} else {
+		sb.WriteString(s)
+	}
+	return sb.String()
+}
+
+// convertDecimalString converts decimal values into their text representation (DecimalString).
+func (r *queryResultSet) convertDecimalString(dest []driver.Value) {
+	for i, v := range dest {
+		switch v := v.(type) {
+		case []byte:
+			if f := r.rr.field(i); f.typeCode().isDecimalType() && len(v) == decimalFieldSize {
+				_, scale, _ := f.TypePrecisionScale()
+				dest[i] = decimalString(v, int(scale))
+			}
+		case *big.Rat: // fixed decimal: exact with the scale of the field
+			_, scale, _ := r.rr.field(i).TypePrecisionScale()
+			dest[i] = v.FloatString(int(scale))
+		}
+	}
+}
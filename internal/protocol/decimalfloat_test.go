@@ -0,0 +1,83 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// testDecimal128 encodes a decimal value with mantissa m (< 2^64) and exponent exp in decimal128 format.
+func testDecimal128(m uint64, exp int, neg bool) []byte {
+	b := make([]byte, decimalFieldSize)
+	binary.LittleEndian.PutUint64(b, m)
+	e := uint16(exp+Dec128Bias) << 1
+	b[14] = byte(e)
+	b[15] = byte(e >> 8)
+	if neg {
+		b[15] |= 0x80
+	}
+	return b
+}
+
+func TestDecimalFloat64(t *testing.T) {
+	testData := []struct {
+		m   uint64
+		exp int
+		neg bool
+		f   float64
+	}{
+		{0, 0, false, 0},
+		{4711, -2, false, 47.11},
+		{4711, -2, true, -47.11},
+		{42, 3, false, 42000},
+		{1, -1, false, 0.1},
+		{12345678901234567, -16, false, 1.2345678901234567},
+	}
+
+	for i, d := range testData {
+		if f := decimalFloat64(testDecimal128(d.m, d.exp, d.neg)); f != d.f {
+			t.Fatalf("test %d: value %v - expected %v", i, f, d.f)
+		}
+	}
+}
+
+func TestDecimalString(t *testing.T) {
+	testData := []struct {
+		m     uint64
+		exp   int
+		neg   bool
+		scale int
+		s     string
+	}{
+		{123, -2, false, 4, "1.2300"},
+		{123, -2, true, 4, "-1.2300"},
+		{0, 0, true, 4, "0.0000"},  // negative zero
+		{0, -2, true, 0, "0.00"},   // negative zero
+		{5, -3, false, 2, "0.005"}, // more fractional digits than scale
+		{42, 3, false, 0, "42000"}, // positive exponent
+		{42, 3, false, 2, "42000.00"},
+		{4711, -2, false, 32767, "47.11"}, // floating point decimal
+		{1, -1, false, 32767, "0.1"},
+	}
+
+	for i, d := range testData {
+		if s := decimalString(testDecimal128(d.m, d.exp, d.neg), d.scale); s != d.s {
+			t.Fatalf("test %d: value %s - expected %s", i, s, d.s)
+		}
+	}
+}
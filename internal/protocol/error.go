@@ -17,6 +17,7 @@ limitations under the License.
 package protocol
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/SAP/go-hdb/internal/protocol/encoding"
@@ -63,6 +64,16 @@ func (e *hdbError) Error() string {
 	return fmt.Sprintf("SQL %s %d - %s", e.errorLevel, e.errorCode, e.errorText)
 }
 
+// HasErrorCode returns true if err is a database error and any of its single errors
+// (in case of multiple errors returned by the database server) matches one of the codes.
+func HasErrorCode(err error, codes ...int) bool {
+	var e *hdbErrors
+	if !errors.As(err, &e) {
+		return false
+	}
+	return e.hasCode(codes...)
+}
+
 type hdbErrors struct {
 	errors []*hdbError
 	//numArg int
@@ -125,6 +136,11 @@ func (e *hdbErrors) Text() string {
 	return string(e.errors[e.idx].errorText)
 }
 
+// SQLState implements the driver.Error interface.
+func (e *hdbErrors) SQLState() string {
+	return string(e.errors[e.idx].sqlState[:])
+}
+
 // IsWarning implements the driver.Error interface.
 func (e *hdbErrors) IsWarning() bool {
 	return e.errors[e.idx].errorLevel == errorLevelWarning
@@ -146,6 +162,17 @@ func (e *hdbErrors) setStmtNo(idx, no int) {
 	}
 }
 
+func (e *hdbErrors) hasCode(codes ...int) bool {
+	for _, _error := range e.errors {
+		for _, code := range codes {
+			if int(_error.errorCode) == code {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (e *hdbErrors) isWarnings() bool {
 	for _, _error := range e.errors {
 		if _error.errorLevel != errorLevelWarning {
@@ -155,6 +182,16 @@ func (e *hdbErrors) isWarnings() bool {
 	return true
 }
 
+// clone returns a copy of the errors not sharing memory with the part read by the protocol reader.
+func (e *hdbErrors) clone() *hdbErrors {
+	c := &hdbErrors{errors: make([]*hdbError, len(e.errors))}
+	for i, _error := range e.errors {
+		cerr := *_error
+		c.errors[i] = &cerr
+	}
+	return c
+}
+
 func (e *hdbErrors) reset(numArg int) {
 	e.idx = 0 // init error index
 	if e.errors == nil || numArg > cap(e.errors) {
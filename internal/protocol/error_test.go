@@ -0,0 +1,53 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestHasErrorCode(t *testing.T) {
+	e := &hdbErrors{errors: []*hdbError{
+		{errorCode: 301, errorLevel: errorLevelError, sqlState: sqlState{'2', '3', '0', '0', '0'}},
+		{errorCode: 133, errorLevel: errorLevelError},
+	}}
+
+	testData := []struct {
+		err   error
+		codes []int
+		has   bool
+	}{
+		{e, []int{301}, true},
+		{e, []int{133}, true},
+		{e, []int{259, 133}, true},
+		{e, []int{259}, false},
+		{fmt.Errorf("wrapped: %w", e), []int{133}, true},
+		{fmt.Errorf("no database error"), []int{301}, false},
+		{nil, []int{301}, false},
+	}
+
+	for i, d := range testData {
+		if has := HasErrorCode(d.err, d.codes...); has != d.has {
+			t.Fatalf("%d: has error code %v %t - expected %t", i, d.codes, has, d.has)
+		}
+	}
+
+	if e.SQLState() != "23000" {
+		t.Fatalf("sql state %s - expected %s", e.SQLState(), "23000")
+	}
+}
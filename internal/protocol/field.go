@@ -18,9 +18,11 @@ package protocol
 
 import (
 	"database/sql/driver"
+	"fmt"
 	"sort"
 
 	"github.com/SAP/go-hdb/internal/protocol/encoding"
+	"github.com/SAP/go-hdb/internal/unicode/cesu8"
 )
 
 const noFieldName uint32 = 0xFFFFFFFF
@@ -88,6 +90,7 @@ type Field interface {
 	In() bool
 	Out() bool
 	Converter() Converter
+	typeCode() typeCode
 }
 
 var (
@@ -95,6 +98,115 @@ var (
 	_ Field = (*parameterField)(nil)
 )
 
+// LengthError is returned by CheckLength in case a value exceeds the length of a variable length field.
+type LengthError struct {
+	FieldName   string // Name of the field ("<unnamed>" for fields without name).
+	TypeName    string // Database type name of the field.
+	FieldLength int64  // Length of the field.
+	Length      int64  // Length of the value (measured like the field length).
+	InChars     bool   // Lengths are measured in characters (see LengthInChars) instead of bytes.
+	CESU8Size   int    // Size of the CESU-8 encoded value in bytes (character based fields only).
+}
+
+func (e *LengthError) Error() string {
+	if e.InChars { // utf-8 and CESU-8 character counts might differ (surrogate pairs)
+		return fmt.Sprintf("field %s: value CESU-8 length %d (%d CESU-8 bytes) exceeds length %d of %s field", e.FieldName, e.Length, e.CESU8Size, e.FieldLength, e.TypeName)
+	}
+	return fmt.Sprintf("field %s: value length %d exceeds length %d of %s field", e.FieldName, e.Length, e.FieldLength, e.TypeName)
+}
+
+/*
+CheckLength checks if the length of a variable length field value exceeds the length of the field,
+so that values are never truncated silently. The length of character based fields (e.g. NVARCHAR)
+is measured in characters like by the database, where characters outside the basic multilingual plane
+count twice (CESU-8 surrogate pairs). The length of all other variable length fields (e.g. VARCHAR,
+VARBINARY) is measured in bytes. In case the value is too long a *LengthError is returned.
+*/
+func CheckLength(f Field, v driver.Value) error {
+	tc := f.typeCode()
+	fieldLength, ok := f.TypeLength()
+	if !ok || fieldLength <= 0 {
+		return nil
+	}
+
+	var b []byte
+	switch v := v.(type) {
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	case RawCESU8:
+		b = v
+	default:
+		return nil
+	}
+
+	chars := LengthInChars(f)
+	length := int64(len(b))
+	if chars {
+		if _, ok := v.(RawCESU8); ok {
+			length = cesu8CharLength(b)
+		} else {
+			length = int64(CharLength(string(b)))
+		}
+	}
+	if length <= fieldLength {
+		return nil
+	}
+	err := &LengthError{FieldName: f.Name(), TypeName: tc.typeName(), FieldLength: fieldLength, Length: length, InChars: chars}
+	if err.FieldName == "" {
+		err.FieldName = "<unnamed>"
+	}
+	if chars {
+		if _, ok := v.(RawCESU8); ok {
+			err.CESU8Size = len(b)
+		} else {
+			err.CESU8Size = cesu8.Size(b)
+		}
+	}
+	return err
+}
+
+/*
+LengthInChars returns true if the type length of a field (see Field.TypeLength) is measured in characters
+and false if it is measured in bytes. The length of character based fields (NCHAR, NVARCHAR, SHORTTEXT, ...)
+is the number of CESU-8 characters, where characters outside the basic multilingual plane count twice, so
+a buffer of 3 bytes per character is sufficient for the CESU-8 encoded value and 4 bytes per character for
+the UTF-8 encoded value. The length of all other variable length fields (CHAR, VARCHAR, BINARY, VARBINARY, ...)
+is the number of bytes.
+*/
+func LengthInChars(f Field) bool {
+	return f.typeCode().fieldType() == cesu8Type
+}
+
+/*
+CharLength returns the length of s in characters like measured by the database for character based
+fields (see LengthInChars): the number of CESU-8 (UTF-16) code units, where characters outside the basic
+multilingual plane count twice.
+*/
+func CharLength(s string) int {
+	n := 0
+	for _, r := range s {
+		if r > 0xFFFF { // surrogate pair
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// cesu8CharLength returns the number of characters of CESU-8 encoded bytes b counted in CESU-8 (UTF-16) code units.
+func cesu8CharLength(b []byte) int64 {
+	var n int64
+	for _, c := range b {
+		if c&0xC0 != 0x80 { // no continuation byte
+			n++
+		}
+	}
+	return n
+}
+
 // TODO cache
 func newFieldValues(size int) []driver.Value {
 	return make([]driver.Value, size)
@@ -17,6 +17,7 @@ limitations under the License.
 package protocol
 
 import (
+	"bytes"
 	"database/sql/driver"
 	"errors"
 	"fmt"
@@ -24,6 +25,7 @@ import (
 	"math"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/SAP/go-hdb/internal/protocol/encoding"
@@ -134,8 +136,8 @@ func prmSize(tc typeCode, arg driver.NamedValue) int {
 	return tc.fieldType().prmSize(v)
 }
 
-// encode parameter
-func encodePrm(e *encoding.Encoder, tc typeCode, arg driver.NamedValue) error {
+// encode parameter (scale: fraction of the parameter field)
+func encodePrm(e *encoding.Encoder, tc typeCode, scale int, arg driver.NamedValue) error {
 	v := arg.Value
 	encTc := tc.encTc()
 	if v == nil && tc != tcSecondtime { // secondTime exception (see (*1))
@@ -143,6 +145,9 @@ func encodePrm(e *encoding.Encoder, tc typeCode, arg driver.NamedValue) error {
 		return nil
 	}
 	e.Byte(byte(encTc)) // type code
+	if ft, ok := tc.fieldType().(_fixedType); ok {
+		return ft.encodeFixed(e, v, scale)
+	}
 	return tc.fieldType().encodePrm(e, v)
 }
 
@@ -172,14 +177,16 @@ func decodePrm(d *encoding.Decoder) (typeCode, interface{}, error) {
 }
 
 /*
-decode result
+decode result (scale: fraction of the result field)
 */
-func decodeRes(d *encoding.Decoder, tc typeCode) (interface{}, error) {
+func decodeRes(d *encoding.Decoder, tc typeCode, scale int) (interface{}, error) {
 	ft := tc.fieldType()
 
 	switch ft := ft.(type) {
 	default:
 		panic("field type missing decoder")
+	case _fixedType:
+		return decodeFixedRes(d, ft, scale)
 	case resDecoder:
 		return ft.decodeRes(d)
 	case commonDecoder:
@@ -202,6 +209,7 @@ var (
 	daydateType    = _daydateType{}
 	secondtimeType = _secondtimeType{}
 	decimalType    = _decimalType{}
+	decArrayType   = _decArrayType{}
 	varType        = _varType{}
 	alphaType      = _alphaType{}
 	cesu8Type      = _cesu8Type{}
@@ -223,6 +231,7 @@ type _seconddateType struct{}
 type _daydateType struct{}
 type _secondtimeType struct{}
 type _decimalType struct{}
+type _decArrayType struct{}
 type _varType struct{}
 type _alphaType struct{}
 type _cesu8Type struct{}
@@ -244,6 +253,7 @@ var (
 	_ fieldType = (*_daydateType)(nil)
 	_ fieldType = (*_secondtimeType)(nil)
 	_ fieldType = (*_decimalType)(nil)
+	_ fieldType = (*_decArrayType)(nil)
 	_ fieldType = (*_varType)(nil)
 	_ fieldType = (*_alphaType)(nil)
 	_ fieldType = (*_cesu8Type)(nil)
@@ -282,6 +292,7 @@ func (_seconddateType) String() string { return "seconddateType" }
 func (_daydateType) String() string    { return "daydateType" }
 func (_secondtimeType) String() string { return "secondtimeType" }
 func (_decimalType) String() string    { return "decimalType" }
+func (_decArrayType) String() string   { return "decArrayType" }
 func (_varType) String() string        { return "varType" }
 func (_alphaType) String() string      { return "alphaType" }
 func (_cesu8Type) String() string      { return "cesu8Type" }
@@ -289,6 +300,16 @@ func (_lobVarType) String() string     { return "lobVarType" }
 func (_lobCESU8Type) String() string   { return "lobCESU8Type" }
 
 func (ft _tinyintType) Convert(v interface{}) (interface{}, error) {
+	// bool is represented in HDB as tinyint: accept boolean literals
+	// (BOOLEAN and TINYINT parameters share the type code, so this applies to both)
+	if s, ok := v.(string); ok {
+		switch {
+		case strings.EqualFold(s, "true"):
+			return int64(1), nil
+		case strings.EqualFold(s, "false"):
+			return int64(0), nil
+		}
+	}
 	return convertInteger(ft, v, minTinyint, maxTinyint)
 }
 func (ft _smallintType) Convert(v interface{}) (interface{}, error) {
@@ -418,6 +439,7 @@ func (ft _daydateType) Convert(v interface{}) (interface{}, error)    { return c
 func (ft _secondtimeType) Convert(v interface{}) (interface{}, error) { return convertTime(ft, v) }
 
 // time
+// time values are converted using the wall clock only (monotonic clock reading is stripped via Round(0))
 func convertTime(ft fieldType, v interface{}) (driver.Value, error) {
 	if v == nil {
 		return nil, nil
@@ -426,7 +448,7 @@ func convertTime(ft fieldType, v interface{}) (driver.Value, error) {
 	switch v := v.(type) {
 
 	case time.Time:
-		return v, nil
+		return v.Round(0), nil
 	}
 
 	rv := reflect.ValueOf(v)
@@ -443,12 +465,15 @@ func convertTime(ft fieldType, v interface{}) (driver.Value, error) {
 
 	if rv.Type().ConvertibleTo(timeReflectType) {
 		tv := rv.Convert(timeReflectType)
-		return tv.Interface().(time.Time), nil
+		return tv.Interface().(time.Time).Round(0), nil
 	}
 	return nil, newConvertError(ft, v, nil)
 }
 
 func (ft _decimalType) Convert(v interface{}) (interface{}, error) { return convertDecimal(ft, v) }
+func (ft _decArrayType) Convert(v interface{}) (interface{}, error) {
+	return convertDecArray(ft, v)
+}
 
 // decimal
 func convertDecimal(ft fieldType, v interface{}) (driver.Value, error) {
@@ -461,9 +486,29 @@ func convertDecimal(ft fieldType, v interface{}) (driver.Value, error) {
 	return nil, newConvertError(ft, v, nil)
 }
 
+// decimal array (sequence of decimal fields)
+func convertDecArray(ft fieldType, v interface{}) (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if v, ok := v.([]byte); ok && len(v)%decimalFieldSize == 0 {
+		return v, nil
+	}
+	return nil, newConvertError(ft, v, nil)
+}
+
 func (ft _varType) Convert(v interface{}) (interface{}, error)   { return convertBytes(ft, v) }
 func (ft _alphaType) Convert(v interface{}) (interface{}, error) { return convertBytes(ft, v) }
-func (ft _cesu8Type) Convert(v interface{}) (interface{}, error) { return convertBytes(ft, v) }
+func (ft _cesu8Type) Convert(v interface{}) (interface{}, error) {
+	if v, ok := v.(RawCESU8); ok { // sent without transformation
+		return v, nil
+	}
+	return convertBytes(ft, v)
+}
+
+// RawCESU8 is a value of CESU-8 encoded bytes which is sent to the database verbatim
+// or fetched from the database without transformation (see Session.SetRawCESU8).
+type RawCESU8 []byte
 
 // bytes
 func convertBytes(ft fieldType, v interface{}) (driver.Value, error) {
@@ -475,6 +520,8 @@ func convertBytes(ft fieldType, v interface{}) (driver.Value, error) {
 
 	case string, []byte:
 		return v, nil
+	case RawCESU8: // non unicode fields: bytes are sent verbatim anyway
+		return []byte(v), nil
 	}
 
 	rv := reflect.ValueOf(v)
@@ -523,27 +570,32 @@ func convertLob(isCharBased bool, ft fieldType, v interface{}) (driver.Value, er
 		return v, nil
 	case ReadProvider:
 		return v.Reader(), nil
+	case string:
+		return strings.NewReader(v), nil
+	case []byte:
+		return bytes.NewReader(v), nil
 	default:
 		return nil, newConvertError(ft, v, nil)
 	}
 }
 
-func (_tinyintType) prmSize(interface{}) int    { return tinyintFieldSize }
-func (_smallintType) prmSize(interface{}) int   { return smallintFieldSize }
-func (_integerType) prmSize(interface{}) int    { return integerFieldSize }
-func (_bigintType) prmSize(interface{}) int     { return bigintFieldSize }
-func (_realType) prmSize(interface{}) int       { return realFieldSize }
-func (_doubleType) prmSize(interface{}) int     { return doubleFieldSize }
-func (_dateType) prmSize(interface{}) int       { return dateFieldSize }
-func (_timeType) prmSize(interface{}) int       { return timeFieldSize }
-func (_timestampType) prmSize(interface{}) int  { return timestampFieldSize }
-func (_longdateType) prmSize(interface{}) int   { return longdateFieldSize }
-func (_seconddateType) prmSize(interface{}) int { return seconddateFieldSize }
-func (_daydateType) prmSize(interface{}) int    { return daydateFieldSize }
-func (_secondtimeType) prmSize(interface{}) int { return secondtimeFieldSize }
-func (_decimalType) prmSize(interface{}) int    { return decimalFieldSize }
-func (_lobVarType) prmSize(v interface{}) int   { return lobInputParametersSize }
-func (_lobCESU8Type) prmSize(v interface{}) int { return lobInputParametersSize }
+func (_tinyintType) prmSize(interface{}) int       { return tinyintFieldSize }
+func (_smallintType) prmSize(interface{}) int      { return smallintFieldSize }
+func (_integerType) prmSize(interface{}) int       { return integerFieldSize }
+func (_bigintType) prmSize(interface{}) int        { return bigintFieldSize }
+func (_realType) prmSize(interface{}) int          { return realFieldSize }
+func (_doubleType) prmSize(interface{}) int        { return doubleFieldSize }
+func (_dateType) prmSize(interface{}) int          { return dateFieldSize }
+func (_timeType) prmSize(interface{}) int          { return timeFieldSize }
+func (_timestampType) prmSize(interface{}) int     { return timestampFieldSize }
+func (_longdateType) prmSize(interface{}) int      { return longdateFieldSize }
+func (_seconddateType) prmSize(interface{}) int    { return seconddateFieldSize }
+func (_daydateType) prmSize(interface{}) int       { return daydateFieldSize }
+func (_secondtimeType) prmSize(interface{}) int    { return secondtimeFieldSize }
+func (_decimalType) prmSize(interface{}) int       { return decimalFieldSize }
+func (ft _decArrayType) prmSize(v interface{}) int { return varType.prmSize(v) }
+func (_lobVarType) prmSize(v interface{}) int      { return lobInputParametersSize }
+func (_lobCESU8Type) prmSize(v interface{}) int    { return lobInputParametersSize }
 
 func (ft _varType) prmSize(v interface{}) int {
 	switch v := v.(type) {
@@ -560,6 +612,8 @@ func (ft _alphaType) prmSize(v interface{}) int {
 }
 func (ft _cesu8Type) prmSize(v interface{}) int {
 	switch v := v.(type) {
+	case RawCESU8:
+		return varBytesSize(ft, len(v))
 	case []byte:
 		return varBytesSize(ft, cesu8.Size(v))
 	case string:
@@ -732,7 +786,7 @@ func asTime(ft fieldType, v interface{}) (time.Time, error) {
 	if !ok {
 		return zeroTime, newConvertError(ft, v, nil)
 	}
-	//store in utc
+	//store in utc (wall clock only - UTC() strips the monotonic clock reading)
 	return t.UTC(), nil
 }
 
@@ -748,6 +802,17 @@ func (ft _decimalType) encodePrm(e *encoding.Encoder, v interface{}) error {
 	return nil
 }
 
+func (ft _decArrayType) encodePrm(e *encoding.Encoder, v interface{}) error {
+	p, ok := v.([]byte)
+	if !ok {
+		return newConvertError(ft, v, nil)
+	}
+	if len(p)%decimalFieldSize != 0 {
+		return fmt.Errorf("invalid argument length %d - expected multiple of %d", len(p), decimalFieldSize)
+	}
+	return encodeVarBytes(e, p)
+}
+
 func (ft _varType) encodePrm(e *encoding.Encoder, v interface{}) error {
 	switch v := v.(type) {
 	case []byte:
@@ -795,6 +860,12 @@ func encodeVarString(e *encoding.Encoder, s string) error {
 
 func (ft _cesu8Type) encodePrm(e *encoding.Encoder, v interface{}) error {
 	switch v := v.(type) {
+	case RawCESU8:
+		if err := encodeVarBytesSize(e, len(v)); err != nil {
+			return err
+		}
+		e.Bytes(v)
+		return nil
 	case []byte:
 		return encodeCESU8Bytes(e, v)
 	case string:
@@ -987,6 +1058,25 @@ func (_decimalType) decode(d *encoding.Decoder) (interface{}, error) {
 	return b, nil
 }
 
+/*
+decimal array:
+- length indicated like variable length fields
+- content is a sequence of decimal fields each encoded in decimal128 format
+  (each element is carrying its own exponent (scale))
+*/
+func (ft _decArrayType) decode(d *encoding.Decoder) (interface{}, error) {
+	size, null := decodeVarBytesSize(d)
+	if null {
+		return nil, nil
+	}
+	b := make([]byte, size)
+	d.Bytes(b)
+	if size%decimalFieldSize != 0 {
+		return nil, fmt.Errorf("invalid decimal array size %d - expected multiple of %d", size, decimalFieldSize)
+	}
+	return b, nil
+}
+
 func (_varType) decode(d *encoding.Decoder) (interface{}, error) {
 	size, null := decodeVarBytesSize(d)
 	if null {
@@ -1027,6 +1117,43 @@ func (_cesu8Type) decode(d *encoding.Decoder) (interface{}, error) {
 	return d.CESU8Bytes(size), nil
 }
 
+// decodeRawCESU8 decodes a unicode character field without the CESU-8 to UTF-8 transformation.
+func decodeRawCESU8(d *encoding.Decoder) (interface{}, error) {
+	size, null := decodeVarBytesSize(d)
+	if null {
+		return nil, nil
+	}
+	b := make(RawCESU8, size)
+	d.Bytes(b)
+	return b, nil
+}
+
+// alphanumNumeric is the flag of the alphanum indicator byte marking purely numeric values.
+const alphanumNumeric = 0x80
+
+/*
+decodeAlphanumPadded decodes an alphanum field returning purely numeric values in the stored form
+(padded with leading zeros to the field size) independent of the data format version (see Session.SetAlphanumPadding).
+*/
+func decodeAlphanumPadded(d *encoding.Decoder) (interface{}, error) {
+	if d.Dfv() == dfvLevel1 { // transferred in stored form
+		return alphaType.decode(d)
+	}
+	size, null := decodeVarBytesSize(d)
+	if null {
+		return nil, nil
+	}
+	ind := d.Byte()
+	b := make([]byte, size-1)
+	d.Bytes(b)
+	if ind&alphanumNumeric != 0 {
+		if fieldSize := int(ind &^ alphanumNumeric); len(b) < fieldSize {
+			return append(bytes.Repeat([]byte{'0'}, fieldSize-len(b)), b...), nil
+		}
+	}
+	return b, nil
+}
+
 func decodeVarBytesSize(d *encoding.Decoder) (int, bool) {
 	ind := d.Byte() //length indicator
 	switch {
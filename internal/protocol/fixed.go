@@ -0,0 +1,210 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/SAP/go-hdb/internal/protocol/encoding"
+)
+
+/*
+fixed decimal types (data format version 8):
+- FIXED8, FIXED12, FIXED16 are decimals with a defined precision and scale transferred as
+  two's complement little endian integer (mantissa) of 8, 12 or 16 bytes
+- the scale is not part of the value but provided by the field metadata (fraction)
+- parameter values are handed from the driver in decimal128 format like DECIMAL values
+- result values are handed to the driver as exact *big.Rat values, as FIXED16 values (up to 2^127)
+  might exceed the decimal128 precision of 34 digits
+*/
+
+const (
+	fixed8FieldSize  = 8
+	fixed12FieldSize = 12
+	fixed16FieldSize = 16
+)
+
+const dec128Digits = 34
+
+var (
+	fixed8Type  = _fixedType{size: fixed8FieldSize}
+	fixed12Type = _fixedType{size: fixed12FieldSize}
+	fixed16Type = _fixedType{size: fixed16FieldSize}
+)
+
+type _fixedType struct {
+	size int
+}
+
+var _ fieldType = (*_fixedType)(nil)
+
+func (ft _fixedType) String() string { return fmt.Sprintf("fixed%dType", ft.size) }
+
+func (ft _fixedType) Convert(v interface{}) (interface{}, error) { return convertDecimal(ft, v) }
+
+func (ft _fixedType) prmSize(interface{}) int { return ft.size }
+
+// encodePrm is not supported as the encoding depends on the scale of the field (see encodeFixed).
+func (ft _fixedType) encodePrm(e *encoding.Encoder, v interface{}) error {
+	return fmt.Errorf("%s: missing field scale", ft)
+}
+
+// decodePrm decodes the mantissa only as the scale is not part of the value (sniffer).
+func (ft _fixedType) decodePrm(d *encoding.Decoder) (interface{}, error) { return ft.decodeFixed(d, 0) }
+
+// decodeFixed decodes a fixed value with scale fractional digits into an exact *big.Rat value.
+func (ft _fixedType) decodeFixed(d *encoding.Decoder, scale int) (interface{}, error) {
+	b := make([]byte, ft.size)
+	d.Bytes(b)
+	// little endian -> big endian
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	m := new(big.Int).SetBytes(b)
+	if b[0]&0x80 != 0 { // negative (two's complement)
+		m.Sub(m, new(big.Int).Lsh(natOne, uint(ft.size*8)))
+	}
+	return new(big.Rat).SetFrac(m, exp10(scale)), nil
+}
+
+// encodeFixed encodes a decimal128 value as fixed value with scale fractional digits.
+// The value is rounded (half away from zero) to scale fractional digits.
+func (ft _fixedType) encodeFixed(e *encoding.Encoder, v interface{}, scale int) error {
+	p, ok := v.([]byte)
+	if !ok {
+		return newConvertError(ft, v, nil)
+	}
+	if len(p) != decimalFieldSize {
+		return fmt.Errorf("invalid argument length %d - expected %d", len(p), decimalFieldSize)
+	}
+	m, exp := decodeDecimal128(p)
+
+	// rescale mantissa to scale fractional digits
+	switch shift := exp + scale; {
+	case shift > 0:
+		m.Mul(m, exp10(shift))
+	case shift < 0:
+		q, r := new(big.Int), new(big.Int)
+		q.QuoRem(m, exp10(-shift), r)
+		if r.Abs(r).Lsh(r, 1).Cmp(exp10(-shift)) >= 0 {
+			if m.Sign() < 0 {
+				q.Sub(q, natOne)
+			} else {
+				q.Add(q, natOne)
+			}
+		}
+		m = q
+	}
+
+	limit := new(big.Int).Lsh(natOne, uint(ft.size*8-1))
+	if m.Cmp(limit) >= 0 || m.Cmp(new(big.Int).Neg(limit)) < 0 {
+		return fmt.Errorf("%s: value out of range", ft)
+	}
+	if m.Sign() < 0 { // two's complement
+		m.Add(m, new(big.Int).Lsh(limit, 1))
+	}
+
+	b := make([]byte, ft.size)
+	mb := m.Bytes() // big endian
+	for i := 0; i < len(mb); i++ {
+		b[i] = mb[len(mb)-1-i]
+	}
+	e.Bytes(b)
+	return nil
+}
+
+// fixed decimal results are preceded by a null indicator like integer results.
+func decodeFixedRes(d *encoding.Decoder, ft _fixedType, scale int) (interface{}, error) {
+	if !d.Bool() { //null value
+		return nil, nil
+	}
+	return ft.decodeFixed(d, scale)
+}
+
+var (
+	natOne = big.NewInt(1)
+	natTen = big.NewInt(10)
+)
+
+func exp10(n int) *big.Int { return new(big.Int).Exp(natTen, big.NewInt(int64(n)), nil) }
+
+// wordSize is the size of a big.Word in bytes.
+const wordSize = bits.UintSize / 8
+
+// Dec128Bias is the exponent bias of the decimal128 format.
+const Dec128Bias = 6176
+
+// DecimalExp returns the exponent of a value in decimal128 format.
+func DecimalExp(b []byte) int {
+	return int((((uint16(b[15])<<8)|uint16(b[14]))<<1)>>2) - Dec128Bias
+}
+
+/*
+DecodeDecimal decodes a value in decimal128 format (BID encoding) by setting m to the unsigned mantissa
+and returns if the value is negative and its exponent. b is not modified.
+*/
+func DecodeDecimal(b []byte, m *big.Int) (neg bool, exp int) {
+	neg = (b[15] & 0x80) != 0
+	exp = DecimalExp(b)
+
+	// mantissa: bytes 0..13 and lowest bit of byte 14 (rest: sign and exp)
+	mb := func(i int) byte {
+		if i == 14 {
+			return b[14] & 0x01
+		}
+		return b[i]
+	}
+
+	//most significand byte
+	msb := 14
+	for msb > 0 {
+		if mb(msb) != 0 {
+			break
+		}
+		msb--
+	}
+
+	//calc number of words
+	numWords := (msb / wordSize) + 1
+	w := make([]big.Word, numWords)
+
+	k := numWords - 1
+	d := big.Word(0)
+	for i := msb; i >= 0; i-- {
+		d |= big.Word(mb(i))
+		if k*wordSize == i {
+			w[k] = d
+			k--
+			d = 0
+		}
+		d <<= 8
+	}
+	m.SetBits(w)
+	return neg, exp
+}
+
+// decodeDecimal128 returns the signed mantissa and the exponent of a value in decimal128 format.
+func decodeDecimal128(b []byte) (*big.Int, int) {
+	m := new(big.Int)
+	neg, exp := DecodeDecimal(b, m)
+	if neg {
+		m.Neg(m)
+	}
+	return m, exp
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/SAP/go-hdb/internal/protocol/encoding"
+)
+
+// encodeDecimal128 encodes the value m * 10^exp in decimal128 format. Trailing zeros of the mantissa
+// are removed only if the mantissa exceeds the decimal128 precision of 34 digits.
+func encodeDecimal128(m *big.Int, exp int) (driver.Value, error) {
+	neg := m.Sign() < 0
+	m = new(big.Int).Abs(m)
+
+	max := exp10(dec128Digits)
+	q, r := new(big.Int), new(big.Int)
+	for m.Cmp(max) >= 0 {
+		q.QuoRem(m, natTen, r)
+		if r.Sign() != 0 {
+			return nil, fmt.Errorf("decimal: value exceeds %d digits", dec128Digits)
+		}
+		m.Set(q)
+		exp++
+	}
+
+	b := make([]byte, decimalFieldSize)
+	mb := m.Bytes() // big endian, at most 113 bits
+	for i := 0; i < len(mb); i++ {
+		b[i] = mb[len(mb)-1-i]
+	}
+	w := uint16(exp+Dec128Bias) << 1
+	b[14] |= byte(w)
+	b[15] = byte(w >> 8)
+	if neg {
+		b[15] |= 0x80
+	}
+	return b, nil
+}
+
+func mustParseInt(t *testing.T, s string) *big.Int {
+	i, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		t.Fatalf("invalid integer %s", s)
+	}
+	return i
+}
+
+func testFixedRoundtrip(t *testing.T) {
+	testData := []struct {
+		ft    _fixedType
+		m     string
+		scale int
+	}{
+		{fixed8Type, "0", 2},
+		{fixed8Type, "150", 2},
+		{fixed8Type, "-150", 2},
+		{fixed8Type, "9223372036854775807", 0},  // max int64
+		{fixed8Type, "-9223372036854775808", 0}, // min int64
+		{fixed8Type, "-1", 18},
+		{fixed12Type, "39614081257132168796771975167", 4},  // 2^95 - 1
+		{fixed12Type, "-39614081257132168796771975168", 4}, // -2^95
+		{fixed12Type, "-255", 1},
+		{fixed16Type, "9999999999999999999999999999999999", 10},  // 34 digits
+		{fixed16Type, "-9999999999999999999999999999999999", 10}, // 34 digits
+		{fixed16Type, "12345678901234567890123456789012340000", 6},
+		{fixed16Type, "-65536", 0},
+	}
+
+	for i, d := range testData {
+		m := mustParseInt(t, d.m)
+
+		v, err := encodeDecimal128(m, -d.scale)
+		if err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		buf := new(bytes.Buffer)
+		if err := d.ft.encodeFixed(encoding.NewEncoder(buf), v, d.scale); err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		if buf.Len() != d.ft.size {
+			t.Fatalf("test %d: size %d - expected %d", i, buf.Len(), d.ft.size)
+		}
+		r, err := d.ft.decodeFixed(encoding.NewDecoder(buf), d.scale)
+		if err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		x := r.(*big.Rat)
+		if e := new(big.Rat).SetFrac(m, exp10(d.scale)); x.Cmp(e) != 0 {
+			t.Fatalf("test %d: value %s - expected %s", i, x.FloatString(d.scale), e.FloatString(d.scale))
+		}
+	}
+}
+
+func testFixedDecode(t *testing.T) {
+	testData := []struct {
+		m     string
+		scale int
+		text  string
+	}{
+		{"170141183460469231731687303715884105727", 0, "170141183460469231731687303715884105727"},   // 2^127 - 1
+		{"-170141183460469231731687303715884105727", 0, "-170141183460469231731687303715884105727"}, // -(2^127 - 1)
+		{"-170141183460469231731687303715884105728", 0, "-170141183460469231731687303715884105728"}, // -2^127
+		{"12345678901234567890123456789012345678", 10, "1234567890123456789012345678.9012345678"},   // 38 digits
+		{"-12345678901234567890123456789012345671", 38, "-0.12345678901234567890123456789012345671"},
+	}
+
+	for i, d := range testData {
+		m := mustParseInt(t, d.m)
+		if m.Sign() < 0 { // two's complement
+			m.Add(m, new(big.Int).Lsh(natOne, fixed16FieldSize*8))
+		}
+		b := make([]byte, fixed16FieldSize)
+		mb := m.Bytes() // big endian
+		for j := 0; j < len(mb); j++ {
+			b[j] = mb[len(mb)-1-j]
+		}
+
+		r, err := fixed16Type.decodeFixed(encoding.NewDecoder(bytes.NewReader(b)), d.scale)
+		if err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		if text := r.(*big.Rat).FloatString(d.scale); text != d.text {
+			t.Fatalf("test %d: value %s - expected %s", i, text, d.text)
+		}
+	}
+}
+
+func testFixedRounding(t *testing.T) {
+	testData := []struct {
+		m      int64
+		exp    int
+		scale  int
+		result int64
+	}{
+		{12345, -3, 2, 1235},   // 12.345 -> 12.35
+		{-12345, -3, 2, -1235}, // -12.345 -> -12.35
+		{12344, -3, 2, 1234},   // 12.344 -> 12.34
+		{15, 0, 2, 1500},       // 15 -> 15.00
+		{5, -1, 0, 1},          // 0.5 -> 1
+		{-5, -1, 0, -1},        // -0.5 -> -1
+		{4, -1, 0, 0},          // 0.4 -> 0
+	}
+
+	for i, d := range testData {
+		v, err := encodeDecimal128(big.NewInt(d.m), d.exp)
+		if err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		buf := new(bytes.Buffer)
+		if err := fixed8Type.encodeFixed(encoding.NewEncoder(buf), v, d.scale); err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		if m := encoding.NewDecoder(buf).Int64(); m != d.result {
+			t.Fatalf("test %d: mantissa %d - expected %d", i, m, d.result)
+		}
+	}
+}
+
+func testFixedOutOfRange(t *testing.T) {
+	testData := []struct {
+		ft    _fixedType
+		m     string
+		scale int
+	}{
+		{fixed8Type, "9223372036854775808", 0},            // max int64 + 1
+		{fixed8Type, "-9223372036854775809", 0},           // min int64 - 1
+		{fixed8Type, "1", 19},                             // scale overflow: 10^19
+		{fixed12Type, "39614081257132168796771975168", 0}, // 2^95
+	}
+
+	for i, d := range testData {
+		m := mustParseInt(t, d.m)
+		v, err := encodeDecimal128(m, 0)
+		if err != nil {
+			t.Fatalf("test %d: %s", i, err)
+		}
+		if err := d.ft.encodeFixed(encoding.NewEncoder(new(bytes.Buffer)), v, d.scale); err == nil {
+			t.Fatalf("test %d: out of range error expected", i)
+		}
+	}
+}
+
+func TestFixed(t *testing.T) {
+	tests := []struct {
+		name string
+		fct  func(t *testing.T)
+	}{
+		{"roundtrip", testFixedRoundtrip},
+		{"decode", testFixedDecode},
+		{"rounding", testFixedRounding},
+		{"outOfRange", testFixedOutOfRange},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.fct(t)
+		})
+	}
+}
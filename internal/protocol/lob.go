@@ -17,10 +17,16 @@ limitations under the License.
 package protocol
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"sync/atomic"
+
+	"golang.org/x/text/transform"
 
 	"github.com/SAP/go-hdb/internal/protocol/encoding"
+	"github.com/SAP/go-hdb/internal/unicode"
 )
 
 const (
@@ -85,11 +91,22 @@ const (
 // WriterSetter is the interface wrapping the SetWriter method (Lob handling).
 type WriterSetter interface{ SetWriter(w io.Writer) error }
 
+// ReaderGetter is the interface wrapping the Reader method (lazy lob handling).
+type ReaderGetter interface{ Reader() io.Reader }
+
 // sessionSetter is the interface wrapping the setSession method (lob handling).
 type sessionSetter interface{ setSession(s *Session) }
 
+// resultSetSetter is the interface wrapping the setResultSet method (lazy lob handling).
+type resultSetSetter interface{ setResultSet(rs *queryResultSet) }
+
 var _ WriterSetter = (*lobOutDescr)(nil)
+var _ ReaderGetter = (*lobOutDescr)(nil)
 var _ sessionSetter = (*lobOutDescr)(nil)
+var _ resultSetSetter = (*lobOutDescr)(nil)
+
+// ErrLobReaderClosed is returned by a lazy lob reader after the rows or the session the lob was read from are closed.
+var ErrLobReaderClosed = errors.New("lob reader closed")
 
 /*
 TODO description
@@ -117,6 +134,7 @@ lobOutDescr
 */
 type lobOutDescr struct {
 	s           *Session
+	rs          *queryResultSet // result set of a lob column (nil for output parameters)
 	isCharBased bool
 	/*
 		HDB does not return lob type code but undefined only
@@ -134,11 +152,123 @@ type lobOutDescr struct {
 func (d *lobOutDescr) String() string {
 	return fmt.Sprintf("typecode %s options %s numChar %d numByte %d id %d bytes %v", d.ltc, d.opt, d.numChar, d.numByte, d.id, d.b)
 }
-func (d *lobOutDescr) setSession(s *Session) { d.s = s }
+func (d *lobOutDescr) setSession(s *Session)           { d.s = s }
+func (d *lobOutDescr) setResultSet(rs *queryResultSet) { d.rs = rs }
+
+// context returns the context lob chunks are read with (see Session.SetContext).
+func (d *lobOutDescr) context() context.Context {
+	if d.rs != nil {
+		return d.rs.ctx
+	}
+	return d.s.ctx
+}
 
 // SetWriter implements the WriterSetter interface.
 func (d *lobOutDescr) SetWriter(wr io.Writer) error { return d.s.decodeLobs(d, wr) }
 
+// Reader implements the ReaderGetter interface.
+func (d *lobOutDescr) Reader() io.Reader {
+	if d.isCharBased {
+		return transform.NewReader(newLobReader(d, countLobChars), unicode.Cesu8ToUtf8Transformer) // CESU8 transformer
+	}
+	return newLobReader(d, countLobBytes)
+}
+
+/*
+lobReader reads a lob field from the database on demand (lazy lob handling):
+- the data included in the lob descriptor is returned first
+- the remaining data is requested in chunks of the session lob chunk size via the lob locator
+  on subsequent Read calls
+- the reader is invalidated (ErrLobReaderClosed) as soon as the result set or the session is closed,
+  as the lob locator is not valid anymore
+*/
+type lobReader struct {
+	descr      *lobOutDescr
+	countChars func(b []byte) (int64, error)
+	lobRequest *readLobRequest
+	lobReply   *readLobReply
+	last       []byte // chunk read last
+	b          []byte // data of last chunk not yet returned by Read
+	eof        bool
+	err        error
+}
+
+func newLobReader(descr *lobOutDescr, countChars func(b []byte) (int64, error)) *lobReader {
+	return &lobReader{
+		descr:      descr,
+		countChars: countChars,
+		lobRequest: &readLobRequest{id: descr.id},
+		lobReply:   &readLobReply{},
+		last:       descr.b,
+		b:          descr.b,
+		eof:        descr.opt.isLastData(),
+	}
+}
+
+func (r *lobReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	if len(r.b) == 0 && r.eof {
+		return 0, io.EOF
+	}
+	if err := r.checkValid(); err != nil {
+		return 0, err
+	}
+	if len(r.b) == 0 {
+		if err := r.fetch(); err != nil {
+			r.err = err
+			return 0, err
+		}
+	}
+	n := copy(p, r.b)
+	r.b = r.b[n:]
+	return n, nil
+}
+
+// checkValid checks, if the lob locator is still valid.
+func (r *lobReader) checkValid() error {
+	if r.descr.rs != nil && atomic.LoadInt32(&r.descr.rs.closed) != 0 {
+		return fmt.Errorf("%w: rows closed", ErrLobReaderClosed)
+	}
+	if atomic.LoadInt32(&r.descr.s.closed) != 0 {
+		return fmt.Errorf("%w: session closed", ErrLobReaderClosed)
+	}
+	return nil
+}
+
+// fetch reads the next lob chunk from the database.
+func (r *lobReader) fetch() error {
+	ofs, err := r.countChars(r.last) // offset in characters
+	if err != nil {
+		return err
+	}
+	r.lobRequest.ofs += ofs
+
+	s := r.descr.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := r.checkValid(); err != nil { // closed while waiting for the session
+		return err
+	}
+	if s.IsBad() {
+		return fmt.Errorf("%w: session is bad", ErrLobReaderClosed)
+	}
+
+	r.lobRequest.chunkSize = s.lobChunkSize(r.descr.numChar, r.lobRequest.ofs)
+	if r.lobRequest.chunkSize <= 0 { // inconsistent number of characters - let the database decide
+		r.lobRequest.chunkSize = s.cfg.LobChunkSize()
+	}
+	if err := s.readLobChunk(r.descr.context(), r.lobRequest, r.lobReply); err != nil {
+		return err
+	}
+	r.last = r.lobReply.b
+	r.b = r.lobReply.b
+	r.eof = r.lobReply.opt.isLastData()
+	return nil
+}
+
 /*
 write lobs:
 - write lob field to database in chunks
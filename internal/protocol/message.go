@@ -26,6 +26,11 @@ const (
 	messageHeaderSize = 32 //nolint:varcheck
 )
 
+// packet options
+const (
+	poCompressed = 0x02 // message varpart is compressed (LZ4)
+)
+
 //message header
 type messageHeader struct {
 	sessionID     int64
@@ -33,15 +38,20 @@ type messageHeader struct {
 	varPartLength uint32
 	varPartSize   uint32
 	noOfSegm      int16
+	packetOptions int8
+	// uncompressed varpart length of a compressed message (packet option poCompressed)
+	compressionVarPartLength uint32
 }
 
 func (h *messageHeader) String() string {
-	return fmt.Sprintf("session id %d packetCount %d varPartLength %d, varPartSize %d noOfSegm %d",
+	return fmt.Sprintf("session id %d packetCount %d varPartLength %d, varPartSize %d noOfSegm %d packetOptions %d compressionVarPartLength %d",
 		h.sessionID,
 		h.packetCount,
 		h.varPartLength,
 		h.varPartSize,
-		h.noOfSegm)
+		h.noOfSegm,
+		h.packetOptions,
+		h.compressionVarPartLength)
 }
 
 func (h *messageHeader) encode(enc *encoding.Encoder) error {
@@ -50,7 +60,10 @@ func (h *messageHeader) encode(enc *encoding.Encoder) error {
 	enc.Uint32(h.varPartLength)
 	enc.Uint32(h.varPartSize)
 	enc.Int16(h.noOfSegm)
-	enc.Zeroes(10) //messageHeaderSize
+	enc.Int8(h.packetOptions)
+	enc.Zeroes(1)
+	enc.Uint32(h.compressionVarPartLength)
+	enc.Zeroes(4) //messageHeaderSize
 	return nil
 }
 
@@ -60,6 +73,9 @@ func (h *messageHeader) decode(dec *encoding.Decoder) error {
 	h.varPartLength = dec.Uint32()
 	h.varPartSize = dec.Uint32()
 	h.noOfSegm = dec.Int16()
-	dec.Skip(10) //messageHeaderSize
+	h.packetOptions = dec.Int8()
+	dec.Skip(1)
+	h.compressionVarPartLength = dec.Uint32()
+	dec.Skip(4) //messageHeaderSize
 	return dec.Error()
 }
@@ -47,4 +47,5 @@ const (
 	mtExecuteITab     messageType = 78
 	mtFetchNextITab   messageType = 79
 	mtInsertNextITab  messageType = 80
+	mtDBConnectInfo   messageType = 82
 )
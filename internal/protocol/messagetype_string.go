@@ -33,6 +33,7 @@ func _() {
 	_ = x[mtExecuteITab-78]
 	_ = x[mtFetchNextITab-79]
 	_ = x[mtInsertNextITab-80]
+	_ = x[mtDBConnectInfo-82]
 }
 
 const (
@@ -42,6 +43,7 @@ const (
 	_messageType_name_3 = "mtWriteLobmtReadLobmtFindLob"
 	_messageType_name_4 = "mtAuthenticatemtConnectmtCommitmtRollbackmtCloseResultsetmtDropStatementIDmtFetchNextmtFetchAbsolutemtFetchRelativemtFetchFirstmtFetchLast"
 	_messageType_name_5 = "mtDisconnectmtExecuteITabmtFetchNextITabmtInsertNextITab"
+	_messageType_name_6 = "mtDBConnectInfo"
 )
 
 var (
@@ -69,6 +71,8 @@ func (i messageType) String() string {
 	case 77 <= i && i <= 80:
 		i -= 77
 		return _messageType_name_5[_messageType_index_5[i]:_messageType_index_5[i+1]]
+	case i == 82:
+		return _messageType_name_6
 	default:
 		return "messageType(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
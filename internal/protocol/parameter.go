@@ -100,6 +100,7 @@ func (f *parameterField) String() string {
 }
 
 func (f *parameterField) Converter() Converter { return f.tc.fieldType() }
+func (f *parameterField) typeCode() typeCode   { return f.tc }
 
 // TypeName returns the type name of the field.
 // see https://golang.org/pkg/database/sql/driver/#RowsColumnTypeDatabaseTypeName
@@ -133,6 +134,12 @@ func (f *parameterField) Nullable() bool {
 	return f.parameterOptions == poOptional
 }
 
+// InOutValue is the argument value of an in- and output parameter (INOUT) of a procedure call.
+type InOutValue struct {
+	In   interface{} // converted input value
+	Dest interface{} // output parameter destination
+}
+
 // in returns true if the parameter field is an input field.
 func (f *parameterField) In() bool {
 	return f.mode == pmInout || f.mode == pmIn
@@ -234,7 +241,7 @@ func (p *inputParameters) encode(enc *encoding.Encoder) error {
 	for i, arg := range p.args {
 		//mass insert
 		f := p.inputFields[i%cnt]
-		if err := encodePrm(enc, f.tc, arg); err != nil {
+		if err := encodePrm(enc, f.tc, int(f.fraction), arg); err != nil {
 			return err
 		}
 	}
@@ -259,7 +266,7 @@ func (p *outputParameters) decode(dec *encoding.Decoder, ph *partHeader) error {
 	for i := 0; i < numArg; i++ {
 		for j, field := range p.outputFields {
 			var err error
-			if p.fieldValues[i*cols+j], err = decodeRes(dec, field.tc); err != nil {
+			if p.fieldValues[i*cols+j], err = decodeRes(dec, field.tc, int(field.fraction)); err != nil {
 				return err
 			}
 		}
@@ -54,8 +54,17 @@ func (*authInitReq) kind() partKind         { return pkAuthentication }
 func (*authInitRep) kind() partKind         { return pkAuthentication }
 func (*authFinalReq) kind() partKind        { return pkAuthentication }
 func (*authFinalRep) kind() partKind        { return pkAuthentication }
+func (*authTokenInitReq) kind() partKind    { return pkAuthentication }
+func (*authTokenInitRep) kind() partKind    { return pkAuthentication }
+func (*authTokenFinalReq) kind() partKind   { return pkAuthentication }
+func (*authTokenFinalRep) kind() partKind   { return pkAuthentication }
+func (*authX509InitReq) kind() partKind     { return pkAuthentication }
+func (*authX509InitRep) kind() partKind     { return pkAuthentication }
+func (*authX509FinalReq) kind() partKind    { return pkAuthentication }
+func (*authX509FinalRep) kind() partKind    { return pkAuthentication }
 func (clientID) kind() partKind             { return pkClientID }
 func (connectOptions) kind() partKind       { return pkConnectOptions }
+func (dbConnectInfo) kind() partKind        { return pkDBConnectInfo }
 func (*topologyInformation) kind() partKind { return pkTopologyInformation }
 func (command) kind() partKind              { return pkCommand }
 func (*rowsAffected) kind() partKind        { return pkRowsAffected }
@@ -83,8 +92,17 @@ var (
 	_ part = (*authInitRep)(nil)
 	_ part = (*authFinalReq)(nil)
 	_ part = (*authFinalRep)(nil)
+	_ part = (*authTokenInitReq)(nil)
+	_ part = (*authTokenInitRep)(nil)
+	_ part = (*authTokenFinalReq)(nil)
+	_ part = (*authTokenFinalRep)(nil)
+	_ part = (*authX509InitReq)(nil)
+	_ part = (*authX509InitRep)(nil)
+	_ part = (*authX509FinalReq)(nil)
+	_ part = (*authX509FinalRep)(nil)
 	_ part = (*clientID)(nil)
 	_ part = (*connectOptions)(nil)
+	_ part = (*dbConnectInfo)(nil)
 	_ part = (*topologyInformation)(nil)
 	_ part = (*command)(nil)
 	_ part = (*rowsAffected)(nil)
@@ -113,16 +131,24 @@ type partWriter interface {
 }
 
 // numArg methods (result == 1)
-func (*authInitReq) numArg() int    { return 1 }
-func (*authInitRep) numArg() int    { return 1 }
-func (*authFinalReq) numArg() int   { return 1 }
-func (*authFinalRep) numArg() int   { return 1 }
-func (clientID) numArg() int        { return 1 }
-func (command) numArg() int         { return 1 }
-func (statementID) numArg() int     { return 1 }
-func (resultsetID) numArg() int     { return 1 }
-func (fetchsize) numArg() int       { return 1 }
-func (*readLobRequest) numArg() int { return 1 }
+func (*authInitReq) numArg() int       { return 1 }
+func (*authInitRep) numArg() int       { return 1 }
+func (*authFinalReq) numArg() int      { return 1 }
+func (*authFinalRep) numArg() int      { return 1 }
+func (*authTokenInitReq) numArg() int  { return 1 }
+func (*authTokenInitRep) numArg() int  { return 1 }
+func (*authTokenFinalReq) numArg() int { return 1 }
+func (*authTokenFinalRep) numArg() int { return 1 }
+func (*authX509InitReq) numArg() int   { return 1 }
+func (*authX509InitRep) numArg() int   { return 1 }
+func (*authX509FinalReq) numArg() int  { return 1 }
+func (*authX509FinalRep) numArg() int  { return 1 }
+func (clientID) numArg() int           { return 1 }
+func (command) numArg() int            { return 1 }
+func (statementID) numArg() int        { return 1 }
+func (resultsetID) numArg() int        { return 1 }
+func (fetchsize) numArg() int          { return 1 }
+func (*readLobRequest) numArg() int    { return 1 }
 
 // func (lobFlags) numArg() int                   { return 1 }
 
@@ -145,8 +171,13 @@ func (readLobRequest) size() int { return readLobRequestSize }
 var (
 	_ partWriter = (*authInitReq)(nil)
 	_ partWriter = (*authFinalReq)(nil)
+	_ partWriter = (*authTokenInitReq)(nil)
+	_ partWriter = (*authTokenFinalReq)(nil)
+	_ partWriter = (*authX509InitReq)(nil)
+	_ partWriter = (*authX509FinalReq)(nil)
 	_ partWriter = (*clientID)(nil)
 	_ partWriter = (*connectOptions)(nil)
+	_ partWriter = (*dbConnectInfo)(nil)
 	_ partWriter = (*command)(nil)
 	_ partWriter = (*statementID)(nil)
 	_ partWriter = (*inputParameters)(nil)
@@ -170,8 +201,17 @@ var (
 	_ partReader = (*authInitRep)(nil)
 	_ partReader = (*authFinalReq)(nil)
 	_ partReader = (*authFinalRep)(nil)
+	_ partReader = (*authTokenInitReq)(nil)
+	_ partReader = (*authTokenInitRep)(nil)
+	_ partReader = (*authTokenFinalReq)(nil)
+	_ partReader = (*authTokenFinalRep)(nil)
+	_ partReader = (*authX509InitReq)(nil)
+	_ partReader = (*authX509InitRep)(nil)
+	_ partReader = (*authX509FinalReq)(nil)
+	_ partReader = (*authX509FinalRep)(nil)
 	_ partReader = (*clientID)(nil)
 	_ partReader = (*connectOptions)(nil)
+	_ partReader = (*dbConnectInfo)(nil)
 	_ partReader = (*topologyInformation)(nil)
 	_ partReader = (*command)(nil)
 	_ partReader = (*rowsAffected)(nil)
@@ -212,6 +252,7 @@ var partTypeMap = map[partKind]reflect.Type{
 	pkError:               reflect.TypeOf((*hdbErrors)(nil)).Elem(),
 	pkClientID:            reflect.TypeOf((*clientID)(nil)).Elem(),
 	pkConnectOptions:      reflect.TypeOf((*connectOptions)(nil)).Elem(),
+	pkDBConnectInfo:       reflect.TypeOf((*dbConnectInfo)(nil)).Elem(),
 	pkTopologyInformation: reflect.TypeOf((*topologyInformation)(nil)).Elem(),
 	pkCommand:             reflect.TypeOf((*command)(nil)).Elem(),
 	pkRowsAffected:        reflect.TypeOf((*rowsAffected)(nil)).Elem(),
@@ -18,13 +18,16 @@ package protocol
 
 import (
 	"bufio"
+	"bytes"
 	"database/sql/driver"
 	"errors"
 	"fmt"
 	"io"
 	"math"
+	"sync/atomic"
 
 	"github.com/SAP/go-hdb/driver/sqltrace"
+	"github.com/SAP/go-hdb/internal/lz4"
 	"github.com/SAP/go-hdb/internal/protocol/encoding"
 )
 
@@ -47,6 +50,7 @@ var (
 
 // A PrepareResult represents the result of a prepare statement.
 type PrepareResult struct {
+	query        string
 	fc           functionCode
 	stmtID       uint64
 	prmFields    []*parameterField
@@ -105,13 +109,34 @@ func (pr *PrepareResult) PrmField(idx int) Field {
 	return pr.prmFields[idx]
 }
 
+// NumResultField returns the number of result fields in a database statement.
+func (pr *PrepareResult) NumResultField() int {
+	return len(pr.resultFields)
+}
+
+// ResultField returns the result field at index idx.
+func (pr *PrepareResult) ResultField(idx int) Field {
+	return pr.resultFields[idx]
+}
+
 // A QueryResult represents the resultset of a query.
 type queryResult struct {
-	_rsID       uint64
-	fields      []*resultField
-	fieldValues []driver.Value
-	attributes  partAttributes
-	_columns    []string
+	_rsID           uint64
+	fields          []*resultField
+	fieldValues     []driver.Value
+	attributes      partAttributes
+	_columns        []string
+	rawCESU8        bool // unicode character data is fetched as CESU-8 bytes (see Session.SetRawCESU8)
+	alphanumPadding bool // numeric alphanum values are fetched in stored form (see Session.SetAlphanumPadding)
+}
+
+// resultFields returns the result fields as Field slice.
+func (qr *queryResult) resultFields() []Field {
+	fields := make([]Field, len(qr.fields))
+	for i, f := range qr.fields {
+		fields[i] = f
+	}
+	return fields
 }
 
 // RsID implements the RowsResult interface.
@@ -148,6 +173,32 @@ func (qr *queryResult) lastPacket() bool {
 	return qr.attributes.LastPacket()
 }
 
+/*
+unexpectedResultset keeps track of a result set returned by a statement execution which does
+not expect a result (e.g. DML statements returning a result set depending on session settings).
+The result set content is skipped and an open result set is closed to release the server side handle.
+*/
+type unexpectedResultset struct {
+	id         uint64
+	attributes partAttributes
+}
+
+func (rs *unexpectedResultset) handlePart(pr *protocolReader, ph *partHeader) {
+	switch ph.partKind {
+	case pkResultsetID:
+		pr.read((*resultsetID)(&rs.id))
+	case pkResultset:
+		rs.attributes = ph.partAttributes
+	}
+}
+
+func (rs *unexpectedResultset) close(s *Session) error {
+	if rs.id == 0 || rs.attributes.ResultsetClosed() {
+		return nil
+	}
+	return s.closeResultsetID(rs.id)
+}
+
 // Columns implements the RowsResult interface.
 func (qr *queryResult) columns() []string {
 	if qr._columns == nil {
@@ -222,6 +273,26 @@ func (cr *callResult) queryResult() (*queryResult, error) {
 	return nil, errors.New("cannot use call result as query result")
 }
 
+// assignOutArgs assigns the output parameter values to the output arguments.
+func (cr *callResult) assignOutArgs(s *Session, outArgs []driver.NamedValue) error {
+	if cr.numRow() == 0 {
+		return nil
+	}
+	if len(outArgs) != len(cr.outputFields) {
+		return fmt.Errorf("invalid number of output arguments %d - expected %d", len(outArgs), len(cr.outputFields))
+	}
+	for i, arg := range outArgs {
+		v := cr.fieldValues[i]
+		if v, ok := v.(sessionSetter); ok {
+			v.setSession(s)
+		}
+		if err := assignValue(arg.Value, v); err != nil {
+			return fmt.Errorf("output parameter %s: %w", cr.outputFields[i].Name(), err)
+		}
+	}
+	return nil
+}
+
 func (cr *callResult) appendTableRefFields() {
 	for i, qr := range cr.qrs {
 		cr.outputFields = append(cr.outputFields, &parameterField{name: fmt.Sprintf("table %d", i), tc: tcTableRef, mode: pmOut, offset: 0})
@@ -260,6 +331,8 @@ type protocolReader struct {
 	lastErrors       *hdbErrors
 	lastRowsAffected *rowsAffected
 
+	warningHandler func(warning error) // called for database warnings (nil: warnings are traced only)
+
 	// partReader read errors could be
 	// - read buffer errors -> buffer Error() and ResetError()
 	// - plus other errors (which cannot be ignored, e.g. Lob reader)
@@ -345,6 +418,9 @@ func (r *protocolReader) checkError() error {
 		for _, e := range r.lastErrors.errors {
 			sqltrace.Traceln(e)
 		}
+		if r.warningHandler != nil {
+			r.warningHandler(r.lastErrors.clone()) // errors part is reused by the reader
+		}
 		return nil
 	}
 
@@ -493,6 +569,16 @@ func (r *protocolReader) iterateParts(partCb func(ph *partHeader)) error {
 
 	r.msgSize = int64(r.mh.varPartLength)
 
+	if r.mh.packetOptions&poCompressed != 0 {
+		dec, err := r.decompress()
+		if err != nil {
+			return err
+		}
+		defer func(dec *encoding.Decoder) { r.dec = dec }(r.dec)
+		r.dec = dec
+		r.msgSize = int64(r.mh.compressionVarPartLength)
+	}
+
 	for i := 0; i < int(r.mh.noOfSegm); i++ {
 
 		if err := r.sh.decode(r.dec); err != nil {
@@ -528,11 +614,52 @@ func (r *protocolReader) iterateParts(partCb func(ph *partHeader)) error {
 	return r.checkError()
 }
 
+// maxMessageSize is the maximal varpart size of a message (segment and part lengths are int32 values).
+const maxMessageSize = math.MaxInt32
+
+/*
+decompress reads the compressed varpart of the current message and returns a decoder of the uncompressed varpart.
+The uncompressed varpart length is checked against the maximal message size and the maximal decompressed size
+of the compressed varpart before the buffer is allocated.
+*/
+func (r *protocolReader) decompress() (*encoding.Decoder, error) {
+	b := make([]byte, r.mh.varPartLength)
+	r.dec.Bytes(b)
+	if err := r.dec.ResetError(); err != nil {
+		return nil, err
+	}
+	if size := int64(r.mh.compressionVarPartLength); size > maxMessageSize || size > int64(lz4.DecompressBound(len(b))) {
+		return nil, fmt.Errorf("invalid uncompressed varpart length %d - compressed varpart length %d", size, len(b))
+	}
+	ub := make([]byte, r.mh.compressionVarPartLength)
+	n, err := lz4.Decode(ub, b)
+	if err != nil {
+		return nil, err
+	}
+	if n != len(ub) {
+		return nil, fmt.Errorf("invalid uncompressed varpart length %d - expected %d", n, len(ub))
+	}
+	dec := encoding.NewDecoder(bytes.NewReader(ub))
+	dec.SetDfv(r.dec.Dfv())
+	return dec, nil
+}
+
+// minCompressSize is the minimal varpart size of a message to be compressed.
+const minCompressSize = 1024
+
 // protocol writer
 type protocolWriter struct {
 	wr  *bufio.Writer
 	enc *encoding.Encoder
 
+	numRequest uint64 // number of requests written (atomic access)
+
+	// network compression (see SessionConfig.Compression)
+	compress bool
+	cbuf     *bytes.Buffer     // uncompressed varpart
+	cenc     *encoding.Encoder // encoder of the uncompressed varpart
+	cb       []byte            // compressed varpart
+
 	tracer traceLogger
 
 	// reuse header
@@ -575,6 +702,7 @@ func (w *protocolWriter) writeProlog() error {
 }
 
 func (w *protocolWriter) write(sessionID int64, messageType messageType, commit bool, writers ...partWriter) error {
+	atomic.AddUint64(&w.numRequest, 1)
 
 	numWriters := len(writers)
 	partSize := make([]int, numWriters)
@@ -596,12 +724,29 @@ func (w *protocolWriter) write(sessionID int64, messageType messageType, commit
 	w.mh.varPartLength = uint32(size)
 	w.mh.varPartSize = uint32(bufferSize)
 	w.mh.noOfSegm = 1
+	w.mh.packetOptions = 0
+	w.mh.compressionVarPartLength = 0
+
+	if w.compress && size >= minCompressSize {
+		return w.writeCompressed(messageType, commit, writers, partSize, size)
+	}
 
 	if err := w.mh.encode(w.enc); err != nil {
 		return err
 	}
 	w.tracer.Log(w.mh)
 
+	if err := w.writeSegment(messageType, commit, writers, partSize, size); err != nil {
+		return err
+	}
+	return w.wr.Flush()
+}
+
+// writeSegment writes the segment of a message of varpart size.
+func (w *protocolWriter) writeSegment(messageType messageType, commit bool, writers []partWriter, partSize []int, size int64) error {
+	numWriters := len(writers)
+	bufferSize := size
+
 	if size > math.MaxInt32 {
 		return fmt.Errorf("message size %d exceeds maximum part header value %d", size, math.MaxInt32)
 	}
@@ -647,5 +792,42 @@ func (w *protocolWriter) write(sessionID int64, messageType messageType, commit
 
 		bufferSize -= int64(partHeaderSize + size + pad)
 	}
+	return nil
+}
+
+/*
+writeCompressed writes a message of varpart size with a compressed varpart. In case the compressed varpart
+is not smaller than the uncompressed one, the message is written uncompressed.
+*/
+func (w *protocolWriter) writeCompressed(messageType messageType, commit bool, writers []partWriter, partSize []int, size int64) error {
+	if w.cbuf == nil {
+		w.cbuf = new(bytes.Buffer)
+		w.cenc = encoding.NewEncoder(w.cbuf)
+	}
+	w.cbuf.Reset()
+
+	enc := w.enc
+	w.enc = w.cenc // encode varpart into buffer
+	err := w.writeSegment(messageType, commit, writers, partSize, size)
+	w.enc = enc
+	if err != nil {
+		return err
+	}
+
+	varPart := w.cbuf.Bytes()
+	w.cb = lz4.Encode(w.cb[:0], varPart)
+	if len(w.cb) < len(varPart) {
+		w.mh.packetOptions = poCompressed
+		w.mh.compressionVarPartLength = uint32(len(varPart))
+		w.mh.varPartLength = uint32(len(w.cb))
+		w.mh.varPartSize = uint32(len(w.cb))
+		varPart = w.cb
+	}
+
+	if err := w.mh.encode(w.enc); err != nil {
+		return err
+	}
+	w.tracer.Log(w.mh)
+	w.enc.Bytes(varPart)
 	return w.wr.Flush()
 }
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestCompressedMessage(t *testing.T) {
+	testData := []struct {
+		cmd        command
+		compressed bool
+	}{
+		{command("select * from dummy"), false}, // below minimal compression size
+		{command("select " + strings.Repeat("'abcdefgh', ", 1000) + "1 from dummy"), true},
+	}
+
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+	pw := newProtocolWriter(wr)
+	pw.compress = true
+	pr := newProtocolReader(true, buf)
+
+	for i, d := range testData {
+		if err := pw.write(1, mtExecuteDirect, false, d.cmd); err != nil {
+			t.Fatal(err)
+		}
+		size := buf.Len()
+
+		var cmd command
+		if err := pr.iterateParts(func(ph *partHeader) {
+			if ph.partKind == pkCommand {
+				pr.read(&cmd)
+			}
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if buf.Len() != 0 {
+			t.Fatalf("test %d: %d unread bytes", i, buf.Len())
+		}
+		if compressed := pr.mh.packetOptions&poCompressed != 0; compressed != d.compressed {
+			t.Fatalf("test %d: compressed %t - expected %t", i, compressed, d.compressed)
+		}
+		if d.compressed && size >= len(d.cmd) {
+			t.Fatalf("test %d: message size %d - expected less than %d", i, size, len(d.cmd))
+		}
+		if !bytes.Equal(cmd, d.cmd) {
+			t.Fatalf("test %d: command %s - expected %s", i, cmd, d.cmd)
+		}
+	}
+}
+
+func TestCompressedMessageLength(t *testing.T) {
+	const compressionVarPartLengthOfs = 24 // offset of compressionVarPartLength in message header
+
+	buf := new(bytes.Buffer)
+	wr := bufio.NewWriter(buf)
+	pw := newProtocolWriter(wr)
+	pw.compress = true
+	pr := newProtocolReader(true, buf)
+
+	if err := pw.write(1, mtExecuteDirect, false, command("select "+strings.Repeat("'abcdefgh', ", 1000)+"1 from dummy")); err != nil {
+		t.Fatal(err)
+	}
+	// uncompressed varpart length exceeding the maximal decompressed size of the compressed varpart
+	binary.LittleEndian.PutUint32(buf.Bytes()[compressionVarPartLengthOfs:], maxMessageSize)
+
+	if err := pr.iterateParts(func(ph *partHeader) {}); err == nil || !strings.Contains(err.Error(), "invalid uncompressed varpart length") {
+		t.Fatalf("invalid uncompressed varpart length error expected - got %v", err)
+	}
+}
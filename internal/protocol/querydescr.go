@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/SAP/go-hdb/internal/protocol/scanner"
 )
@@ -79,16 +81,25 @@ func encodeID(id uint64) string {
 
 var errInvalidCmdToken = errors.New("invalid command token")
 
+// ErrMixedParameters is returned if named (:name) and positional (?) parameters are mixed in a query.
+var ErrMixedParameters = errors.New("named and positional parameters must not be mixed")
+
+// sqlScriptKeywords are the keywords of statements which might contain SQLScript variables (:name)
+// instead of named parameters.
+var sqlScriptKeywords = map[string]bool{"create": true, "alter": true, "do": true}
+
 const (
 	bulkQuery = "bulk"
 )
 
 // QueryDescr represents a query descriptor of a database statement.
 type QueryDescr struct {
-	query  string
-	kind   QueryKind
-	isBulk bool
-	id     uint64
+	query    string
+	kind     QueryKind
+	isBulk   bool
+	id       uint64
+	prmQuery string   // query with named parameters replaced by positional parameters
+	names    []string // named parameters in order of occurrence
 }
 
 func (d *QueryDescr) String() string {
@@ -107,6 +118,19 @@ func (d *QueryDescr) ID() uint64 { return d.id }
 // IsBulk returns true if the query is a bulk statement..
 func (d *QueryDescr) IsBulk() bool { return d.isBulk }
 
+// PrepareQuery returns the query statement to be prepared, where named parameters (:name)
+// are replaced by positional parameters (?).
+func (d *QueryDescr) PrepareQuery() string {
+	if d.names == nil {
+		return d.query
+	}
+	return d.prmQuery
+}
+
+// NamedParameters returns the names of the named parameters of a query descriptor in order of
+// their occurrence (a name occurs multiple times if the parameter is used multiple times).
+func (d *QueryDescr) NamedParameters() []string { return d.names }
+
 // NewQueryDescr returns a new QueryDescr instance.
 func NewQueryDescr(query string, sc *scanner.Scanner) (*QueryDescr, error) {
 	d := &QueryDescr{query: query}
@@ -150,7 +174,45 @@ func NewQueryDescr(query string, sc *scanner.Scanner) (*QueryDescr, error) {
 		}
 	}
 
-	// TODO release v1.0.0 - scan variables (named parameters)
+	// named parameters
+	if d.kind != QkID && !sqlScriptKeywords[keyword] {
+		if err := d.scanNamedParameters(sc, query, start); err != nil {
+			return nil, err
+		}
+	}
 
 	return d, nil
 }
+
+// scanNamedParameters scans the query for named parameters starting at ofs.
+func (d *QueryDescr) scanNamedParameters(sc *scanner.Scanner, query string, ofs int) error {
+	b := strings.Builder{}
+	positional := false
+	last := ofs
+	for {
+		token, start, end := sc.Next()
+		switch token {
+		case scanner.EOS:
+			if d.names == nil {
+				return nil
+			}
+			if positional {
+				return ErrMixedParameters
+			}
+			b.WriteString(query[last:])
+			d.prmQuery = b.String()
+			return nil
+		case scanner.Variable, scanner.PosVariable:
+			positional = true
+		case scanner.NamedVariable:
+			name := query[start+1 : end]
+			if r, _ := utf8.DecodeRuneInString(name); r != '_' && !unicode.IsLetter(r) { // no parameter (e.g. single colon)
+				continue
+			}
+			b.WriteString(query[last:start])
+			b.WriteByte('?')
+			last = end
+			d.names = append(d.names, name)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/SAP/go-hdb/internal/protocol/scanner"
+)
+
+func TestQueryDescrNamedParameters(t *testing.T) {
+	tests := []struct {
+		query        string
+		prepareQuery string
+		names        []string
+		err          error
+	}{
+		{"select * from t where a = ?", "select * from t where a = ?", nil, nil},
+		{"insert into t values (:id, :name)", "insert into t values (?, ?)", []string{"id", "name"}, nil},
+		{"bulk insert into t values (:id)", "insert into t values (?)", []string{"id"}, nil},
+		{"select * from t where a = :a or b = :a", "select * from t where a = ? or b = ?", []string{"a", "a"}, nil},
+		{"select ':a' from t where a = :a", "select ':a' from t where a = ?", []string{"a"}, nil},
+		{"select * from t where a = :1", "select * from t where a = :1", nil, nil},
+		{"create procedure p (in i integer) as begin select :i from dummy; end", "create procedure p (in i integer) as begin select :i from dummy; end", nil, nil},
+		{"select * from t where a = ? and b = :b", "", nil, ErrMixedParameters},
+		{"select * from t where a = :1 and b = :b", "", nil, ErrMixedParameters},
+	}
+
+	sc := &scanner.Scanner{}
+	for _, test := range tests {
+		d, err := NewQueryDescr(test.query, sc)
+		if err != test.err {
+			t.Fatalf("query %s: error %v - expected %v", test.query, err, test.err)
+		}
+		if err != nil {
+			continue
+		}
+		if d.PrepareQuery() != test.prepareQuery {
+			t.Fatalf("query %s: prepare query %s - expected %s", test.query, d.PrepareQuery(), test.prepareQuery)
+		}
+		if !reflect.DeepEqual(d.NamedParameters(), test.names) {
+			t.Fatalf("query %s: named parameters %v - expected %v", test.query, d.NamedParameters(), test.names)
+		}
+	}
+}
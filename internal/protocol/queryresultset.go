@@ -17,10 +17,13 @@ limitations under the License.
 package protocol
 
 import (
+	"context"
 	"database/sql/driver"
 	"io"
 	"reflect"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 /*
@@ -40,12 +43,17 @@ var (
 
 var noColumns = []string{}
 
-type noResultType struct{}
+type noResultType struct {
+	rowsAffected int64
+}
 
 func (r *noResultType) Columns() []string              { return noColumns }
 func (r *noResultType) Close() error                   { return nil }
 func (r *noResultType) Next(dest []driver.Value) error { return io.EOF }
 
+// RowsAffected returns the number of rows affected by the statement.
+func (r *noResultType) RowsAffected() int64 { return r.rowsAffected }
+
 // query result set
 
 //  check if queryResult implements all required interfaces
@@ -60,19 +68,50 @@ var (
 )
 
 type queryResultSet struct {
-	s       *Session
-	rrs     []rowsResult
-	rr      rowsResult
-	idx     int // current result set
-	pos     int
-	lastErr error
+	s         *Session
+	rrs       []rowsResult
+	rr        rowsResult
+	idx       int // current result set
+	pos       int
+	lastErr   error
+	loc       *time.Location // return location of time values (nil: UTC)
+	dfMode    DecimalFloatMode
+	fetchSize int // fetch size of FETCH requests (0: session configuration)
+
+	rowsAffected int64 // rows affected by the statement returning the result set
+
+	fetchProgress FetchProgressFunc
+	fetched       int64 // number of rows fetched (fetch progress)
+	reported      bool  // current chunk reported (fetch progress)
+
+	closed int32 // result set closed (invalidates lazy lob readers)
+
+	ctx context.Context // context of the query execution (see Session.SetContext)
 }
 
 func newQueryResultSet(s *Session, rrs ...rowsResult) *queryResultSet {
 	if len(rrs) == 0 {
 		panic("query result set is empty")
 	}
-	return &queryResultSet{s: s, rrs: rrs, rr: rrs[0]}
+	return &queryResultSet{s: s, rrs: rrs, rr: rrs[0], loc: s.cfg.ReturnLocation(), dfMode: s.cfg.DecimalFloatMode(), fetchProgress: s.cfg.FetchProgress(), ctx: s.ctx}
+}
+
+// FetchProgressFunc is called for each chunk of result set rows read from the database
+// with the number of rows fetched so far and if the chunk is the last chunk of the result set.
+type FetchProgressFunc func(fetched int64, lastPacket bool)
+
+/*
+reportFetch reports the rows of the current chunk to the fetch progress function.
+Chunks are reported while iterating the result set only: the rows returned with the query
+are reported by the first call of Next.
+*/
+func (r *queryResultSet) reportFetch() {
+	r.reported = true
+	if r.fetchProgress == nil {
+		return
+	}
+	r.fetched += int64(r.rr.numRow())
+	r.fetchProgress(r.fetched, r.rr.lastPacket())
 }
 
 func (r *queryResultSet) Columns() []string {
@@ -80,13 +119,31 @@ func (r *queryResultSet) Columns() []string {
 }
 
 func (r *queryResultSet) Close() error {
+	atomic.StoreInt32(&r.closed, 1)
+
 	// if lastError is set, attrs are nil
 	if r.lastErr != nil {
 		return r.lastErr
 	}
 
-	if !r.rr.closed() {
-		return r.s.CloseResultsetID(r.rr.rsID())
+	for _, rr := range r.rrs {
+		if err := r.closeResult(rr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// closeResult releases the database result set of rr if not closed yet.
+func (r *queryResultSet) closeResult(rr rowsResult) error {
+	if rr.closed() || rr.rsID() == 0 {
+		return nil
+	}
+	if err := r.s.CloseResultsetID(rr.rsID()); err != nil {
+		return err
+	}
+	if qr, ok := rr.(*queryResult); ok { // table output parameters are shared by call result fields and result sets
+		qr.attributes |= paResultsetClosed
 	}
 	return nil
 }
@@ -96,14 +153,19 @@ func (r *queryResultSet) Next(dest []driver.Value) error {
 		return driver.ErrBadConn
 	}
 
+	if !r.reported {
+		r.reportFetch()
+	}
+
 	if r.pos >= r.rr.numRow() {
 		if r.rr.lastPacket() {
 			return io.EOF
 		}
-		if err := r.s.fetchNext(r.rr); err != nil {
+		if err := r.s.fetchNext(r.ctx, r.rr, r.fetchSize); err != nil {
 			r.lastErr = err //fieldValues and attrs are nil
 			return err
 		}
+		r.reportFetch()
 		if r.rr.numRow() == 0 {
 			return io.EOF
 		}
@@ -113,19 +175,60 @@ func (r *queryResultSet) Next(dest []driver.Value) error {
 	r.rr.copyRow(r.pos, dest)
 	r.pos++
 
+	if r.loc != nil {
+		r.convertLocation(dest)
+	}
+	switch r.dfMode {
+	case DecimalFloatRound:
+		r.convertDecimalFloat(dest)
+	case DecimalString:
+		r.convertDecimalString(dest)
+	}
+
 	// TODO eliminate
 	for _, v := range dest {
 		if v, ok := v.(sessionSetter); ok {
 			v.setSession(r.s)
 		}
+		if v, ok := v.(resultSetSetter); ok {
+			v.setResultSet(r)
+		}
 	}
 	return nil
 }
 
+/*
+convertLocation converts time values into the return location:
+- date and time types representing a wall clock (DATE, TIME, DAYDATE, SECONDTIME) keep
+  their wall clock and get the location attached
+- timestamp types (TIMESTAMP, LONGDATE, SECONDDATE) are stored in UTC by the driver
+  and are converted to the same instant in the return location
+*/
+func (r *queryResultSet) convertLocation(dest []driver.Value) {
+	for i, v := range dest {
+		t, ok := v.(time.Time)
+		if !ok {
+			continue
+		}
+		if r.rr.field(i).typeCode().isWallClock() {
+			dest[i] = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), r.loc)
+		} else {
+			dest[i] = t.In(r.loc)
+		}
+	}
+}
+
 func (r *queryResultSet) HasNextResultSet() bool {
 	return (r.idx + 1) < len(r.rrs)
 }
 
+// RowsAffected returns the number of rows affected by the statement (e.g. MERGE or UPSERT
+// statements returning a result set).
+func (r *queryResultSet) RowsAffected() int64 { return r.rowsAffected }
+
+// SetFetchSize sets the fetch size of the FETCH requests of the result set overriding the session configuration.
+func (r *queryResultSet) SetFetchSize(fetchSize int) { r.fetchSize = fetchSize }
+
 func (r *queryResultSet) NextResultSet() error {
 	if !r.HasNextResultSet() {
 		return io.EOF
@@ -133,17 +236,56 @@ func (r *queryResultSet) NextResultSet() error {
 	r.lastErr = nil
 	r.idx++
 	r.rr = r.rrs[r.idx]
+	r.pos = 0
+	r.fetched = 0
+	r.reported = false
 	return nil
 }
 
+/*
+ResultSetFields returns the fields of all result sets available after the execution:
+- for procedure calls the fields of the table output parameters
+- for queries the fields of the result sets
+*/
+func (r *queryResultSet) ResultSetFields() [][]Field {
+	var fields [][]Field
+	for _, rr := range r.rrs {
+		switch rr := rr.(type) {
+		case *callResult:
+			for _, qr := range rr.qrs {
+				fields = append(fields, qr.resultFields())
+			}
+			return fields // table output parameters are provided as additional result sets as well
+		case *queryResult:
+			fields = append(fields, rr.resultFields())
+		}
+	}
+	return fields
+}
+
 func (r *queryResultSet) ColumnTypeDatabaseTypeName(idx int) string {
 	return r.rr.field(idx).TypeName()
 }
 
+// ColumnTypeLength returns the type length of a variable length column. The length is measured
+// in characters for unicode character types (NVARCHAR, NCHAR, SHORTTEXT, ...) and in bytes for
+// all other types (see ColumnTypeLengthSemantics).
 func (r *queryResultSet) ColumnTypeLength(idx int) (int64, bool) {
 	return r.rr.field(idx).TypeLength()
 }
 
+// ColumnTypeLengthSemantics returns the type length of a variable length column and if the length
+// is measured in characters (chars == true) or bytes (see LengthInChars).
+// For columns without type length the returned length is zero.
+func (r *queryResultSet) ColumnTypeLengthSemantics(idx int) (chars bool, length int64) {
+	f := r.rr.field(idx)
+	length, ok := f.TypeLength()
+	if !ok {
+		return false, 0
+	}
+	return LengthInChars(f), length
+}
+
 func (r *queryResultSet) ColumnTypePrecisionScale(idx int) (int64, int64, bool) {
 	return r.rr.field(idx).TypePrecisionScale()
 }
@@ -153,7 +295,16 @@ func (r *queryResultSet) ColumnTypeNullable(idx int) (bool, bool) {
 }
 
 func (r *queryResultSet) ColumnTypeScanType(idx int) reflect.Type {
-	return scanTypeMap[r.rr.field(idx).ScanType()]
+	f := r.rr.field(idx)
+	if f.typeCode().isDecimalType() {
+		switch r.dfMode {
+		case DecimalFloatRound:
+			return float64ReflectType
+		case DecimalString:
+			return stringReflectType
+		}
+	}
+	return scanTypeMap[f.ScanType()]
 }
 
 // QrsCache is a query result cache supporting reading
@@ -92,6 +92,7 @@ func (f *resultField) String() string {
 }
 
 func (f *resultField) Converter() Converter { return f.tc.fieldType() }
+func (f *resultField) typeCode() typeCode   { return f.tc }
 
 // TypeName returns the type name of the field.
 // see https://golang.org/pkg/database/sql/driver/#RowsColumnTypeDatabaseTypeName
@@ -179,8 +180,10 @@ func (r *resultMetadata) decode(dec *encoding.Decoder, ph *partHeader) error {
 
 //resultset
 type resultset struct {
-	resultFields []*resultField
-	fieldValues  []driver.Value
+	resultFields    []*resultField
+	fieldValues     []driver.Value
+	rawCESU8        bool // decode unicode character data as undecoded CESU-8 bytes
+	alphanumPadding bool // decode numeric alphanum values padded with leading zeros to the field size
 }
 
 func (r *resultset) String() string {
@@ -195,7 +198,15 @@ func (r *resultset) decode(dec *encoding.Decoder, ph *partHeader) error {
 	for i := 0; i < numArg; i++ {
 		for j, field := range r.resultFields {
 			var err error
-			if r.fieldValues[i*cols+j], err = decodeRes(dec, field.tc); err != nil {
+			switch ft := field.tc.fieldType(); {
+			case r.rawCESU8 && ft == cesu8Type:
+				r.fieldValues[i*cols+j], err = decodeRawCESU8(dec)
+			case r.alphanumPadding && ft == alphaType:
+				r.fieldValues[i*cols+j], err = decodeAlphanumPadded(dec)
+			default:
+				r.fieldValues[i*cols+j], err = decodeRes(dec, field.tc, int(field.fraction))
+			}
+			if err != nil {
 				return err
 			}
 		}
@@ -17,6 +17,7 @@ limitations under the License.
 package protocol
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/SAP/go-hdb/internal/protocol/encoding"
@@ -65,3 +66,17 @@ func (r rowsAffected) total() int64 {
 	}
 	return total
 }
+
+// ErrLastInsertIDNotSupported is returned by ExecResult.LastInsertId as the database does not report
+// the ids of inserted rows. The last value of an identity column can be selected by CURRENT_IDENTITY_VALUE().
+var ErrLastInsertIDNotSupported = errors.New("LastInsertId is not supported - use select current_identity_value() from dummy to get the last identity value")
+
+// ExecResult is the result of a statement execution reporting the number of affected rows
+// (summed up over all rows of a bulk execution).
+type ExecResult int64
+
+// LastInsertId implements the driver.Result interface.
+func (r ExecResult) LastInsertId() (int64, error) { return 0, ErrLastInsertIDNotSupported }
+
+// RowsAffected implements the driver.Result interface.
+func (r ExecResult) RowsAffected() (int64, error) { return int64(r), nil }
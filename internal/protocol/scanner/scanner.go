@@ -53,6 +53,7 @@ const (
 	NamedVariable
 	String
 	Number
+	Comment
 )
 
 var tokenString = map[Token]string{
@@ -69,6 +70,7 @@ var tokenString = map[Token]string{
 	NamedVariable:       "NamedVariable",
 	String:              "String",
 	Number:              "Number",
+	Comment:             "Comment",
 }
 
 func (t Token) String() string {
@@ -213,6 +215,41 @@ func (sc *Scanner) scanVariable() Token {
 	return NamedVariable
 }
 
+// scanComment scans a line comment (-- up to the end of the line) or a block comment (/* up to */)
+// starting with ch and returns false if ch does not start a comment.
+func (sc *Scanner) scanComment(ch rune) bool {
+	if ch != '-' && ch != '/' {
+		return false
+	}
+	ch2, ok := sc.readRune()
+	if !ok {
+		return false
+	}
+	switch {
+	case ch == '-' && ch2 == '-':
+		for {
+			ch, ok := sc.readRune()
+			if !ok {
+				return true
+			}
+			if ch == '\n' {
+				sc.unreadRune()
+				return true
+			}
+		}
+	case ch == '/' && ch2 == '*':
+		for prev := rune(0); ; {
+			ch, ok := sc.readRune()
+			if !ok || (prev == '*' && ch == '/') {
+				return true
+			}
+			prev = ch
+		}
+	}
+	sc.unreadRune()
+	return false
+}
+
 func (sc *Scanner) scanNumber() Token {
 	sc.scanNumeric()
 	ch, ok := sc.readRune()
@@ -221,18 +258,18 @@ func (sc *Scanner) scanNumber() Token {
 	}
 	if isDecimalSeparator(ch) {
 		sc.scanNumeric()
+		if ch, ok = sc.readRune(); !ok {
+			return Number
+		}
 	}
-	ch, ok = sc.readRune()
-	if !ok {
+	if !isExp(ch) {
+		sc.unreadRune()
 		return Number
 	}
-	if isExp(ch) {
-		if isNumber(ch) {
-			sc.scanNumeric()
-			return Number
-		}
+	if ch, ok = sc.readRune(); !ok || !isNumber(ch) {
 		return Error
 	}
+	sc.scanNumeric()
 	return Number
 }
 
@@ -251,6 +288,8 @@ func (sc *Scanner) Next() (Token, int, int) {
 	default:
 		return Error, start, sc.i
 
+	case sc.scanComment(ch):
+		return Comment, start, sc.i
 	case isDelimiter(ch):
 		return Delimiter, start, sc.i
 	case isNameDelimiter(ch):
@@ -125,6 +125,31 @@ var testData = []struct {
 			{Error, `" >= :start;`},
 		},
 	},
+	{
+		// numbers
+		`select 1.5e3, 2 from dummy`,
+		[]tokenValue{
+			{Identifier, "select"},
+			{Number, "1.5e3"},
+			{Delimiter, ","},
+			{Number, "2"},
+			{Identifier, "from"},
+			{Identifier, "dummy"},
+		},
+	},
+	{
+		// comments
+		"select -1 /* c1 */ from dummy -- c2\n-- c3",
+		[]tokenValue{
+			{Identifier, "select"},
+			{Number, "-1"},
+			{Comment, "/* c1 */"},
+			{Identifier, "from"},
+			{Identifier, "dummy"},
+			{Comment, "-- c2"},
+			{Comment, "-- c3"},
+		},
+	},
 	{
 		// call table result query
 		`rsid 1234567890`,
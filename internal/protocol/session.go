@@ -25,7 +25,9 @@ import (
 	"github.com/SAP/go-hdb/proxy"
 	"io"
 	"net"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/text/transform"
@@ -59,10 +61,13 @@ type sessionConn interface {
 	sessionStatus
 }
 
-func newSessionConn(ctx context.Context, addr string, timeoutSec int, tlsConfig *tls.Config, proxyConfig *proxy.Config) (sessionConn, error) {
+// dialContextFunc is the function signature of a custom dialer (see net.Dialer DialContext).
+type dialContextFunc = func(ctx context.Context, network, address string) (net.Conn, error)
+
+func newSessionConn(ctx context.Context, addr string, timeoutSec int, tlsConfig *tls.Config, proxyConfig *proxy.Config, dialContext dialContextFunc) (sessionConn, error) {
 	// session recording
 	if wr, ok := ctx.Value(sesRecording).(io.Writer); ok {
-		conn, err := newDbConn(ctx, addr, timeoutSec, tlsConfig, proxyConfig)
+		conn, err := newDbConn(ctx, addr, timeoutSec, tlsConfig, proxyConfig, dialContext)
 		if err != nil {
 			return nil, err
 		}
@@ -83,7 +88,7 @@ func newSessionConn(ctx context.Context, addr string, timeoutSec int, tlsConfig
 			sessionStatus: nwc,
 		}, nil
 	}
-	return newDbConn(ctx, addr, timeoutSec, tlsConfig, proxyConfig)
+	return newDbConn(ctx, addr, timeoutSec, tlsConfig, proxyConfig, dialContext)
 }
 
 type nullWriterCloser struct{}
@@ -100,6 +105,24 @@ type proxyConn struct {
 	sessionStatus
 }
 
+// countingConn counts the bytes read from and written to the session connection.
+type countingConn struct {
+	sessionConn
+	bytesRead, bytesWritten uint64 // atomic access
+}
+
+func (c *countingConn) Read(b []byte) (int, error) {
+	n, err := c.sessionConn.Read(b)
+	atomic.AddUint64(&c.bytesRead, uint64(n))
+	return n, err
+}
+
+func (c *countingConn) Write(b []byte) (int, error) {
+	n, err := c.sessionConn.Write(b)
+	atomic.AddUint64(&c.bytesWritten, uint64(n))
+	return n, err
+}
+
 // dbConn wraps the database tcp connection. It sets timeouts and handles driver ErrBadConn behavior.
 type dbConn struct {
 	addr      string
@@ -108,14 +131,23 @@ type dbConn struct {
 	lastError error // error bad connection
 }
 
-func newDbConn(ctx context.Context, addr string, timeoutSec int, tlsConfig *tls.Config, proxyConfig *proxy.Config) (*dbConn, error) {
+func newDbConn(ctx context.Context, addr string, timeoutSec int, tlsConfig *tls.Config, proxyConfig *proxy.Config, dialContext dialContextFunc) (*dbConn, error) {
 	var conn net.Conn
 	var err error
 	timeout := time.Duration(timeoutSec) * time.Second
 
-	if proxyConfig == nil {
+	switch {
+	case dialContext != nil: // custom dialer bypasses the built-in proxy dialer
+		if timeout > 0 {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			conn, err = dialContext(ctx, "tcp", addr)
+			cancel()
+		} else {
+			conn, err = dialContext(ctx, "tcp", addr)
+		}
+	case proxyConfig == nil:
 		conn, err = (&net.Dialer{Timeout: timeout}).DialContext(ctx, "tcp", addr)
-	} else {
+	default:
 		d := proxy.NewDialer(proxyConfig)
 		if timeout > 0 {
 			ctx, cancel := context.WithTimeout(ctx, timeout)
@@ -131,6 +163,12 @@ func newDbConn(ctx context.Context, addr string, timeoutSec int, tlsConfig *tls.
 
 	// is TLS connection requested?
 	if tlsConfig != nil {
+		if tlsConfig.ServerName == "" && !tlsConfig.InsecureSkipVerify { // verify the certificate for the dialed host
+			if host, _, err := net.SplitHostPort(addr); err == nil {
+				tlsConfig = tlsConfig.Clone()
+				tlsConfig.ServerName = host
+			}
+		}
 		conn = tls.Client(conn, tlsConfig)
 	}
 
@@ -182,6 +220,13 @@ type SessionConfig interface {
 	Host() string
 	Username() string
 	Password() string
+	Credentials(ctx context.Context) (username, password string, err error)
+	DatabaseName() string
+	Token() string
+	SAMLAssertion() []byte
+	SessionCookie() (username string, cookie []byte)
+	ClientCert() *tls.Certificate
+	AllowedAuthMethods() []string
 	Locale() string
 	BufferSize() int
 	FetchSize() int
@@ -192,21 +237,56 @@ type SessionConfig interface {
 	TLSConfig() *tls.Config
 	Legacy() bool
 	Proxy() *proxy.Config
+	DialContext() func(ctx context.Context, network, address string) (net.Conn, error)
+	ReturnLocation() *time.Location
+	DecimalFloatMode() DecimalFloatMode
+	FetchProgress() FetchProgressFunc
+	WarningHandler() func(warning error)
+	SmallResultThreshold() int
+	AutoLobTransaction() bool
+	PiggybackCommit() bool
+	StmtCacheSize() int
+	Tracer() TraceFunc
+	Compression() bool
 }
 
 const dfvLevel1 = 1
 
 const defaultSessionID = -1
 
+/*
+packetSize is the size of request messages the session aims for (1 MiB, the default packet size of
+SAP HANA clients). Requests are not limited to the packet size, but it is used to derive defaults
+like the number of bulk executions per request (see Session.PacketSize).
+*/
+const packetSize = 1 << 20
+
+/*
+MaxLobChunkSize is the maximal lob chunk size, so that a lob chunk fits into one packet: a chunk of n
+characters of a character lob is transferred in up to 3*n CESU-8 bytes (characters outside the BMP count
+as two characters), and lobChunkReserve bytes are reserved for message, segment and part headers.
+*/
+const MaxLobChunkSize = (packetSize - lobChunkReserve) / 3
+
+const lobChunkReserve = 1 << 10
+
 // Session represents a HDB session.
 type Session struct {
 	cfg SessionConfig
 
-	sessionID int64
+	sessionID    int64
+	connectionID int64 // database connection id (used to cancel requests)
+
+	creds         credentials // credentials the session is authenticated with
+	sessionCookie []byte      // session cookie granted by the database (SAML authentication)
 
-	conn sessionConn
-	rd   *bufio.Reader
-	wr   *bufio.Writer
+	serverOptions connectOptions // connect options negotiated with the server
+
+	conn   *countingConn
+	killed int32 // connection closed to abort a running request
+	closed int32 // session closed (invalidates lazy lob readers)
+	rd     *bufio.Reader
+	wr     *bufio.Writer
 
 	pr *protocolReader
 	pw *protocolWriter
@@ -217,16 +297,92 @@ type Session struct {
 
 	inTx bool // in transaction
 
+	stmtCache *stmtCache // prepared statement cache (see SessionConfig.StmtCacheSize)
+
+	numFetch, numLobChunk uint64 // atomic access
+
+	tracer TraceFunc       // called for database operations (nil: no tracing)
+	ctx    context.Context // context of the current statement (see SetContext)
+
+	rawCESU8        bool // fetch unicode character data of query results as CESU-8 bytes (see SetRawCESU8)
+	alphanumPadding bool // fetch numeric alphanum values of query results in stored form (see SetAlphanumPadding)
+}
+
+// credentials are the credentials a session is authenticated with.
+type credentials struct {
+	username, password string
+	token              string // JSON Web Token (JWT authentication)
+	assertion          []byte // SAML assertion (SAML authentication)
+	cookie             []byte // session cookie of username granted on a previous SAML authentication
 }
 
 // NewSession creates a new database session.
 func NewSession(ctx context.Context, cfg SessionConfig) (*Session, error) {
-	var conn sessionConn
+	if token := cfg.Token(); token != "" {
+		return newSession(ctx, cfg, credentials{token: token})
+	}
+	if assertion := cfg.SAMLAssertion(); assertion != nil {
+		return newSAMLSession(ctx, cfg, assertion)
+	}
+	username, password, err := cfg.Credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newSession(ctx, cfg, credentials{username: username, password: password})
+}
 
-	conn, err := newSessionConn(ctx, cfg.Host(), cfg.Timeout(), cfg.TLSConfig(), cfg.Proxy())
+/*
+newSAMLSession creates a session authenticated via the session cookie of a previous SAML authentication
+as long as the database accepts the cookie and via the SAML assertion otherwise (e.g. cookie expired).
+*/
+func newSAMLSession(ctx context.Context, cfg SessionConfig, assertion []byte) (*Session, error) {
+	if username, cookie := cfg.SessionCookie(); cookie != nil {
+		s, err := newSession(ctx, cfg, credentials{username: username, cookie: cookie})
+		if err == nil {
+			return s, nil
+		}
+		if _, ok := err.(*hdbErrors); !ok { // no authentication error
+			return nil, err
+		}
+	}
+	s, err := newSession(ctx, cfg, credentials{assertion: assertion})
 	if err != nil {
+		if _, ok := err.(*hdbErrors); ok {
+			return nil, fmt.Errorf("SAML authentication: assertion rejected by the database: %w", err)
+		}
 		return nil, err
 	}
+	return s, nil
+}
+
+func newSession(ctx context.Context, cfg SessionConfig, creds credentials) (*Session, error) {
+	s, err := openSession(ctx, cfg, cfg.Host(), creds)
+	if err != nil {
+		return nil, err
+	}
+	if databaseName := cfg.DatabaseName(); databaseName != "" {
+		ci, err := s.dbConnectInfo(databaseName)
+		if err != nil {
+			s.conn.Close()
+			return nil, err
+		}
+		if !ci.isConnected() { // redirect to tenant database
+			s.conn.Close()
+			if s, err = openSession(ctx, cfg, net.JoinHostPort(ci.host(), strconv.Itoa(ci.port())), creds); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return s, s.authenticate()
+}
+
+// openSession opens a not authenticated session to host.
+func openSession(ctx context.Context, cfg SessionConfig, host string, creds credentials) (*Session, error) {
+	sc, err := newSessionConn(ctx, host, cfg.Timeout(), cfg.TLSConfig(), cfg.Proxy(), cfg.DialContext())
+	if err != nil {
+		return nil, err
+	}
+	conn := &countingConn{sessionConn: sc}
 
 	var bufRd *bufio.Reader
 	var bufWr *bufio.Writer
@@ -249,17 +405,38 @@ func NewSession(ctx context.Context, cfg SessionConfig) (*Session, error) {
 	if err := pr.readProlog(); err != nil {
 		return nil, err
 	}
+	pr.warningHandler = cfg.WarningHandler()
 
 	s := &Session{
 		cfg:       cfg,
 		sessionID: defaultSessionID,
+		creds:     creds,
 		conn:      conn,
 		rd:        bufRd,
 		wr:        bufWr,
 		pr:        pr,
 		pw:        pw,
+		stmtCache: newStmtCache(cfg.StmtCacheSize()),
+		tracer:    cfg.Tracer(),
+		ctx:       context.Background(),
 	}
-	return s, s.authenticate()
+	return s, nil
+}
+
+// dbConnectInfo requests the connect information of the tenant database databaseName.
+func (s *Session) dbConnectInfo(databaseName string) (dbConnectInfo, error) {
+	ci := dbConnectInfo{connectOption(ciDatabaseName): optStringType(databaseName)}
+	if err := s.pw.write(s.sessionID, mtDBConnectInfo, false, ci); err != nil {
+		return nil, err
+	}
+	if err := s.pr.iterateParts(func(ph *partHeader) {
+		if ph.partKind == pkDBConnectInfo {
+			s.pr.read(&ci)
+		}
+	}); err != nil {
+		return nil, err
+	}
+	return ci, nil
 }
 
 // Reset resets the session.
@@ -269,6 +446,7 @@ func (s *Session) Reset() {
 
 // Close closes the session.
 func (s *Session) Close() error {
+	atomic.StoreInt32(&s.closed, 1)
 	QrsCache.cleanup(s)
 	return s.conn.Close()
 }
@@ -283,11 +461,118 @@ func (s *Session) SetInTx(v bool) {
 	s.inTx = v
 }
 
+// SetWarningHandler sets the handler called for database warnings and returns the previous handler.
+func (s *Session) SetWarningHandler(h func(warning error)) func(warning error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev := s.pr.warningHandler
+	s.pr.warningHandler = h
+	return prev
+}
+
+/*
+SetRawCESU8 sets if unicode character data of subsequent query results is fetched as undecoded
+CESU-8 bytes instead of UTF-8 and returns the previous setting.
+The setting is kept by the query result for subsequent FETCH requests.
+*/
+func (s *Session) SetRawCESU8(raw bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev := s.rawCESU8
+	s.rawCESU8 = raw
+	return prev
+}
+
+/*
+SetAlphanumPadding sets if purely numeric alphanum values of subsequent query results are padded with
+leading zeros to the field size (stored form) independent of the data format version and returns the previous setting.
+The setting is kept by the query result for subsequent FETCH requests.
+*/
+func (s *Session) SetAlphanumPadding(padding bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev := s.alphanumPadding
+	s.alphanumPadding = padding
+	return prev
+}
+
 // IsBad indicates, that the session is in bad state.
 func (s *Session) IsBad() bool {
-	return s.conn.isBad()
+	return atomic.LoadInt32(&s.killed) != 0 || s.conn.isBad()
+}
+
+// ConnectionID returns the database connection id of the session.
+func (s *Session) ConnectionID() int64 {
+	return s.connectionID
+}
+
+// Kill aborts a running request by closing the database connection.
+// The session is in bad state afterwards.
+func (s *Session) Kill() {
+	if atomic.CompareAndSwapInt32(&s.killed, 0, 1) {
+		s.conn.Close()
+	}
+}
+
+/*
+Cancel requests the database to cancel the currently running statement of the session.
+As the session itself is blocked by the running request the cancellation is sent via
+a separate, short-lived session which might need the SESSION ADMIN privilege.
+*/
+func (s *Session) Cancel() error {
+	cs, err := newSession(context.Background(), s.cfg, s.creds)
+	if err != nil {
+		return err
+	}
+	defer cs.Close()
+	_, err = cs.ExecDirect(fmt.Sprintf("alter system cancel session '%d'", s.connectionID))
+	return err
+}
+
+/*
+SessionCookie returns the session cookie granted by the database on SAML authentication and the database
+user the session is authenticated for. The cookie is nil for sessions not authenticated via SAML.
+*/
+func (s *Session) SessionCookie() (username string, cookie []byte) {
+	return s.creds.username, s.sessionCookie
+}
+
+// BytesRead returns the number of bytes read from the database connection.
+func (s *Session) BytesRead() uint64 {
+	return atomic.LoadUint64(&s.conn.bytesRead)
+}
+
+// BytesWritten returns the number of bytes written to the database connection.
+func (s *Session) BytesWritten() uint64 {
+	return atomic.LoadUint64(&s.conn.bytesWritten)
 }
 
+// Compressed returns true if the messages of the session are sent compressed.
+func (s *Session) Compressed() bool { return s.pw.compress }
+
+// NumRequest returns the number of requests (round trips) sent to the database.
+func (s *Session) NumRequest() uint64 {
+	return atomic.LoadUint64(&s.pw.numRequest)
+}
+
+// NumFetch returns the number of FETCH requests of result set rows.
+func (s *Session) NumFetch() uint64 {
+	return atomic.LoadUint64(&s.numFetch)
+}
+
+// NumLobChunk returns the number of lob chunk requests (read and write).
+func (s *Session) NumLobChunk() uint64 {
+	return atomic.LoadUint64(&s.numLobChunk)
+}
+
+// NumCachedStmt returns the number of statements of the statement cache.
+func (s *Session) NumCachedStmt() int {
+	return s.stmtCache.len()
+}
+
+// PacketSize returns the packet size of the session.
+func (s *Session) PacketSize() int { return packetSize }
+
 // MaxBulkNum returns the maximal number of bulk calls before auto flush.
 func (s *Session) MaxBulkNum() int {
 	maxBulkNum := s.cfg.BulkSize()
@@ -298,13 +583,59 @@ func (s *Session) MaxBulkNum() int {
 }
 
 func (s *Session) authenticate() error {
-	authStepper := newAuth(s.cfg.Username(), s.cfg.Password())
+	allowed := s.cfg.AllowedAuthMethods()
+
+	checkAllowed := func(method string) error {
+		if len(allowedAuthMethods([]string{method}, allowed)) == 0 {
+			return fmt.Errorf("authentication method %s not allowed - allowed methods %v", method, allowed)
+		}
+		return nil
+	}
+
+	var authStepper authStepper
+	switch {
+	case s.creds.token != "":
+		if err := checkAllowed(mnJWT); err != nil {
+			return err
+		}
+		authStepper = newJWTAuth(s.creds.token)
+	case s.creds.cookie != nil:
+		if err := checkAllowed(mnSAML); err != nil {
+			return err
+		}
+		authStepper = newSessionCookieAuth(s.creds.username, s.creds.cookie)
+	case s.creds.assertion != nil:
+		if err := checkAllowed(mnSAML); err != nil {
+			return err
+		}
+		authStepper = newSAMLAuth(s.creds.assertion)
+	case s.cfg.ClientCert() != nil:
+		if err := checkAllowed(mnX509); err != nil {
+			return err
+		}
+		authStepper = newX509Auth(s.cfg.ClientCert())
+	default:
+		methods := allowedAuthMethods(scramMethods, allowed)
+		if len(methods) == 0 {
+			return fmt.Errorf("no password based authentication method allowed - allowed methods %v", allowed)
+		}
+		authStepper = newAuth(s.creds.username, s.creds.password, methods)
+	}
 	if err := s.authenticateMethod(authStepper); err != nil {
 		return err
 	}
+	if a, ok := authStepper.(*tokenAuth); ok && a.method != mnJWT {
+		s.creds.username = a.sessionUsername()
+		s.sessionCookie = a.sessionCookie()
+		if s.sessionCookie == nil { // session cookie still valid
+			s.sessionCookie = s.creds.cookie
+		}
+	}
 	if s.sessionID <= 0 {
 		return fmt.Errorf("invalid session id %d", s.sessionID)
 	}
+	// compress messages only if negotiated (fallback: uncompressed)
+	s.pw.compress = s.cfg.Compression() && s.SupportsCompression()
 	return nil
 }
 
@@ -319,6 +650,9 @@ func (s *Session) connectOptions() connectOptions {
 		co.set(coClientLocale, optStringType(s.cfg.Locale()))
 	}
 	co.set(coClientDistributionMode, cdmOff)
+	if s.cfg.Compression() {
+		co.set(coCompressionLevelAndFlags, optIntType(1))
+	}
 	// co.set(coImplicitLobStreaming, optBooleanType(true))
 	return co
 }
@@ -366,6 +700,10 @@ func (s *Session) authenticateMethod(stepper authStepper) error {
 			// set data format version
 			// TODO generalize for sniffer
 			s.pr.setDfv(int(co[coDataFormatVersion2].(optIntType)))
+			if connectionID, ok := co[coConnectionID].(optIntType); ok {
+				s.connectionID = int64(connectionID)
+			}
+			s.serverOptions = co
 		}
 	}); err != nil {
 		return err
@@ -375,18 +713,21 @@ func (s *Session) authenticateMethod(stepper authStepper) error {
 }
 
 // QueryDirect executes a query without query parameters.
-func (s *Session) QueryDirect(query string) (driver.Rows, error) {
+func (s *Session) QueryDirect(query string) (_ driver.Rows, err error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.trace(s.ctx, TraceQuery, query, 0)(&err)
 
 	// allow e.g inserts as query -> handle commit like in ExecDirect
-	if err := s.pw.write(s.sessionID, mtExecuteDirect, !s.inTx, command(query)); err != nil {
+	if err := s.pw.write(s.sessionID, mtExecuteDirect, !s.inTx, s.queryParts(command(query))...); err != nil {
 		return nil, err
 	}
 
-	qr := &queryResult{}
+	qr := &queryResult{rawCESU8: s.rawCESU8, alphanumPadding: s.alphanumPadding}
 	meta := &resultMetadata{}
-	resSet := &resultset{}
+	resSet := &resultset{rawCESU8: s.rawCESU8, alphanumPadding: s.alphanumPadding}
+	rows := &rowsAffected{}
+	var numRow int64
 
 	if err := s.pr.iterateParts(func(ph *partHeader) {
 		switch ph.partKind {
@@ -400,20 +741,29 @@ func (s *Session) QueryDirect(query string) (driver.Rows, error) {
 			s.pr.read(resSet)
 			qr.fieldValues = resSet.fieldValues
 			qr.attributes = ph.partAttributes
+		case pkRowsAffected:
+			s.pr.read(rows)
+			numRow = rows.total()
 		}
 	}); err != nil {
 		return nil, err
 	}
 	if qr._rsID == 0 { // non select query
-		return noResult, nil
+		if numRow == 0 {
+			return noResult, nil
+		}
+		return &noResultType{rowsAffected: numRow}, nil
 	}
-	return newQueryResultSet(s, qr), nil
+	qrs := newQueryResultSet(s, qr)
+	qrs.rowsAffected = numRow
+	return qrs, nil
 }
 
 // ExecDirect executes a sql statement without statement parameters.
-func (s *Session) ExecDirect(query string) (driver.Result, error) {
+func (s *Session) ExecDirect(query string) (_ driver.Result, err error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.trace(s.ctx, TraceExec, query, 0)(&err)
 
 	if err := s.pw.write(s.sessionID, mtExecuteDirect, !s.inTx, command(query)); err != nil {
 		return nil, err
@@ -421,30 +771,39 @@ func (s *Session) ExecDirect(query string) (driver.Result, error) {
 
 	rows := &rowsAffected{}
 	var numRow int64
+	rs := &unexpectedResultset{}
 	if err := s.pr.iterateParts(func(ph *partHeader) {
-		if ph.partKind == pkRowsAffected {
+		switch ph.partKind {
+		case pkRowsAffected:
 			s.pr.read(rows)
 			numRow = rows.total()
+		case pkResultsetID, pkResultset:
+			rs.handlePart(s.pr, ph)
 		}
 	}); err != nil {
 		return nil, err
 	}
-	if s.pr.functionCode() == fcDDL {
+	fc := s.pr.functionCode()
+	if err := rs.close(s); err != nil {
+		return nil, err
+	}
+	if fc == fcDDL {
 		return driver.ResultNoRows, nil
 	}
-	return driver.RowsAffected(numRow), nil
+	return ExecResult(numRow), nil
 }
 
 // Prepare prepares a sql statement.
-func (s *Session) Prepare(query string) (*PrepareResult, error) {
+func (s *Session) Prepare(query string) (_ *PrepareResult, err error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.trace(s.ctx, TracePrepare, query, 0)(&err)
 
 	if err := s.pw.write(s.sessionID, mtPrepare, false, command(query)); err != nil {
 		return nil, err
 	}
 
-	pr := &PrepareResult{}
+	pr := &PrepareResult{query: query}
 	resMeta := &resultMetadata{}
 	prmMeta := &parameterMetadata{}
 
@@ -467,11 +826,51 @@ func (s *Session) Prepare(query string) (*PrepareResult, error) {
 }
 
 // Exec executes a sql statement.
-func (s *Session) Exec(pr *PrepareResult, args []driver.NamedValue) (driver.Result, error) {
+func (s *Session) Exec(pr *PrepareResult, args []driver.NamedValue) (_ driver.Result, err error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.trace(s.ctx, TraceExec, pr.query, len(args))(&err)
+
+	switch {
+	case s.inTx:
+		return s.exec(pr, args, false)
+	case s.cfg.AutoLobTransaction() && hasLobArgs(pr.prmFields, args):
+		// lob streaming is not permitted in auto-commit mode (SQL Error 596)
+		// -> execute statement in implicit transaction
+	case s.cfg.PiggybackCommit():
+		return s.exec(pr, args, true)
+	}
 
-	if err := s.pw.write(s.sessionID, mtExecute, !s.inTx, statementID(pr.stmtID), newInputParameters(pr.prmFields, args)); err != nil {
+	// execute statement and commit via separate request
+	r, err := s.exec(pr, args, false)
+	if err != nil {
+		s.endTx(mtRollback) // keep exec error
+		return nil, err
+	}
+	if err := s.endTx(mtCommit); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// hasLobArgs returns true if at least one lob field argument is set.
+func hasLobArgs(prmFields []*parameterField, args []driver.NamedValue) bool {
+	if len(prmFields) == 0 {
+		return false
+	}
+	for i, arg := range args { // bulk: args contains multiple rows
+		if prmFields[i%len(prmFields)].tc.isLob() && arg.Value != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// exec executes a prepared statement. If commit is set (auto-commit mode), the commit flag of the
+// execute request is set, so that the database commits the statement without an additional
+// COMMIT request round-trip.
+func (s *Session) exec(pr *PrepareResult, args []driver.NamedValue, commit bool) (driver.Result, error) {
+	if err := s.pw.write(s.sessionID, mtExecute, commit, statementID(pr.stmtID), newInputParameters(pr.prmFields, args)); err != nil {
 		return nil, err
 	}
 
@@ -479,6 +878,7 @@ func (s *Session) Exec(pr *PrepareResult, args []driver.NamedValue) (driver.Resu
 	var ids []locatorID
 	lobReply := &writeLobReply{}
 	var numRow int64
+	rs := &unexpectedResultset{}
 
 	if err := s.pr.iterateParts(func(ph *partHeader) {
 		switch ph.partKind {
@@ -488,12 +888,18 @@ func (s *Session) Exec(pr *PrepareResult, args []driver.NamedValue) (driver.Resu
 		case pkWriteLobReply:
 			s.pr.read(lobReply)
 			ids = lobReply.ids
+		case pkResultsetID, pkResultset:
+			rs.handlePart(s.pr, ph)
 		}
 	}); err != nil {
 		return nil, err
 	}
 	fc := s.pr.functionCode()
 
+	if err := rs.close(s); err != nil {
+		return nil, err
+	}
+
 	if len(ids) != 0 {
 		/*
 			writeLobParameters:
@@ -508,13 +914,21 @@ func (s *Session) Exec(pr *PrepareResult, args []driver.NamedValue) (driver.Resu
 	if fc == fcDDL {
 		return driver.ResultNoRows, nil
 	}
-	return driver.RowsAffected(numRow), nil
+	return ExecResult(numRow), nil
 }
 
-// QueryCall executes a stored procecure (by Query).
-func (s *Session) QueryCall(pr *PrepareResult, args []driver.NamedValue) (driver.Rows, error) {
+/*
+QueryCall executes a stored procecure (by Query).
+
+The first result set consists of one row with the scalar output parameter values followed by the
+table output parameters (as sql.Rows or, in legacy mode, as result set id query). Besides (not in legacy mode)
+each table output parameter is provided as additional result set in order of the procedure parameter declaration,
+so that the tables can be read by sql.Rows.NextResultSet.
+*/
+func (s *Session) QueryCall(pr *PrepareResult, args []driver.NamedValue) (_ driver.Rows, err error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.trace(s.ctx, TraceQuery, pr.query, len(args))(&err)
 
 	/*
 		only in args
@@ -564,16 +978,43 @@ func (s *Session) QueryCall(pr *PrepareResult, args []driver.NamedValue) (driver
 			// add to cache
 			QrsCache.set(qr._rsID, newQueryResultSet(s, qr))
 		}
-	} else {
-		cr.appendTableRowsFields(s)
+		return newQueryResultSet(s, cr), nil
+	}
+	cr.appendTableRowsFields(s)
+	// table output parameters as additional result sets (see sql.Rows.NextResultSet)
+	rrs := make([]rowsResult, 0, 1+len(cr.qrs))
+	rrs = append(rrs, cr)
+	for _, qr := range cr.qrs {
+		rrs = append(rrs, qr)
 	}
-	return newQueryResultSet(s, cr), nil
+	return newQueryResultSet(s, rrs...), nil
 }
 
-// ExecCall executes a stored procecure (by Exec).
+/*
+ExecCall executes a stored procecure (by Exec).
+
+The scalar output parameter values are assigned to the output arguments (sql.Out) in the order
+of the procedure parameter declaration as reported by the database on statement preparation.
+As the arguments need to be provided for all parameters (in and out) in declaration order,
+the n-th sql.Out argument receives the value of the n-th output parameter of the procedure.
+In- and output parameters (INOUT) are provided as InOutValue.
+*/
 func (s *Session) ExecCall(pr *PrepareResult, args []driver.NamedValue) (driver.Result, error) {
+	cr, outArgs, err := s.execCall(pr, args)
+	if err != nil {
+		return nil, err
+	}
+	// assign output parameters after session is unlocked (lob output parameters are read via session)
+	if err := cr.assignOutArgs(s, outArgs); err != nil {
+		return nil, err
+	}
+	return driver.ResultNoRows, nil
+}
+
+func (s *Session) execCall(pr *PrepareResult, args []driver.NamedValue) (_ *callResult, _ []driver.NamedValue, err error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.trace(s.ctx, TraceExec, pr.query, len(args))(&err)
 
 	/*
 		in,- and output args
@@ -582,18 +1023,27 @@ func (s *Session) ExecCall(pr *PrepareResult, args []driver.NamedValue) (driver.
 	var inPrmFields, outPrmFields []*parameterField
 	var inArgs, outArgs []driver.NamedValue
 	for i, f := range pr.prmFields {
+		inOut, isInOut := args[i].Value.(InOutValue)
 		if f.In() {
 			inPrmFields = append(inPrmFields, f)
-			inArgs = append(inArgs, args[i])
+			arg := args[i]
+			if isInOut {
+				arg.Value = inOut.In
+			}
+			inArgs = append(inArgs, arg)
 		}
 		if f.Out() {
 			outPrmFields = append(outPrmFields, f)
-			outArgs = append(outArgs, args[i])
+			arg := args[i]
+			if isInOut {
+				arg.Value = inOut.Dest
+			}
+			outArgs = append(outArgs, arg)
 		}
 	}
 
 	if err := s.pw.write(s.sessionID, mtExecute, false, statementID(pr.stmtID), newInputParameters(inPrmFields, inArgs)); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	/*
@@ -605,7 +1055,7 @@ func (s *Session) ExecCall(pr *PrepareResult, args []driver.NamedValue) (driver.
 
 	cr, ids, err := s.readCall(outPrmFields)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if len(ids) != 0 {
@@ -615,13 +1065,10 @@ func (s *Session) ExecCall(pr *PrepareResult, args []driver.NamedValue) (driver.
 			- cr (callResult output parameters are set after all lob input parameters are written)
 		*/
 		if err := s.encodeLobs(cr, ids, inPrmFields, inArgs); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
-
-	// TODO release v1.0.0 - assign output parameters
-	return nil, fmt.Errorf("not implemented yet")
-	//return driver.ResultNoRows, nil
+	return cr, outArgs, nil
 }
 
 func (s *Session) readCall(outputFields []*parameterField) (*callResult, []locatorID, error) {
@@ -681,20 +1128,27 @@ func (s *Session) readCall(outputFields []*parameterField) (*callResult, []locat
 }
 
 // Query executes a query.
-func (s *Session) Query(pr *PrepareResult, args []driver.NamedValue) (driver.Rows, error) {
+func (s *Session) Query(pr *PrepareResult, args []driver.NamedValue) (_ driver.Rows, err error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.trace(s.ctx, TraceQuery, pr.query, len(args))(&err)
 
 	// allow e.g inserts as query -> handle commit like in exec
-	if err := s.pw.write(s.sessionID, mtExecute, !s.inTx, statementID(pr.stmtID), newInputParameters(pr.prmFields, args)); err != nil {
+	if err := s.pw.write(s.sessionID, mtExecute, !s.inTx, s.queryParts(statementID(pr.stmtID), newInputParameters(pr.prmFields, args))...); err != nil {
 		return nil, err
 	}
 
-	qr := &queryResult{fields: pr.resultFields}
-	resSet := &resultset{}
+	qr := &queryResult{fields: pr.resultFields, rawCESU8: s.rawCESU8, alphanumPadding: s.alphanumPadding}
+	meta := &resultMetadata{}
+	resSet := &resultset{rawCESU8: s.rawCESU8, alphanumPadding: s.alphanumPadding}
+	rows := &rowsAffected{}
+	var numRow int64
 
 	if err := s.pr.iterateParts(func(ph *partHeader) {
 		switch ph.partKind {
+		case pkResultMetadata: // result set of a statement without prepared result metadata (e.g. DML)
+			s.pr.read(meta)
+			qr.fields = meta.resultFields
 		case pkResultsetID:
 			s.pr.read((*resultsetID)(&qr._rsID))
 		case pkResultset:
@@ -702,30 +1156,53 @@ func (s *Session) Query(pr *PrepareResult, args []driver.NamedValue) (driver.Row
 			s.pr.read(resSet)
 			qr.fieldValues = resSet.fieldValues
 			qr.attributes = ph.partAttributes
+		case pkRowsAffected:
+			s.pr.read(rows)
+			numRow = rows.total()
 		}
 	}); err != nil {
 		return nil, err
 	}
 	if qr._rsID == 0 { // non select query
-		return noResult, nil
+		if numRow == 0 {
+			return noResult, nil
+		}
+		return &noResultType{rowsAffected: numRow}, nil
 	}
-	return newQueryResultSet(s, qr), nil
+	qrs := newQueryResultSet(s, qr)
+	qrs.rowsAffected = numRow
+	return qrs, nil
 }
 
-// FetchNext fetches next chunk in query result set.
-func (s *Session) fetchNext(rr rowsResult) error {
+// queryParts adds the fetch size part requesting the rows of the execution reply
+// in case a small result threshold is configured.
+func (s *Session) queryParts(parts ...partWriter) []partWriter {
+	if threshold := s.cfg.SmallResultThreshold(); threshold > 0 {
+		parts = append(parts, fetchsize(threshold))
+	}
+	return parts
+}
+
+// FetchNext fetches next chunk in query result set (fetchSize <= 0: session configuration fetch size).
+func (s *Session) fetchNext(ctx context.Context, rr rowsResult, fetchSize int) (err error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.trace(ctx, TraceFetch, "", 0)(&err)
+	atomic.AddUint64(&s.numFetch, 1)
+
+	if fetchSize <= 0 {
+		fetchSize = s.cfg.FetchSize()
+	}
 
 	qr, err := rr.queryResult()
 	if err != nil {
 		return err
 	}
-	if err := s.pw.write(s.sessionID, mtFetchNext, false, resultsetID(qr._rsID), fetchsize(s.cfg.FetchSize())); err != nil {
+	if err := s.pw.write(s.sessionID, mtFetchNext, false, resultsetID(qr._rsID), fetchsize(fetchSize)); err != nil {
 		return err
 	}
 
-	resSet := &resultset{}
+	resSet := &resultset{rawCESU8: qr.rawCESU8, alphanumPadding: qr.alphanumPadding}
 
 	return s.pr.iterateParts(func(ph *partHeader) {
 		if ph.partKind == pkResultset {
@@ -741,16 +1218,72 @@ func (s *Session) fetchNext(rr rowsResult) error {
 func (s *Session) DropStatementID(id uint64) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.dropStatementID(id)
+}
+
+func (s *Session) dropStatementID(id uint64) error {
 	if err := s.pw.write(s.sessionID, mtDropStatementID, false, statementID(id)); err != nil {
 		return err
 	}
 	return s.pr.readSkip()
 }
 
+/*
+CachedPrepareResult removes and returns the prepare result cached for key from the statement cache
+(see SessionConfig.StmtCacheSize), so that a statement prepared before can be reused without a
+database round trip. The cache is discarded if the session is bad, so that statement handles of a
+broken connection are never reused.
+*/
+func (s *Session) CachedPrepareResult(key string) (*PrepareResult, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.IsBad() || atomic.LoadInt32(&s.closed) != 0 {
+		s.stmtCache.clear()
+		return nil, false
+	}
+	return s.stmtCache.get(key)
+}
+
+/*
+CachePrepareResult adds the prepare result of a statement, which is not used anymore, to the statement cache.
+The handles of the least recently used statements exceeding the cache size are dropped (with a cache size of 0
+the handle of the statement is dropped immediately).
+*/
+func (s *Session) CachePrepareResult(key string, pr *PrepareResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.IsBad() {
+		s.stmtCache.clear()
+		return s.dropStatementID(pr.stmtID)
+	}
+	for _, pr := range s.stmtCache.put(key, pr) {
+		if err := s.dropStatementID(pr.stmtID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClearStmtCache drops the handles of all statements of the statement cache.
+func (s *Session) ClearStmtCache() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, pr := range s.stmtCache.clear() {
+		if err := s.dropStatementID(pr.stmtID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // CloseResultsetID releases the hdb resultset handle.
 func (s *Session) CloseResultsetID(id uint64) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.closeResultsetID(id)
+}
+
+func (s *Session) closeResultsetID(id uint64) error {
 	if err := s.pw.write(s.sessionID, mtCloseResultset, false, resultsetID(id)); err != nil {
 		return err
 	}
@@ -761,10 +1294,7 @@ func (s *Session) CloseResultsetID(id uint64) error {
 func (s *Session) Commit() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if err := s.pw.write(s.sessionID, mtCommit, false); err != nil {
-		return err
-	}
-	if err := s.pr.readSkip(); err != nil {
+	if err := s.endTx(mtCommit); err != nil {
 		return err
 	}
 	s.inTx = false
@@ -775,16 +1305,33 @@ func (s *Session) Commit() error {
 func (s *Session) Rollback() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if err := s.pw.write(s.sessionID, mtRollback, false); err != nil {
-		return err
-	}
-	if err := s.pr.readSkip(); err != nil {
+	if err := s.endTx(mtRollback); err != nil {
 		return err
 	}
 	s.inTx = false
 	return nil
 }
 
+/*
+RollbackOpenTx rolls back a transaction which is still open when the session is reset
+(e.g. a transaction leaked by a missing commit or rollback) and logs a warning.
+*/
+func (s *Session) RollbackOpenTx() error {
+	if !s.inTx {
+		return nil
+	}
+	plog.Printf("warning: rollback of open transaction on session reset (session id %d)", s.sessionID)
+	return s.Rollback()
+}
+
+// endTx ends a transaction (mt: mtCommit or mtRollback).
+func (s *Session) endTx(mt messageType) error {
+	if err := s.pw.write(s.sessionID, mt, false); err != nil {
+		return err
+	}
+	return s.pr.readSkip()
+}
+
 // decodeLobs decodes (reads from db) output lob or result lob parameters.
 
 // read lob reply
@@ -795,24 +1342,9 @@ func (s *Session) decodeLobs(descr *lobOutDescr, wr io.Writer) error {
 
 	if descr.isCharBased {
 		wrcl := transform.NewWriter(wr, unicode.Cesu8ToUtf8Transformer) // CESU8 transformer
-		err = s._decodeLobs(descr, wrcl, func(b []byte) (int64, error) {
-			// Caution: hdb counts 4 byte utf-8 encodings (cesu-8 6 bytes) as 2 (3 byte) chars
-			numChars := int64(0)
-			for len(b) > 0 {
-				if !cesu8.FullRune(b) { //
-					return 0, fmt.Errorf("lob chunk consists of incomplete CESU-8 runes")
-				}
-				_, size := cesu8.DecodeRune(b)
-				b = b[size:]
-				numChars++
-				if size == cesu8.CESUMax {
-					numChars++
-				}
-			}
-			return numChars, nil
-		})
+		err = s._decodeLobs(descr, wrcl, countLobChars)
 	} else {
-		err = s._decodeLobs(descr, wr, func(b []byte) (int64, error) { return int64(len(b)), nil })
+		err = s._decodeLobs(descr, wr, countLobBytes)
 	}
 
 	if pw, ok := wr.(*io.PipeWriter); ok { // if the writer is a pipe-end -> close at the end
@@ -825,17 +1357,61 @@ func (s *Session) decodeLobs(descr *lobOutDescr, wr io.Writer) error {
 	return err
 }
 
-func (s *Session) _decodeLobs(descr *lobOutDescr, wr io.Writer, countChars func(b []byte) (int64, error)) error {
+// countLobChars returns the number of characters of a CESU-8 encoded lob chunk.
+func countLobChars(b []byte) (int64, error) {
+	// Caution: hdb counts 4 byte utf-8 encodings (cesu-8 6 bytes) as 2 (3 byte) chars
+	numChars := int64(0)
+	for len(b) > 0 {
+		if !cesu8.FullRune(b) { //
+			return 0, fmt.Errorf("lob chunk consists of incomplete CESU-8 runes")
+		}
+		_, size := cesu8.DecodeRune(b)
+		b = b[size:]
+		numChars++
+		if size == cesu8.CESUMax {
+			numChars++
+		}
+	}
+	return numChars, nil
+}
+
+// countLobBytes returns the number of bytes of a binary lob chunk.
+func countLobBytes(b []byte) (int64, error) { return int64(len(b)), nil }
+
+// lobChunkSize returns the size of the next chunk to be read of a lob with numChar characters at offset ofs.
+func (s *Session) lobChunkSize(numChar, ofs int64) int32 {
 	lobChunkSize := int64(s.cfg.LobChunkSize())
+	chunkSize := numChar - ofs
+	if chunkSize > lobChunkSize {
+		return int32(lobChunkSize)
+	}
+	return int32(chunkSize)
+}
+
+// readLobChunk reads the lob chunk requested by lobRequest into lobReply.
+func (s *Session) readLobChunk(ctx context.Context, lobRequest *readLobRequest, lobReply *readLobReply) (err error) {
+	defer s.trace(ctx, TraceReadLob, "", 0)(&err)
+	atomic.AddUint64(&s.numLobChunk, 1)
 
-	chunkSize := func(numChar, ofs int64) int32 {
-		chunkSize := numChar - ofs
-		if chunkSize > lobChunkSize {
-			return int32(lobChunkSize)
+	if err := s.pw.write(s.sessionID, mtWriteLob, false, lobRequest); err != nil {
+		return err
+	}
+
+	if err := s.pr.iterateParts(func(ph *partHeader) {
+		if ph.partKind == pkReadLobReply {
+			s.pr.read(lobReply)
 		}
-		return int32(chunkSize)
+	}); err != nil {
+		return err
 	}
 
+	if lobReply.id != lobRequest.id {
+		return fmt.Errorf("internal error: invalid lob locator %d - expected %d", lobReply.id, lobRequest.id)
+	}
+	return nil
+}
+
+func (s *Session) _decodeLobs(descr *lobOutDescr, wr io.Writer, countChars func(b []byte) (int64, error)) error {
 	if _, err := wr.Write(descr.b); err != nil {
 		return err
 	}
@@ -855,24 +1431,12 @@ func (s *Session) _decodeLobs(descr *lobOutDescr, wr io.Writer, countChars func(
 	for !eof {
 
 		lobRequest.ofs += ofs
-		lobRequest.chunkSize = chunkSize(descr.numChar, ofs)
+		lobRequest.chunkSize = s.lobChunkSize(descr.numChar, ofs)
 
-		if err := s.pw.write(s.sessionID, mtWriteLob, false, lobRequest); err != nil {
+		if err := s.readLobChunk(descr.context(), lobRequest, lobReply); err != nil {
 			return err
 		}
 
-		if err := s.pr.iterateParts(func(ph *partHeader) {
-			if ph.partKind == pkReadLobReply {
-				s.pr.read(lobReply)
-			}
-		}); err != nil {
-			return err
-		}
-
-		if lobReply.id != lobRequest.id {
-			return fmt.Errorf("internal error: invalid lob locator %d - expected %d", lobReply.id, lobRequest.id)
-		}
-
 		if _, err := wr.Write(lobReply.b); err != nil {
 			return err
 		}
@@ -887,7 +1451,8 @@ func (s *Session) _decodeLobs(descr *lobOutDescr, wr io.Writer, countChars func(
 }
 
 // encodeLobs encodes (write to db) input lob parameters.
-func (s *Session) encodeLobs(cr *callResult, ids []locatorID, inPrmFields []*parameterField, args []driver.NamedValue) error {
+func (s *Session) encodeLobs(cr *callResult, ids []locatorID, inPrmFields []*parameterField, args []driver.NamedValue) (err error) {
+	defer s.trace(s.ctx, TraceWriteLob, "", len(ids))(&err)
 
 	chunkSize := int(s.cfg.LobChunkSize())
 
@@ -943,6 +1508,7 @@ func (s *Session) encodeLobs(cr *callResult, ids []locatorID, inPrmFields []*par
 
 		writeLobRequest.descrs = descrs
 
+		atomic.AddUint64(&s.numLobChunk, 1)
 		if err := s.pw.write(s.sessionID, mtReadLob, false, writeLobRequest); err != nil {
 			return err
 		}
@@ -0,0 +1,72 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+)
+
+func TestTLSServerName(t *testing.T) {
+	cert, key, err := CreateTestCert("server", false, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{cert.Raw}, PrivateKey: key}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(cert)
+
+	handshake := func(tlsConfig *tls.Config) error {
+		c, err := newDbConn(context.Background(), ln.Addr().String(), 0, tlsConfig, nil, nil)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+		return c.conn.(*tls.Conn).Handshake()
+	}
+
+	// server name of dialed host
+	tlsConfig := &tls.Config{RootCAs: rootCAs}
+	if err := handshake(tlsConfig); err != nil {
+		t.Fatal(err)
+	}
+	if tlsConfig.ServerName != "" {
+		t.Fatalf("server name %s - expected TLS configuration not to be changed", tlsConfig.ServerName)
+	}
+	// explicit server name not matching the certificate
+	if err := handshake(&tls.Config{RootCAs: rootCAs, ServerName: "localhost"}); err == nil {
+		t.Fatal("certificate verification error expected")
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"container/list"
+	"sync/atomic"
+)
+
+type stmtCacheEntry struct {
+	key string
+	pr  *PrepareResult
+}
+
+/*
+stmtCache is a least recently used cache of the prepared statements of a session keyed by the
+statement query:
+- a cached statement is removed from the cache while in use, so that a statement handle is
+  never used by more than one statement at a time
+- the same query might be cached more than once (statements of the same query closed after
+  concurrent usage)
+*/
+type stmtCache struct {
+	size    int
+	entries *list.List // front: most recently used
+	keys    map[string][]*list.Element
+	numStmt int32 // number of cached statements (atomic access, readable without session lock)
+}
+
+func newStmtCache(size int) *stmtCache {
+	return &stmtCache{size: size, entries: list.New(), keys: map[string][]*list.Element{}}
+}
+
+// get removes and returns the most recently cached prepare result of key.
+func (c *stmtCache) get(key string) (*PrepareResult, bool) {
+	elems := c.keys[key]
+	if len(elems) == 0 {
+		return nil, false
+	}
+	e := elems[len(elems)-1]
+	c.remove(e)
+	c.updateNumStmt()
+	return e.Value.(*stmtCacheEntry).pr, true
+}
+
+// put adds the prepare result of key and returns the prepare results evicted from the cache.
+func (c *stmtCache) put(key string, pr *PrepareResult) []*PrepareResult {
+	c.keys[key] = append(c.keys[key], c.entries.PushFront(&stmtCacheEntry{key: key, pr: pr}))
+	var evicted []*PrepareResult
+	for c.entries.Len() > c.size {
+		e := c.entries.Back()
+		c.remove(e)
+		evicted = append(evicted, e.Value.(*stmtCacheEntry).pr)
+	}
+	c.updateNumStmt()
+	return evicted
+}
+
+// clear removes and returns all cached prepare results.
+func (c *stmtCache) clear() []*PrepareResult {
+	prs := make([]*PrepareResult, 0, c.entries.Len())
+	for e := c.entries.Front(); e != nil; e = e.Next() {
+		prs = append(prs, e.Value.(*stmtCacheEntry).pr)
+	}
+	c.entries.Init()
+	c.keys = map[string][]*list.Element{}
+	c.updateNumStmt()
+	return prs
+}
+
+func (c *stmtCache) updateNumStmt() { atomic.StoreInt32(&c.numStmt, int32(c.entries.Len())) }
+
+// len returns the number of cached statements.
+func (c *stmtCache) len() int { return int(atomic.LoadInt32(&c.numStmt)) }
+
+func (c *stmtCache) remove(e *list.Element) {
+	key := e.Value.(*stmtCacheEntry).key
+	elems := c.keys[key]
+	for i, elem := range elems {
+		if elem == e {
+			elems = append(elems[:i], elems[i+1:]...)
+			break
+		}
+	}
+	if len(elems) == 0 {
+		delete(c.keys, key)
+	} else {
+		c.keys[key] = elems
+	}
+	c.entries.Remove(e)
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"testing"
+)
+
+func TestStmtCache(t *testing.T) {
+	c := newStmtCache(2)
+
+	pr1, pr2, pr3, pr4 := &PrepareResult{stmtID: 1}, &PrepareResult{stmtID: 2}, &PrepareResult{stmtID: 3}, &PrepareResult{stmtID: 4}
+
+	if evicted := c.put("a", pr1); len(evicted) != 0 {
+		t.Fatalf("evicted %v - expected none", evicted)
+	}
+	if evicted := c.put("b", pr2); len(evicted) != 0 {
+		t.Fatalf("evicted %v - expected none", evicted)
+	}
+	// use a: b is least recently used
+	if pr, ok := c.get("a"); !ok || pr != pr1 {
+		t.Fatalf("prepare result %v %t - expected %v", pr, ok, pr1)
+	}
+	if _, ok := c.get("a"); ok {
+		t.Fatal("statement in use must not be cached")
+	}
+	c.put("a", pr1)
+	if evicted := c.put("c", pr3); len(evicted) != 1 || evicted[0] != pr2 {
+		t.Fatalf("evicted %v - expected %v", evicted, pr2)
+	}
+	if _, ok := c.get("b"); ok {
+		t.Fatal("evicted statement must not be cached")
+	}
+	// same key cached twice
+	if evicted := c.put("c", pr4); len(evicted) != 1 || evicted[0] != pr1 {
+		t.Fatalf("evicted %v - expected %v", evicted, pr1)
+	}
+	if pr, ok := c.get("c"); !ok || pr != pr4 {
+		t.Fatalf("prepare result %v %t - expected %v", pr, ok, pr4)
+	}
+	if prs := c.clear(); len(prs) != 1 || prs[0] != pr3 {
+		t.Fatalf("cleared %v - expected %v", prs, pr3)
+	}
+
+	// cache size 0: statements are evicted immediately
+	c = newStmtCache(0)
+	if evicted := c.put("a", pr1); len(evicted) != 1 || evicted[0] != pr1 {
+		t.Fatalf("evicted %v - expected %v", evicted, pr1)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2020 SAP SE
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protocol
+
+import (
+	"context"
+	"time"
+)
+
+// Traced operations (see TraceInfo).
+const (
+	TracePrepare  = "prepare"  // statement preparation
+	TraceQuery    = "query"    // query execution
+	TraceExec     = "exec"     // statement execution
+	TraceFetch    = "fetch"    // fetch of result set rows
+	TraceReadLob  = "readLob"  // read of a lob chunk
+	TraceWriteLob = "writeLob" // write of the lob arguments of a statement
+)
+
+// TraceInfo describes a traced database operation.
+type TraceInfo struct {
+	Op           string        // Traced operation (TracePrepare, TraceQuery, ...).
+	Query        string        // SQL statement (empty for fetch and lob operations).
+	NumArg       int           // Number of arguments (including all rows of bulk statements, number of lobs of lob writes).
+	BytesWritten uint64        // Number of bytes sent to the database.
+	BytesRead    uint64        // Number of bytes read from the database.
+	Duration     time.Duration // Elapsed time of the operation.
+	Err          error         // Error of the operation (nil if no error occurred).
+}
+
+// TraceFunc is called for each traced database operation with the context of the statement.
+type TraceFunc func(ctx context.Context, info TraceInfo)
+
+func nopTrace(err *error) {}
+
+/*
+trace returns the function reporting an operation started by now to the session tracer.
+The returned function is meant to be deferred with the address of the named error result
+of the operation. Without tracer a no-op function is returned.
+*/
+func (s *Session) trace(ctx context.Context, op, query string, numArg int) func(err *error) {
+	if s.tracer == nil {
+		return nopTrace
+	}
+	start := time.Now()
+	bytesWritten, bytesRead := s.BytesWritten(), s.BytesRead()
+	return func(err *error) {
+		s.tracer(ctx, TraceInfo{
+			Op:           op,
+			Query:        query,
+			NumArg:       numArg,
+			BytesWritten: s.BytesWritten() - bytesWritten,
+			BytesRead:    s.BytesRead() - bytesRead,
+			Duration:     time.Since(start),
+			Err:          *err,
+		})
+	}
+}
+
+/*
+SetContext sets the context passed to the session tracer for the operations of a statement and
+returns the previous context. Fetch and lob operations of result sets are traced with the context
+set on the execution of the query.
+*/
+func (s *Session) SetContext(ctx context.Context) context.Context {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev := s.ctx
+	s.ctx = ctx
+	return prev
+}
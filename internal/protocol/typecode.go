@@ -51,8 +51,8 @@ const (
 	tcTimeltz           typeCode = 0x12
 	tcTimestampTz       typeCode = 0x13
 	tcTimestampLtz      typeCode = 0x14
-	tcIntervalYm        typeCode = 0x15
-	tcIntervalDs        typeCode = 0x16
+	tcIntervalYm        typeCode = 0x15 // reserved: HANA SQL has no interval data type - not sent by the database
+	tcIntervalDs        typeCode = 0x16 // reserved: HANA SQL has no interval data type - not sent by the database
 	tcRowid             typeCode = 0x17
 	tcUrowid            typeCode = 0x18
 	tcClob              typeCode = 0x19
@@ -99,7 +99,16 @@ const (
 )
 
 func (tc typeCode) isLob() bool {
-	return tc == tcClob || tc == tcNclob || tc == tcBlob || tc == tcText || tc == tcBintext || tc == tcLocator
+	return tc == tcClob || tc == tcNclob || tc == tcBlob || tc == tcText || tc == tcBintext || tc == tcLocator || tc.isSpatial()
+}
+
+func (tc typeCode) isSpatial() bool {
+	return tc == tcStGeometry || tc == tcStPoint
+}
+
+// isWallClock returns true for date / time types representing a wall clock value rather than an instant.
+func (tc typeCode) isWallClock() bool {
+	return tc == tcDate || tc == tcTime || tc == tcDaydate || tc == tcSecondtime
 }
 
 func (tc typeCode) isCharBased() bool {
@@ -115,7 +124,11 @@ func (tc typeCode) isIntegerType() bool {
 }
 
 func (tc typeCode) isDecimalType() bool {
-	return tc == tcSmalldecimal || tc == tcDecimal
+	return tc == tcSmalldecimal || tc == tcDecimal || tc.isFixedType()
+}
+
+func (tc typeCode) isFixedType() bool {
+	return tc == tcFixed8 || tc == tcFixed12 || tc == tcFixed16
 }
 
 // see hdbclient
@@ -135,37 +148,45 @@ tcBintext:
 */
 
 var dataTypeMap = map[typeCode]DataType{
-	tcTinyint:    DtTinyint,
-	tcSmallint:   DtSmallint,
-	tcInteger:    DtInteger,
-	tcBigint:     DtBigint,
-	tcReal:       DtReal,
-	tcDouble:     DtDouble,
-	tcDate:       DtTime,
-	tcTime:       DtTime,
-	tcTimestamp:  DtTime,
-	tcLongdate:   DtTime,
-	tcSeconddate: DtTime,
-	tcDaydate:    DtTime,
-	tcSecondtime: DtTime,
-	tcDecimal:    DtDecimal,
-	tcChar:       DtString,
-	tcVarchar:    DtString,
-	tcString:     DtString,
-	tcAlphanum:   DtString,
-	tcNchar:      DtString,
-	tcNvarchar:   DtString,
-	tcNstring:    DtString,
-	tcShorttext:  DtString,
-	tcBinary:     DtBytes,
-	tcVarbinary:  DtBytes,
-	tcBlob:       DtLob,
-	tcClob:       DtLob,
-	tcNclob:      DtLob,
-	tcText:       DtLob,
-	tcBintext:    DtLob,
-	tcTableRef:   DtString,
-	tcTableRows:  DtRows,
+	tcTinyint:           DtTinyint,
+	tcSmallint:          DtSmallint,
+	tcInteger:           DtInteger,
+	tcBigint:            DtBigint,
+	tcReal:              DtReal,
+	tcDouble:            DtDouble,
+	tcDate:              DtTime,
+	tcTime:              DtTime,
+	tcTimestamp:         DtTime,
+	tcLongdate:          DtTime,
+	tcSeconddate:        DtTime,
+	tcDaydate:           DtTime,
+	tcSecondtime:        DtTime,
+	tcDecimal:           DtDecimal,
+	tcDecimalDigitArray: DtDecimalArray,
+	tcChar:              DtString,
+	tcVarchar:           DtString,
+	tcString:            DtString,
+	tcAlphanum:          DtString,
+	tcNchar:             DtString,
+	tcNvarchar:          DtString,
+	tcNstring:           DtString,
+	tcShorttext:         DtString,
+	tcBinary:            DtBytes,
+	tcVarbinary:         DtBytes,
+	tcRowid:             DtBytes,
+	tcUrowid:            DtBytes,
+	tcBlob:              DtLob,
+	tcClob:              DtLob,
+	tcNclob:             DtLob,
+	tcText:              DtLob,
+	tcBintext:           DtLob,
+	tcStGeometry:        DtLob,
+	tcStPoint:           DtSTPoint,
+	tcFixed8:            DtDecimal,
+	tcFixed12:           DtDecimal,
+	tcFixed16:           DtDecimal,
+	tcTableRef:          DtString,
+	tcTableRows:         DtRows,
 }
 
 // DataType converts a type code into one of the supported data types by the driver.
@@ -184,36 +205,44 @@ func (tc typeCode) typeName() string {
 }
 
 var tcFieldTypeMap = map[typeCode]fieldType{
-	tcTinyint:    tinyintType,
-	tcSmallint:   smallintType,
-	tcInteger:    integerType,
-	tcBigint:     bigintType,
-	tcReal:       realType,
-	tcDouble:     doubleType,
-	tcDate:       dateType,
-	tcTime:       timeType,
-	tcTimestamp:  timestampType,
-	tcLongdate:   longdateType,
-	tcSeconddate: seconddateType,
-	tcDaydate:    daydateType,
-	tcSecondtime: secondtimeType,
-	tcDecimal:    decimalType,
-	tcChar:       varType,
-	tcVarchar:    varType,
-	tcString:     varType,
-	tcAlphanum:   alphaType,
-	tcNchar:      cesu8Type,
-	tcNvarchar:   cesu8Type,
-	tcNstring:    cesu8Type,
-	tcShorttext:  cesu8Type,
-	tcBinary:     varType,
-	tcVarbinary:  varType,
-	tcBlob:       lobVarType,
-	tcClob:       lobVarType,
-	tcNclob:      lobCESU8Type,
-	tcText:       lobCESU8Type,
-	tcBintext:    lobCESU8Type,
-	tcLocator:    lobCESU8Type,
+	tcTinyint:           tinyintType,
+	tcSmallint:          smallintType,
+	tcInteger:           integerType,
+	tcBigint:            bigintType,
+	tcReal:              realType,
+	tcDouble:            doubleType,
+	tcDate:              dateType,
+	tcTime:              timeType,
+	tcTimestamp:         timestampType,
+	tcLongdate:          longdateType,
+	tcSeconddate:        seconddateType,
+	tcDaydate:           daydateType,
+	tcSecondtime:        secondtimeType,
+	tcDecimal:           decimalType,
+	tcDecimalDigitArray: decArrayType,
+	tcChar:              varType,
+	tcVarchar:           varType,
+	tcString:            varType,
+	tcAlphanum:          alphaType,
+	tcNchar:             cesu8Type,
+	tcNvarchar:          cesu8Type,
+	tcNstring:           cesu8Type,
+	tcShorttext:         cesu8Type,
+	tcBinary:            varType,
+	tcVarbinary:         varType,
+	tcRowid:             varType, // row ids are transferred as variable length binary values
+	tcUrowid:            varType,
+	tcBlob:              lobVarType,
+	tcClob:              lobVarType,
+	tcNclob:             lobCESU8Type,
+	tcText:              lobCESU8Type,
+	tcBintext:           lobCESU8Type,
+	tcLocator:           lobCESU8Type,
+	tcStGeometry:        lobVarType, // spatial types are transferred as binary lobs (WKB)
+	tcStPoint:           lobVarType,
+	tcFixed8:            fixed8Type, // fixed decimal results are handed to the driver as *big.Rat
+	tcFixed12:           fixed12Type,
+	tcFixed16:           fixed16Type,
 }
 
 func (tc typeCode) fieldType() fieldType {